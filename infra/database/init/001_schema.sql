@@ -17,8 +17,13 @@ CREATE TABLE IF NOT EXISTS daily_bars (
     close NUMERIC(12, 4) NOT NULL,
     volume BIGINT NOT NULL,
     vwap NUMERIC(12, 4),
+    prev_close NUMERIC(12, 4),
     change NUMERIC(12, 4),
     change_percent NUMERIC(8, 4),
+    change_basis VARCHAR(16),     -- prev_close, open, none
+    avg_volume_20 BIGINT,         -- prior 20 sessions
+    avg_volume_50 BIGINT,         -- prior 50 sessions
+    volume_ratio NUMERIC(10, 4),  -- volume / avg_volume_20
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 
@@ -32,6 +37,69 @@ CREATE INDEX idx_daily_bars_date ON daily_bars (date DESC);
 CREATE INDEX idx_daily_bars_gainers ON daily_bars (date DESC, change_percent DESC NULLS LAST) WHERE change_percent > 0;
 CREATE INDEX idx_daily_bars_losers ON daily_bars (date DESC, change_percent ASC NULLS LAST) WHERE change_percent < 0;
 CREATE INDEX idx_daily_bars_volume ON daily_bars (date DESC, volume DESC);
+CREATE INDEX idx_daily_bars_volume_ratio ON daily_bars (date DESC, volume_ratio DESC NULLS LAST) WHERE volume_ratio IS NOT NULL;
+
+-- Intraday minute bars; 5m/15m/1h views are rolled up at query time
+CREATE TABLE IF NOT EXISTS intraday_bars (
+    symbol VARCHAR(10) NOT NULL,
+    ts TIMESTAMPTZ NOT NULL,          -- bar start
+    open NUMERIC(12, 4) NOT NULL,
+    high NUMERIC(12, 4) NOT NULL,
+    low NUMERIC(12, 4) NOT NULL,
+    close NUMERIC(12, 4) NOT NULL,
+    volume BIGINT NOT NULL,
+    vwap NUMERIC(12, 4),
+    transactions INTEGER,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (symbol, ts)
+);
+
+-- Hypertable when TimescaleDB is installed
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb') THEN
+        PERFORM create_hypertable('intraday_bars', 'ts', chunk_time_interval => INTERVAL '1 day', if_not_exists => TRUE);
+    END IF;
+END
+$$;
+
+-- Splits and cash dividends; price_factor multiplies prices before ex_date
+-- (NULL until the close before ex_date is stored)
+CREATE TABLE IF NOT EXISTS corporate_actions (
+    id VARCHAR(128) PRIMARY KEY,      -- Polygon reference ID
+    symbol VARCHAR(10) NOT NULL,
+    action_type VARCHAR(16) NOT NULL CHECK (action_type IN ('split', 'dividend')),
+    ex_date DATE NOT NULL,
+    split_from NUMERIC(16, 6),
+    split_to NUMERIC(16, 6),
+    cash_amount NUMERIC(16, 6),
+    currency VARCHAR(8),
+    pay_date DATE,
+    dividend_type VARCHAR(8),         -- CD, SC, LT, ST
+    price_factor NUMERIC(20, 12),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_corporate_actions_symbol ON corporate_actions (symbol, ex_date);
+
+-- Ticker reference data (names, security types)
+CREATE TABLE IF NOT EXISTS tickers (
+    symbol VARCHAR(16) PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    type VARCHAR(16),                 -- CS, ETF, ADRC, WARRANT, ...
+    primary_exchange VARCHAR(16),
+    cik VARCHAR(16),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    currency VARCHAR(8),
+    sector VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_tickers_type ON tickers (type) WHERE active;
+CREATE INDEX idx_tickers_sector ON tickers (sector) WHERE active AND sector IS NOT NULL;
 
 -- Market indices (SPY, QQQ, DIA, IWM)
 CREATE TABLE IF NOT EXISTS market_indices (
@@ -142,14 +210,46 @@ CREATE TABLE IF NOT EXISTS watchlist_items (
 CREATE INDEX idx_watchlist_items_ticker ON watchlist_items (ticker);
 CREATE INDEX idx_watchlist_items_watchlist ON watchlist_items (watchlist_id);
 
+-- =====================================================
+-- SAVED SCREENS
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS saved_screens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    filter TEXT NOT NULL DEFAULT '',  -- screen expression, e.g. close > sma200 AND rsi14 < 30
+    sort TEXT NOT NULL DEFAULT '',
+    sort_order VARCHAR(4) NOT NULL DEFAULT '',  -- asc, desc
+    result_limit INTEGER NOT NULL DEFAULT 0 CHECK (result_limit >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT saved_screens_name_unique UNIQUE (name)
+);
+
+-- Daily matches per saved screen, diffed against the previous evaluation
+CREATE TABLE IF NOT EXISTS screen_results (
+    screen_id UUID NOT NULL REFERENCES saved_screens(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    previous_date DATE,
+    symbols TEXT[] NOT NULL DEFAULT '{}',
+    entered TEXT[] NOT NULL DEFAULT '{}',
+    exited TEXT[] NOT NULL DEFAULT '{}',
+    evaluated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (screen_id, date)
+);
+
 -- =====================================================
 -- SIGNALS (Unified for all tools)
 -- =====================================================
 
 CREATE TABLE IF NOT EXISTS signals (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    source VARCHAR(50) NOT NULL,  -- options_flow, sector, strength, news
+    source VARCHAR(50) NOT NULL,  -- options_flow, news, gap, breakout, unusual_volume, new_high, ...
     timestamp TIMESTAMPTZ NOT NULL,
+    session_date DATE NOT NULL,   -- New York trading date of timestamp
     ticker VARCHAR(10),
     direction VARCHAR(20),        -- bullish, bearish, neutral
     strength INTEGER CHECK (strength BETWEEN 1 AND 4),
@@ -162,6 +262,8 @@ CREATE TABLE IF NOT EXISTS signals (
 CREATE INDEX idx_signals_source_time ON signals (source, timestamp DESC);
 CREATE INDEX idx_signals_ticker ON signals (ticker, timestamp DESC);
 CREATE INDEX idx_signals_timestamp ON signals (timestamp DESC);
+-- One signal per source, ticker and session
+CREATE UNIQUE INDEX idx_signals_dedup ON signals (source, COALESCE(ticker, ''), session_date);
 
 -- =====================================================
 -- NEWS & SENTIMENT
@@ -206,6 +308,7 @@ CREATE TABLE IF NOT EXISTS article_sentiment (
 
 CREATE TABLE IF NOT EXISTS options_flow (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    alert_id VARCHAR(64),             -- Unusual Whales alert ID
     timestamp TIMESTAMPTZ NOT NULL,
     ticker VARCHAR(10) NOT NULL,
     expiration DATE NOT NULL,
@@ -225,6 +328,8 @@ CREATE TABLE IF NOT EXISTS options_flow (
 CREATE INDEX idx_options_flow_timestamp ON options_flow (timestamp DESC);
 CREATE INDEX idx_options_flow_ticker ON options_flow (ticker, timestamp DESC);
 CREATE INDEX idx_options_flow_premium ON options_flow (timestamp DESC, premium DESC);
+-- Repeated polls store an alert once
+CREATE UNIQUE INDEX idx_options_flow_alert ON options_flow (alert_id);
 
 -- =====================================================
 -- UTILITY FUNCTIONS
@@ -285,9 +390,14 @@ ON CONFLICT (symbol, date) DO NOTHING;
 -- =====================================================
 
 COMMENT ON TABLE daily_bars IS 'OHLCV time-series data for all stocks from Polygon.io';
+COMMENT ON TABLE tickers IS 'Ticker reference data from Polygon (name, type, exchange)';
 COMMENT ON TABLE market_indices IS 'Major market index ETFs (SPY, QQQ, DIA, IWM)';
 COMMENT ON TABLE sector_data IS 'SPDR sector ETF data with relative strength metrics';
+COMMENT ON COLUMN sector_data.relative_strength IS 'Percent change minus SPY percent change, in percentage points';
+COMMENT ON TABLE sector_rotation IS 'Daily RISK_ON/RISK_OFF/NEUTRAL signal from leading and lagging sectors';
 COMMENT ON TABLE strength_scores IS 'Daily composite strength scores for ticker ranking';
+COMMENT ON COLUMN strength_scores.rs_vs_spy IS '20-session percent change minus SPY''s, in percentage points';
+COMMENT ON COLUMN strength_scores.volume_trend IS 'Average volume over 5 sessions relative to 20';
 COMMENT ON TABLE watchlists IS 'User-created watchlists for tracking specific tickers';
 COMMENT ON TABLE signals IS 'Unified signals from all analysis tools';
 COMMENT ON TABLE articles IS 'News articles from RSS feeds';
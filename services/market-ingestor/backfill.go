@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/config"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/scheduler"
+)
+
+// runBackfill implements `market-ingestor backfill -from YYYY-MM-DD [-to YYYY-MM-DD] [-intraday] [-actions]`
+func runBackfill(cfg *config.Config, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	fromFlag := fs.String("from", "", "first date to backfill (YYYY-MM-DD, required)")
+	// Today has no bars until the EOD ingest, so default to the last complete session
+	lastSession := calendar.PreviousTradingDay(time.Now().In(calendar.Location))
+	toFlag := fs.String("to", lastSession.Format("2006-01-02"), "last date to backfill (YYYY-MM-DD)")
+	concurrency := fs.Int("concurrency", cfg.BackfillConcurrency, fmt.Sprintf("concurrent grouped daily fetches (at most %d)", scheduler.MaxBackfillConcurrency))
+	force := fs.Bool("force", false, "re-ingest dates that already have stored bars")
+	intraday := fs.Bool("intraday", false, "backfill minute bars for INTRADAY_SYMBOLS instead of daily bars")
+	actions := fs.Bool("actions", false, "backfill splits and dividends instead of daily bars")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *fromFlag == "" {
+		return errors.New("-from is required")
+	}
+	from, err := time.Parse("2006-01-02", *fromFlag)
+	if err != nil {
+		return fmt.Errorf("parsing -from: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", *toFlag)
+	if err != nil {
+		return fmt.Errorf("parsing -to: %w", err)
+	}
+
+	if cfg.DatabaseURL == "" && cfg.MemorySnapshotPath == "" {
+		logger.Warn("backfilling into the in-memory store; results are discarded on exit")
+	}
+	dataStore := openStore(cfg, logger)
+	defer dataStore.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	sched, err := newScheduler(cfg, dataStore, nil, nil, logger)
+	if err != nil {
+		return err
+	}
+
+	if *intraday {
+		if len(cfg.IntradaySymbols) == 0 {
+			return errors.New("-intraday requires INTRADAY_SYMBOLS")
+		}
+		return sched.BackfillIntraday(ctx, cfg.IntradaySymbols, from, to)
+	}
+
+	if *actions {
+		return sched.BackfillCorporateActions(ctx, from, to)
+	}
+
+	progress, err := sched.Backfill(ctx, scheduler.BackfillRequest{
+		From:        from,
+		To:          to,
+		Concurrency: *concurrency,
+		Force:       *force,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "backfill %s..%s: %d trading days, %d skipped, %d completed, %d failed\n",
+		*fromFlag, *toFlag, progress.TradingDays, progress.Skipped, progress.Completed, progress.Failed)
+	for _, d := range progress.FailedDates {
+		fmt.Fprintf(os.Stderr, "  failed: %s\n", d)
+	}
+	return nil
+}
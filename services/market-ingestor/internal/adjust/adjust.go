@@ -0,0 +1,151 @@
+// Package adjust rewrites stored daily bars, which are kept as traded, into
+// series that are continuous across splits and, optionally, dividends.
+package adjust
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// Mode selects how bar history is adjusted
+type Mode string
+
+const (
+	// Raw returns bars as traded
+	Raw Mode = "raw"
+	// Split scales prices and volume before each split to post-split terms
+	Split Mode = "split"
+	// TotalReturn additionally scales prices before each cash dividend as
+	// if it were reinvested, so percent changes include the payout
+	TotalReturn Mode = "total_return"
+)
+
+// ParseMode validates a mode name; empty selects Split
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case "":
+		return Split, nil
+	case Raw, Split, TotalReturn:
+		return Mode(s), nil
+	}
+	return "", fmt.Errorf("adjust must be one of raw, split, total_return")
+}
+
+// event is one action's effect on earlier prices
+type event struct {
+	exDate      time.Time
+	priceFactor float64
+	splitFactor float64 // share of priceFactor due to splits, for volume
+}
+
+// Bars returns bars, ordered by date, adjusted under mode for actions. Each
+// bar's prices are multiplied by the factors of every action with a later
+// ex date; its prev_close, which is stored already restated for splits on
+// the bar's own date, also by the factors of dividends on that date, so a
+// bar on an ex date reports its change against a comparable prior close.
+// Actions whose factor is not yet known are skipped.
+func Bars(bars []models.DailyBar, actions []models.CorporateAction, mode Mode) []models.DailyBar {
+	events := events(actions, mode)
+	if len(events) == 0 {
+		return bars
+	}
+
+	out := make([]models.DailyBar, len(bars))
+	for i, bar := range bars {
+		price, volume := factorsAfter(events, bar.Date, false)
+		prevPrice, prevSplit := factorsAfter(events, bar.Date, true)
+		prevPrice *= volume / prevSplit
+
+		bar.Open *= price
+		bar.High *= price
+		bar.Low *= price
+		bar.Close *= price
+		bar.VWAP *= price
+		if volume != 1 {
+			bar.Volume = int64(float64(bar.Volume)/volume + 0.5)
+			bar.AvgVolume20 = int64(float64(bar.AvgVolume20)/volume + 0.5)
+			bar.AvgVolume50 = int64(float64(bar.AvgVolume50)/volume + 0.5)
+		}
+		if bar.PrevClose > 0 {
+			bar.PrevClose *= prevPrice
+			bar.Change = bar.Close - bar.PrevClose
+			bar.ChangePct = (bar.Change / bar.PrevClose) * 100
+		} else if bar.ChangeBasis == models.ChangeBasisOpen {
+			bar.Change *= price
+		}
+		out[i] = bar
+	}
+	return out
+}
+
+// Aggregates returns intraday bars adjusted under mode for actions, as Bars
+// adjusts daily bars. A bar belongs to its New York session date.
+func Aggregates(aggs []models.Aggregate, actions []models.CorporateAction, mode Mode) []models.Aggregate {
+	events := events(actions, mode)
+	if len(events) == 0 {
+		return aggs
+	}
+
+	out := make([]models.Aggregate, len(aggs))
+	for i, agg := range aggs {
+		price, volume := factorsAfter(events, agg.Timestamp.In(calendar.Location), false)
+
+		agg.Open *= price
+		agg.High *= price
+		agg.Low *= price
+		agg.Close *= price
+		agg.VWAP *= price
+		if volume != 1 {
+			agg.Volume = int64(float64(agg.Volume)/volume + 0.5)
+		}
+		out[i] = agg
+	}
+	return out
+}
+
+// events returns the actions mode applies, ordered by ex date
+func events(actions []models.CorporateAction, mode Mode) []event {
+	if mode == Raw {
+		return nil
+	}
+
+	var evs []event
+	for _, a := range actions {
+		if a.PriceFactor <= 0 {
+			continue
+		}
+		switch {
+		case a.Type == models.ActionSplit:
+			evs = append(evs, event{exDate: a.ExDate, priceFactor: a.PriceFactor, splitFactor: a.PriceFactor})
+		case a.Type == models.ActionDividend && mode == TotalReturn:
+			evs = append(evs, event{exDate: a.ExDate, priceFactor: a.PriceFactor, splitFactor: 1})
+		}
+	}
+	sort.Slice(evs, func(i, j int) bool { return evs[i].exDate.Before(evs[j].exDate) })
+	return evs
+}
+
+// factorsAfter multiplies the price and split factors of events with ex
+// dates after date, or on or after it when inclusive
+func factorsAfter(evs []event, date time.Time, inclusive bool) (price, split float64) {
+	price, split = 1, 1
+	day := dateKey(date)
+	for i := len(evs) - 1; i >= 0; i-- {
+		ex := dateKey(evs[i].exDate)
+		if ex < day || (ex == day && !inclusive) {
+			break
+		}
+		price *= evs[i].priceFactor
+		split *= evs[i].splitFactor
+	}
+	return price, split
+}
+
+// dateKey compares dates by calendar day regardless of time zone
+func dateKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/indicators"
+	"github.com/go-chi/chi/v5"
+)
+
+// getIndicators returns the latest values of the indicators named in the
+// set query parameter, e.g. ?set=rsi14,sma50,macd
+func (h *Handler) getIndicators(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(chi.URLParam(r, "symbol"))
+
+	specs, err := indicators.ParseSet(r.URL.Query().Get("set"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Specs are already validated, so any failure is the store's
+	res, err := h.indicators.Get(r.Context(), symbol, specs)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	if res == nil {
+		writeError(w, http.StatusNotFound, "no bars stored for "+symbol)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/adjust"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// getLive returns live session snapshots for ?symbols=AAPL,MSFT, or for
+// every streamed symbol
+func (h *Handler) getLive(w http.ResponseWriter, r *http.Request) {
+	var symbols []string
+	for _, symbol := range strings.Split(r.URL.Query().Get("symbols"), ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	snapshots, err := h.store.GetSnapshots(r.Context(), symbols)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshots)
+}
+
+// intradayIntervals are the bar sizes /intraday serves
+var intradayIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+}
+
+// maxIntradayDays caps the sessions one /intraday request may span
+const maxIntradayDays = 31
+
+// getIntraday returns a symbol's intraday bars at ?interval= (1m default)
+// for the sessions ?from= through ?to=, the single session ?date=, or the
+// most recent session stored, adjusted as ?adjust= selects like /bars
+func (h *Handler) getIntraday(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(chi.URLParam(r, "symbol"))
+
+	interval := time.Minute
+	if v := r.URL.Query().Get("interval"); v != "" {
+		var ok bool
+		if interval, ok = intradayIntervals[v]; !ok {
+			writeError(w, http.StatusBadRequest, "interval must be one of 1m, 5m, 15m, 30m, 1h")
+			return
+		}
+	}
+
+	from, err := parseDateParam(r, "from")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	to, err := parseDateParam(r, "to")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	date, err := parseDateParam(r, "date")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	mode, err := adjust.ParseMode(r.URL.Query().Get("adjust"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !date.IsZero() {
+		from, to = date, date
+	}
+
+	if from.IsZero() && to.IsZero() {
+		latest, err := h.store.GetIntradayBars(r.Context(), symbol, time.Minute, time.Time{}, time.Time{}, 1)
+		if err != nil {
+			h.writeStoreError(w, r, err)
+			return
+		}
+		if len(latest) == 0 {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode([]models.Aggregate{})
+			return
+		}
+		from = latest[0].Timestamp.In(calendar.Location)
+		to = from
+	}
+	if from.IsZero() {
+		from = to
+	}
+	if to.IsZero() {
+		to = from
+	}
+	if from.After(to) {
+		writeError(w, http.StatusBadRequest, "from must not be after to")
+		return
+	}
+
+	// Sessions are New York calendar days
+	start := sessionStart(from)
+	end := sessionStart(to).AddDate(0, 0, 1)
+	if end.Sub(start) > maxIntradayDays*24*time.Hour+time.Hour {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("intraday requests may span at most %d days", maxIntradayDays))
+		return
+	}
+
+	bars, err := h.store.GetIntradayBars(r.Context(), symbol, interval, start, end.Add(-time.Nanosecond), 0)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	if mode != adjust.Raw && len(bars) > 0 {
+		actions, err := h.store.GetCorporateActions(r.Context(), symbol)
+		if err != nil {
+			h.writeStoreError(w, r, err)
+			return
+		}
+		bars = adjust.Aggregates(bars, actions, mode)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bars)
+}
+
+// sessionStart returns midnight New York time on t's calendar date
+func sessionStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, calendar.Location)
+}
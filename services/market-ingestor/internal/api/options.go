@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// defaultOptionsFlowLimit is the number of orders returned when ?limit= is
+// not given
+const defaultOptionsFlowLimit = 50
+
+// getOptionsFlow returns stored options flow newest first, filtered by
+// ?ticker=, ?direction= (bullish or bearish) and ?since= (a date or RFC
+// 3339 time)
+func (h *Handler) getOptionsFlow(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.OptionsFlowFilter{Ticker: strings.ToUpper(q.Get("ticker"))}
+
+	switch d := models.FlowDirection(strings.ToUpper(q.Get("direction"))); d {
+	case "", models.FlowBullish, models.FlowBearish:
+		filter.Direction = d
+	default:
+		writeError(w, http.StatusBadRequest, "direction must be bullish or bearish")
+		return
+	}
+
+	if v := q.Get("since"); v != "" {
+		since, err := parseSince(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.Since = since
+	}
+
+	limit := defaultOptionsFlowLimit
+	if v := q.Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be an integer between 1 and 500")
+			return
+		}
+	}
+
+	flows, err := h.store.GetOptionsFlow(r.Context(), filter, limit)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(flows)
+}
@@ -1,25 +1,70 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/adjust"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/events"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/indicators"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/scheduler"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/screener"
 	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 )
 
+const (
+	defaultBarsLimit = 250
+	maxBarsLimit     = 5000
+
+	// defaultMinVolumeRatio is the RVOL floor for /unusual-volume
+	defaultMinVolumeRatio = 2.0
+)
+
+// Backfiller starts and reports on historical backfills
+type Backfiller interface {
+	StartBackfill(req scheduler.BackfillRequest) error
+	BackfillStatus() scheduler.BackfillProgress
+}
+
+// Options configures optional router behavior
+type Options struct {
+	// AdminToken is required as a bearer token on /api/v1/admin routes,
+	// which are not mounted when it is empty
+	AdminToken string
+	// ScreenerDefaults applies to screener lists unless overridden per request
+	ScreenerDefaults models.ScreenerFilter
+}
+
 type Handler struct {
-	store  store.Store
-	logger *slog.Logger
+	store      store.Store
+	backfiller Backfiller
+	indicators *indicators.Engine
+	screener   *screener.Screener
+	events     *events.Broadcaster
+	logger     *slog.Logger
+	opts       Options
 }
 
-func NewRouter(store store.Store, logger *slog.Logger) http.Handler {
+func NewRouter(store store.Store, backfiller Backfiller, engine *indicators.Engine, screener *screener.Screener, events *events.Broadcaster, logger *slog.Logger, opts Options) http.Handler {
 	h := &Handler{
-		store:  store,
-		logger: logger,
+		store:      store,
+		backfiller: backfiller,
+		indicators: engine,
+		screener:   screener,
+		events:     events,
+		logger:     logger,
+		opts:       opts,
 	}
 
 	r := chi.NewRouter()
@@ -31,8 +76,8 @@ func NewRouter(store store.Store, logger *slog.Logger) http.Handler {
 	r.Use(middleware.Recoverer)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:8787"},
-		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "Last-Event-ID"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -41,10 +86,57 @@ func NewRouter(store store.Store, logger *slog.Logger) http.Handler {
 	r.Get("/health", h.health)
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Get("/summary", h.getSummary)
+		r.Get("/stream", h.stream)
 		r.Get("/indices", h.getIndices)
 		r.Get("/gainers", h.getGainers)
 		r.Get("/losers", h.getLosers)
 		r.Get("/active", h.getMostActive)
+		r.Get("/unusual-volume", h.getUnusualVolume)
+		r.Get("/bars/{symbol}", h.getBars)
+		r.Get("/actions/{symbol}", h.getCorporateActions)
+		r.Get("/indicators/{symbol}", h.getIndicators)
+		r.Get("/sectors", h.getSectors)
+		r.Get("/sectors/rotation", h.getSectorRotation)
+		r.Get("/strength/top", h.getStrongest)
+		r.Get("/strength/bottom", h.getWeakest)
+		r.Get("/strength/{ticker}", h.getStrength)
+		r.Get("/signals", h.getSignals)
+		r.Get("/options/flow", h.getOptionsFlow)
+		r.Get("/live", h.getLive)
+		r.Get("/intraday/{symbol}", h.getIntraday)
+		r.Post("/screen", h.runScreen)
+
+		r.Route("/screens", func(r chi.Router) {
+			r.Get("/", h.listScreens)
+			r.Post("/", h.createScreen)
+			r.Get("/{id}", h.getScreen)
+			r.Put("/{id}", h.updateScreen)
+			r.Delete("/{id}", h.deleteScreen)
+			r.Get("/{id}/run", h.runSavedScreen)
+			r.Get("/{id}/diff", h.getScreenDiff)
+			r.Get("/{id}/results", h.getScreenResults)
+		})
+
+		r.Route("/watchlists", func(r chi.Router) {
+			r.Get("/", h.listWatchlists)
+			r.Post("/", h.createWatchlist)
+			r.Get("/{id}", h.getWatchlist)
+			r.Put("/{id}", h.updateWatchlist)
+			r.Delete("/{id}", h.deleteWatchlist)
+			r.Get("/{id}/items", h.getWatchlistItems)
+			r.Post("/{id}/items", h.addWatchlistItem)
+			r.Put("/{id}/items/{ticker}", h.updateWatchlistItem)
+			r.Delete("/{id}/items/{ticker}", h.removeWatchlistItem)
+			r.Get("/{id}/quotes", h.getWatchlistQuotes)
+		})
+
+		if opts.AdminToken != "" {
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(h.requireAdmin)
+				r.Get("/backfill", h.getBackfill)
+				r.Post("/backfill", h.startBackfill)
+			})
+		}
 	})
 
 	return r
@@ -55,12 +147,45 @@ func (h *Handler) health(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) getSummary(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.screenerFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	indices, err := h.store.GetIndices(ctx)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	gainers, err := h.store.GetTopGainers(ctx, 10, filter)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	losers, err := h.store.GetTopLosers(ctx, 10, filter)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	active, err := h.store.GetMostActive(ctx, 10, filter)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	lastUpdated, err := h.store.GetLastUpdated(ctx)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
 	summary := map[string]any{
-		"indices":      h.store.GetIndices(),
-		"top_gainers":  h.store.GetTopGainers(10),
-		"top_losers":   h.store.GetTopLosers(10),
-		"most_active":  h.store.GetMostActive(10),
-		"last_updated": h.store.GetLastUpdated(),
+		"indices":      indices,
+		"top_gainers":  gainers,
+		"top_losers":   losers,
+		"most_active":  active,
+		"last_updated": lastUpdated,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -68,21 +193,262 @@ func (h *Handler) getSummary(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) getIndices(w http.ResponseWriter, r *http.Request) {
+	indices, err := h.store.GetIndices(r.Context())
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(h.store.GetIndices())
+	json.NewEncoder(w).Encode(indices)
 }
 
 func (h *Handler) getGainers(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.screenerFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	gainers, err := h.store.GetTopGainers(r.Context(), 20, filter)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(h.store.GetTopGainers(20))
+	json.NewEncoder(w).Encode(gainers)
 }
 
 func (h *Handler) getLosers(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.screenerFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	losers, err := h.store.GetTopLosers(r.Context(), 20, filter)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(h.store.GetTopLosers(20))
+	json.NewEncoder(w).Encode(losers)
 }
 
 func (h *Handler) getMostActive(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.screenerFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	active, err := h.store.GetMostActive(r.Context(), 20, filter)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(active)
+}
+
+func (h *Handler) getUnusualVolume(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.screenerFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be an integer between 1 and 500")
+			return
+		}
+	}
+
+	minRatio := defaultMinVolumeRatio
+	if v := r.URL.Query().Get("min_ratio"); v != "" {
+		minRatio, err = strconv.ParseFloat(v, 64)
+		if err != nil || minRatio < 0 {
+			writeError(w, http.StatusBadRequest, "min_ratio must be a non-negative number")
+			return
+		}
+	}
+
+	results, err := h.store.GetUnusualVolume(r.Context(), limit, minRatio, filter)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(h.store.GetMostActive(20))
+	json.NewEncoder(w).Encode(results)
+}
+
+func (h *Handler) getBars(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(chi.URLParam(r, "symbol"))
+
+	from, err := parseDateParam(r, "from")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	to, err := parseDateParam(r, "to")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		writeError(w, http.StatusBadRequest, "from must not be after to")
+		return
+	}
+
+	limit := defaultBarsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		if limit > maxBarsLimit {
+			limit = maxBarsLimit
+		}
+	}
+
+	mode, err := adjust.ParseMode(r.URL.Query().Get("adjust"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bars, err := h.store.GetBars(r.Context(), symbol, from, to, limit)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	if mode != adjust.Raw && len(bars) > 0 {
+		actions, err := h.store.GetCorporateActions(r.Context(), symbol)
+		if err != nil {
+			h.writeStoreError(w, r, err)
+			return
+		}
+		bars = adjust.Bars(bars, actions, mode)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bars)
+}
+
+// getCorporateActions returns a symbol's splits and dividends with the
+// price factors bar adjustment applies
+func (h *Handler) getCorporateActions(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(chi.URLParam(r, "symbol"))
+
+	actions, err := h.store.GetCorporateActions(r.Context(), symbol)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(actions)
+}
+
+// screenerFilter overlays the types, min_price, min_volume and
+// min_dollar_volume query parameters on the configured defaults.
+// types=all disables type filtering.
+func (h *Handler) screenerFilter(r *http.Request) (models.ScreenerFilter, error) {
+	filter := h.opts.ScreenerDefaults
+	q := r.URL.Query()
+
+	if q.Has("types") {
+		filter.Types = parseTypes(q.Get("types"))
+	}
+	if v := q.Get("min_price"); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("min_price must be a non-negative number")
+		}
+		filter.MinPrice = n
+	}
+	if v := q.Get("min_volume"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("min_volume must be a non-negative integer")
+		}
+		filter.MinVolume = n
+	}
+	if v := q.Get("min_dollar_volume"); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("min_dollar_volume must be a non-negative number")
+		}
+		filter.MinDollarVolume = n
+	}
+
+	return filter, nil
+}
+
+// parseTypes splits a comma-separated list of security type codes. An empty
+// list or "all" means no type filter.
+func parseTypes(v string) []string {
+	var types []string
+	for _, t := range strings.Split(v, ",") {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if t == "ALL" || t == "*" {
+			return nil
+		}
+		if t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// parseDateParam parses an optional YYYY-MM-DD query parameter
+func parseDateParam(r *http.Request, name string) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date in YYYY-MM-DD format", name)
+	}
+	return t, nil
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// writeStoreError maps a store failure to an error response. Missing and
+// duplicate records are 404 and 409; an unreachable database is 503, a
+// timed-out query 504 and anything else 500, with the cause logged rather
+// than returned. Nothing is written once the client has gone away.
+func (h *Handler) writeStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	logger := h.logger.With("path", r.URL.Path, "request_id", middleware.GetReqID(r.Context()), "error", err)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		writeError(w, http.StatusNotFound, "not found")
+	case errors.Is(err, store.ErrDuplicate):
+		writeError(w, http.StatusConflict, "already exists")
+	case r.Context().Err() != nil:
+		logger.Debug("request canceled during store call")
+	case errors.Is(err, context.DeadlineExceeded):
+		logger.Error("store call timed out")
+		writeError(w, http.StatusGatewayTimeout, "storage timed out")
+	case store.IsUnavailable(err):
+		logger.Error("store unavailable")
+		writeError(w, http.StatusServiceUnavailable, "storage unavailable")
+	default:
+		logger.Error("store call failed")
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
 }
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/screener"
+)
+
+// runScreen evaluates a custom screen against the latest bars. The
+// screener list query parameters narrow the universe as on /gainers.
+func (h *Handler) runScreen(w http.ResponseWriter, r *http.Request) {
+	universe, err := h.screenerFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req screener.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	opts, err := h.screener.CompileOptions(r.Context())
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	screen, err := screener.Compile(req, opts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.screener.Run(r.Context(), screen, universe)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/screener"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	maxScreenNameLength  = 100
+	defaultScreenHistory = 30
+)
+
+func (h *Handler) listScreens(w http.ResponseWriter, r *http.Request) {
+	screens, err := h.store.ListScreens(r.Context())
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(screens)
+}
+
+func (h *Handler) getScreen(w http.ResponseWriter, r *http.Request) {
+	screen, err := h.store.GetScreen(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeScreenError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(screen)
+}
+
+func (h *Handler) createScreen(w http.ResponseWriter, r *http.Request) {
+	screen, ok := h.decodeScreen(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.store.CreateScreen(r.Context(), screen)
+	if err != nil {
+		h.writeScreenError(w, r, err)
+		return
+	}
+
+	h.logger.Info("saved screen created", "id", created.ID, "name", created.Name)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *Handler) updateScreen(w http.ResponseWriter, r *http.Request) {
+	screen, ok := h.decodeScreen(w, r)
+	if !ok {
+		return
+	}
+	screen.ID = chi.URLParam(r, "id")
+
+	updated, err := h.store.UpdateScreen(r.Context(), screen)
+	if err != nil {
+		h.writeScreenError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *Handler) deleteScreen(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.DeleteScreen(r.Context(), chi.URLParam(r, "id")); err != nil {
+		h.writeScreenError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// runSavedScreen evaluates a saved screen against the latest bars now
+func (h *Handler) runSavedScreen(w http.ResponseWriter, r *http.Request) {
+	saved, err := h.store.GetScreen(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeScreenError(w, r, err)
+		return
+	}
+
+	universe, err := h.screenerFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	opts, err := h.screener.CompileOptions(r.Context())
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	screen, err := screener.Compile(screener.RequestFor(saved), opts)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	res, err := h.screener.Run(r.Context(), screen, universe)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// getScreenDiff returns the symbols that entered and exited a saved screen
+// on date (its latest evaluation by default)
+func (h *Handler) getScreenDiff(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := h.store.GetScreen(r.Context(), id); err != nil {
+		h.writeScreenError(w, r, err)
+		return
+	}
+
+	date, err := parseDateParam(r, "date")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.store.GetScreenResults(r.Context(), id, date, 1)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	if len(results) == 0 && date.IsZero() {
+		writeError(w, http.StatusNotFound, "screen has not been evaluated yet")
+		return
+	}
+	if len(results) == 0 || (!date.IsZero() && results[0].Date.Format("2006-01-02") != date.Format("2006-01-02")) {
+		writeError(w, http.StatusNotFound, "screen was not evaluated on "+date.Format("2006-01-02"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results[0])
+}
+
+// getScreenResults returns a saved screen's recent daily results
+func (h *Handler) getScreenResults(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := h.store.GetScreen(r.Context(), id); err != nil {
+		h.writeScreenError(w, r, err)
+		return
+	}
+
+	limit := defaultScreenHistory
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be an integer between 1 and 500")
+			return
+		}
+	}
+
+	results, err := h.store.GetScreenResults(r.Context(), id, time.Time{}, limit)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// decodeScreen reads and validates a saved screen body, writing an error and
+// returning false if it is invalid or cannot be checked
+func (h *Handler) decodeScreen(w http.ResponseWriter, r *http.Request) (models.SavedScreen, bool) {
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		screener.Request
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return models.SavedScreen{}, false
+	}
+
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" || len(body.Name) > maxScreenNameLength {
+		writeError(w, http.StatusBadRequest, "name is required and must be at most 100 characters")
+		return models.SavedScreen{}, false
+	}
+	opts, err := h.screener.CompileOptions(r.Context())
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return models.SavedScreen{}, false
+	}
+	if _, err := screener.Compile(body.Request, opts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return models.SavedScreen{}, false
+	}
+
+	return models.SavedScreen{
+		Name:        body.Name,
+		Description: body.Description,
+		Filter:      body.Filter,
+		Sort:        body.Sort,
+		Order:       strings.ToLower(body.Order),
+		Limit:       body.Limit,
+	}, true
+}
+
+// writeScreenError is writeStoreError with saved-screen wording
+func (h *Handler) writeScreenError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		writeError(w, http.StatusNotFound, "screen not found")
+	case errors.Is(err, store.ErrDuplicate):
+		writeError(w, http.StatusConflict, "a screen with that name already exists")
+	default:
+		h.writeStoreError(w, r, err)
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
+)
+
+// getSectors returns the sector ETFs on ?date=, or the latest analyzed
+// session, by relative strength vs SPY, strongest first
+func (h *Handler) getSectors(w http.ResponseWriter, r *http.Request) {
+	date, err := parseDateParam(r, "date")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.store.GetSectorData(r.Context(), date)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+// getSectorRotation returns the rotation signal on ?date=, or the latest
+func (h *Handler) getSectorRotation(w http.ResponseWriter, r *http.Request) {
+	date, err := parseDateParam(r, "date")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rotation, err := h.store.GetSectorRotation(r.Context(), date)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "no sector rotation for that date")
+		return
+	}
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rotation)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// defaultSignalsLimit is the page size when ?limit= is not given
+const defaultSignalsLimit = 50
+
+// signalsPage is a page of signals; NextCursor is set while more remain
+type signalsPage struct {
+	Signals    []models.Signal `json:"signals"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// getSignals returns signals newest first, filtered by ?source=, ?ticker=,
+// ?direction= and ?since= (a date or RFC 3339 time), ?limit= (50 default)
+// at a time. Pass a page's next_cursor as ?cursor= for the next page.
+func (h *Handler) getSignals(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.SignalFilter{
+		Source: models.SignalSource(strings.ToLower(q.Get("source"))),
+		Ticker: strings.ToUpper(q.Get("ticker")),
+	}
+
+	switch d := models.SignalDirection(strings.ToLower(q.Get("direction"))); d {
+	case "", models.Bullish, models.Bearish, models.Neutral:
+		filter.Direction = d
+	default:
+		writeError(w, http.StatusBadRequest, "direction must be bullish, bearish or neutral")
+		return
+	}
+
+	if v := q.Get("since"); v != "" {
+		since, err := parseSince(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.Since = since
+	}
+
+	limit := defaultSignalsLimit
+	if v := q.Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be an integer between 1 and 500")
+			return
+		}
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		var err error
+		if filter.Before, filter.BeforeID, err = decodeSignalCursor(v); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// One extra row tells whether another page follows
+	signals, err := h.store.GetSignals(r.Context(), filter, limit+1)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	page := signalsPage{Signals: signals}
+	if len(signals) > limit {
+		page.Signals = signals[:limit]
+		last := page.Signals[limit-1]
+		page.NextCursor = encodeSignalCursor(last.Timestamp, last.ID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseSince reads a YYYY-MM-DD date, as the start of that New York
+// trading date, or an RFC 3339 time
+func parseSince(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", v, calendar.Location)
+	if err != nil {
+		return time.Time{}, errors.New("since must be a date in YYYY-MM-DD format or an RFC 3339 time")
+	}
+	return t, nil
+}
+
+// encodeSignalCursor returns an opaque cursor resuming after the signal
+// with timestamp t and ID id
+func encodeSignalCursor(t time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(t.UTC().Format(time.RFC3339Nano) + "|" + id))
+}
+
+func decodeSignalCursor(cursor string) (time.Time, string, error) {
+	invalid := errors.New("invalid cursor")
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", invalid
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+	return t, id, nil
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/events"
+)
+
+const (
+	// heartbeatInterval keeps idle streams alive through proxies
+	heartbeatInterval = 15 * time.Second
+
+	// streamRetry is the reconnect delay suggested to EventSource clients
+	streamRetry = 5 * time.Second
+
+	// resyncEvent tells a resuming client that events were missed and it
+	// should refetch current state
+	resyncEvent = "resync"
+)
+
+// stream serves events as server-sent events. Clients resume with the
+// Last-Event-ID header (or last_event_id query parameter) and may narrow
+// the stream with ?types=bars,alert.
+func (h *Handler) stream(w http.ResponseWriter, r *http.Request) {
+	var lastID uint64
+	if v := r.Header.Get("Last-Event-ID"); v != "" || r.URL.Query().Has("last_event_id") {
+		if v == "" {
+			v = r.URL.Query().Get("last_event_id")
+		}
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Last-Event-ID must be an event ID")
+			return
+		}
+		lastID = id
+	}
+
+	var types map[string]bool
+	if v := r.URL.Query().Get("types"); v != "" {
+		types = make(map[string]bool)
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types[t] = true
+			}
+		}
+	}
+	wanted := func(ev events.Event) bool {
+		return types == nil || types[ev.Type]
+	}
+
+	replay, complete, ch, cancel := h.events.Subscribe(lastID)
+	defer cancel()
+
+	// The server's write timeout would otherwise end the stream, so
+	// extend the deadline before each write instead
+	rc := http.NewResponseController(w)
+	write := func(format string, args ...any) bool {
+		rc.SetWriteDeadline(time.Now().Add(2 * heartbeatInterval))
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+	send := func(ev events.Event) bool {
+		return write("id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, ev.Data)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !write("retry: %d\n\n", streamRetry.Milliseconds()) {
+		return
+	}
+	if !complete {
+		if !write("event: %s\ndata: {}\n\n", resyncEvent) {
+			return
+		}
+	}
+	for _, ev := range replay {
+		if wanted(ev) && !send(ev) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-ch:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and
+				// resumes from its last event ID
+				return
+			}
+			if wanted(ev) && !send(ev) {
+				return
+			}
+		case <-heartbeat.C:
+			if !write(": heartbeat\n\n") {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
+	"github.com/go-chi/chi/v5"
+)
+
+// getStrongest returns the highest ranked strength scores
+func (h *Handler) getStrongest(w http.ResponseWriter, r *http.Request) {
+	h.getStrengthRanking(w, r, false)
+}
+
+// getWeakest returns the lowest ranked strength scores, weakest first
+func (h *Handler) getWeakest(w http.ResponseWriter, r *http.Request) {
+	h.getStrengthRanking(w, r, true)
+}
+
+// getStrengthRanking serves ?limit= (20 default) scores from either end of
+// the ranking on ?date=, or the latest session ranked
+func (h *Handler) getStrengthRanking(w http.ResponseWriter, r *http.Request, bottom bool) {
+	date, err := parseDateParam(r, "date")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be an integer between 1 and 500")
+			return
+		}
+	}
+
+	scores, err := h.store.GetStrengthScores(r.Context(), date, limit, bottom)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scores)
+}
+
+// getStrength returns a ticker's score and components on ?date=, or its
+// latest
+func (h *Handler) getStrength(w http.ResponseWriter, r *http.Request) {
+	ticker := strings.ToUpper(chi.URLParam(r, "ticker"))
+	date, err := parseDateParam(r, "date")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	score, err := h.store.GetStrengthScore(r.Context(), ticker, date)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "no strength score for "+ticker)
+		return
+	}
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(score)
+}
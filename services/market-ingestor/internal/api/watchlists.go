@@ -0,0 +1,224 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
+	"github.com/go-chi/chi/v5"
+)
+
+const maxWatchlistNameLength = 100
+
+// watchlistTickerRe matches the tickers a watchlist accepts, such as BRK.B
+var watchlistTickerRe = regexp.MustCompile(`^[A-Z][A-Z0-9.\-]{0,9}$`)
+
+func (h *Handler) listWatchlists(w http.ResponseWriter, r *http.Request) {
+	lists, err := h.store.ListWatchlists(r.Context())
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lists)
+}
+
+func (h *Handler) getWatchlist(w http.ResponseWriter, r *http.Request) {
+	list, err := h.store.GetWatchlist(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeWatchlistError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *Handler) createWatchlist(w http.ResponseWriter, r *http.Request) {
+	list, ok := decodeWatchlist(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.store.CreateWatchlist(r.Context(), list)
+	if err != nil {
+		h.writeStoreError(w, r, err)
+		return
+	}
+
+	h.logger.Info("watchlist created", "id", created.ID, "name", created.Name)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// updateWatchlist renames a watchlist and replaces its description
+func (h *Handler) updateWatchlist(w http.ResponseWriter, r *http.Request) {
+	list, ok := decodeWatchlist(w, r)
+	if !ok {
+		return
+	}
+	list.ID = chi.URLParam(r, "id")
+
+	updated, err := h.store.UpdateWatchlist(r.Context(), list)
+	if err != nil {
+		h.writeWatchlistError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *Handler) deleteWatchlist(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.DeleteWatchlist(r.Context(), chi.URLParam(r, "id")); err != nil {
+		h.writeWatchlistError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getWatchlistItems returns a watchlist's items ordered by ticker
+func (h *Handler) getWatchlistItems(w http.ResponseWriter, r *http.Request) {
+	list, err := h.store.GetWatchlist(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeWatchlistError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list.Items)
+}
+
+// addWatchlistItem adds {"ticker": ..., "notes": ...} to a watchlist
+func (h *Handler) addWatchlistItem(w http.ResponseWriter, r *http.Request) {
+	var body models.WatchlistItem
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	body.Ticker = strings.ToUpper(strings.TrimSpace(body.Ticker))
+	if !watchlistTickerRe.MatchString(body.Ticker) {
+		writeError(w, http.StatusBadRequest, "ticker is required and must be at most 10 characters")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	added, err := h.store.AddWatchlistItem(r.Context(), id, models.WatchlistItem{
+		Ticker: body.Ticker,
+		Notes:  strings.TrimSpace(body.Notes),
+	})
+	if errors.Is(err, store.ErrDuplicate) {
+		writeError(w, http.StatusConflict, body.Ticker+" is already on this watchlist")
+		return
+	}
+	if err != nil {
+		h.writeWatchlistError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(added)
+}
+
+// updateWatchlistItem replaces an item's notes with {"notes": ...}
+func (h *Handler) updateWatchlistItem(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Notes string `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	ticker := strings.ToUpper(chi.URLParam(r, "ticker"))
+	updated, err := h.store.UpdateWatchlistItem(r.Context(), id, models.WatchlistItem{
+		Ticker: ticker,
+		Notes:  strings.TrimSpace(body.Notes),
+	})
+	if err != nil {
+		h.writeWatchlistItemError(w, r, id, ticker, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *Handler) removeWatchlistItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ticker := strings.ToUpper(chi.URLParam(r, "ticker"))
+	if err := h.store.RemoveWatchlistItem(r.Context(), id, ticker); err != nil {
+		h.writeWatchlistItemError(w, r, id, ticker, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getWatchlistQuotes returns every item on a watchlist with its latest bar
+// (close, change and volume ratio) and latest strength score
+func (h *Handler) getWatchlistQuotes(w http.ResponseWriter, r *http.Request) {
+	quotes, err := h.store.GetWatchlistQuotes(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeWatchlistError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quotes)
+}
+
+// decodeWatchlist reads and validates a watchlist body, writing a 400 and
+// returning false if it is invalid
+func decodeWatchlist(w http.ResponseWriter, r *http.Request) (models.Watchlist, bool) {
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return models.Watchlist{}, false
+	}
+
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" || len(body.Name) > maxWatchlistNameLength {
+		writeError(w, http.StatusBadRequest, "name is required and must be at most 100 characters")
+		return models.Watchlist{}, false
+	}
+
+	return models.Watchlist{
+		Name:        body.Name,
+		Description: strings.TrimSpace(body.Description),
+	}, true
+}
+
+// writeWatchlistError is writeStoreError with watchlist wording
+func (h *Handler) writeWatchlistError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "watchlist not found")
+		return
+	}
+	h.writeStoreError(w, r, err)
+}
+
+// writeWatchlistItemError tells a missing watchlist apart from a ticker
+// that is not on it
+func (h *Handler) writeWatchlistItemError(w http.ResponseWriter, r *http.Request, id, ticker string, err error) {
+	if !errors.Is(err, store.ErrNotFound) {
+		h.writeStoreError(w, r, err)
+		return
+	}
+	if _, err := h.store.GetWatchlist(r.Context(), id); err != nil {
+		h.writeWatchlistError(w, r, err)
+		return
+	}
+	writeError(w, http.StatusNotFound, ticker+" is not on this watchlist")
+}
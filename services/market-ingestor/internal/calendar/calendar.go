@@ -0,0 +1,227 @@
+// Package calendar implements the NYSE trading calendar: full-day holidays
+// and early (1:00 PM ET) closes, computed by rule so it works for any year.
+package calendar
+
+import (
+	"sort"
+	"time"
+	_ "time/tzdata" // embed zone data so Location never fails to load
+)
+
+// Location is the exchange time zone. All dates returned by this package are
+// midnight in Location, and all inputs are interpreted by their calendar date
+// (see Date).
+var Location = mustLoadLocation("America/New_York")
+
+const (
+	// OpenHour and OpenMinute are the session open (9:30 AM ET)
+	OpenHour   = 9
+	OpenMinute = 30
+	// RegularCloseHour is the normal session close (4:00 PM ET)
+	RegularCloseHour = 16
+	// EarlyCloseHour is the session close on half days (1:00 PM ET)
+	EarlyCloseHour = 13
+)
+
+// Day is a named calendar date such as a holiday or early close
+type Day struct {
+	Date time.Time `json:"date"`
+	Name string    `json:"name"`
+}
+
+// Holidays returns the NYSE full-day closures for year, in date order
+func Holidays(year int) []Day {
+	days := []Day{
+		{Date: observed(date(year, time.January, 1)), Name: "New Year's Day"},
+		{Date: nthWeekday(year, time.January, time.Monday, 3), Name: "Martin Luther King Jr. Day"},
+		{Date: nthWeekday(year, time.February, time.Monday, 3), Name: "Washington's Birthday"},
+		{Date: easter(year).AddDate(0, 0, -2), Name: "Good Friday"},
+		{Date: lastWeekday(year, time.May, time.Monday), Name: "Memorial Day"},
+		{Date: observed(date(year, time.July, 4)), Name: "Independence Day"},
+		{Date: nthWeekday(year, time.September, time.Monday, 1), Name: "Labor Day"},
+		{Date: nthWeekday(year, time.November, time.Thursday, 4), Name: "Thanksgiving Day"},
+		{Date: observed(date(year, time.December, 25)), Name: "Christmas Day"},
+	}
+	if year >= 2022 {
+		days = append(days, Day{Date: observed(date(year, time.June, 19)), Name: "Juneteenth"})
+	}
+
+	// NYSE does not close on Friday Dec 31 when New Year's Day is a Saturday
+	filtered := days[:0]
+	for _, d := range days {
+		if d.Date.Year() == year {
+			filtered = append(filtered, d)
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].Date.Before(filtered[j].Date)
+	})
+	return filtered
+}
+
+// EarlyCloses returns the NYSE half days for year, in date order
+func EarlyCloses(year int) []Day {
+	var days []Day
+
+	// July 3 closes early when Independence Day falls Tuesday through Friday
+	july4 := date(year, time.July, 4)
+	if wd := july4.Weekday(); wd >= time.Tuesday && wd <= time.Friday {
+		days = append(days, Day{Date: date(year, time.July, 3), Name: "Independence Day Eve"})
+	}
+
+	thanksgiving := nthWeekday(year, time.November, time.Thursday, 4)
+	days = append(days, Day{Date: thanksgiving.AddDate(0, 0, 1), Name: "Day after Thanksgiving"})
+
+	// Christmas Eve closes early when it falls Monday through Thursday
+	christmasEve := date(year, time.December, 24)
+	if wd := christmasEve.Weekday(); wd >= time.Monday && wd <= time.Thursday {
+		days = append(days, Day{Date: christmasEve, Name: "Christmas Eve"})
+	}
+
+	return days
+}
+
+// Holiday returns the holiday on t's date, if any
+func Holiday(t time.Time) (Day, bool) {
+	day := Date(t)
+	for _, h := range Holidays(day.Year()) {
+		if h.Date.Equal(day) {
+			return h, true
+		}
+	}
+	return Day{}, false
+}
+
+// IsTradingDay reports whether the exchange is open on t's date
+func IsTradingDay(t time.Time) bool {
+	day := Date(t)
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := Holiday(day)
+	return !holiday
+}
+
+// IsEarlyClose reports whether t's date is a trading day with a 1:00 PM close
+func IsEarlyClose(t time.Time) bool {
+	day := Date(t)
+	for _, d := range EarlyCloses(day.Year()) {
+		if d.Date.Equal(day) {
+			return IsTradingDay(day)
+		}
+	}
+	return false
+}
+
+// OpenTime returns the session open on t's date. The result is only
+// meaningful for trading days.
+func OpenTime(t time.Time) time.Time {
+	day := Date(t)
+	return time.Date(day.Year(), day.Month(), day.Day(), OpenHour, OpenMinute, 0, 0, Location)
+}
+
+// CloseTime returns the session close on t's date. The result is only
+// meaningful for trading days.
+func CloseTime(t time.Time) time.Time {
+	day := Date(t)
+	hour := RegularCloseHour
+	if IsEarlyClose(day) {
+		hour = EarlyCloseHour
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, Location)
+}
+
+// PreviousTradingDay returns the last trading day strictly before t's date
+func PreviousTradingDay(t time.Time) time.Time {
+	day := Date(t).AddDate(0, 0, -1)
+	for !IsTradingDay(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// NextTradingDay returns the first trading day strictly after t's date
+func NextTradingDay(t time.Time) time.Time {
+	day := Date(t).AddDate(0, 0, 1)
+	for !IsTradingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// TradingDays returns every trading day between from and to, inclusive
+func TradingDays(from, to time.Time) []time.Time {
+	var days []time.Time
+	end := Date(to)
+	for day := Date(from); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if IsTradingDay(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// Date returns midnight in Location on t's calendar date. The date is read
+// in t's own location, so a YYYY-MM-DD parsed as UTC keeps its day; convert
+// instants such as time.Now() to Location first.
+func Date(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return date(y, m, d)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, Location)
+}
+
+// observed moves a Saturday holiday to Friday and a Sunday holiday to Monday
+func observed(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, -1)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// nthWeekday returns the nth occurrence of weekday in month
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := date(year, month, 1)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+(n-1)*7)
+}
+
+// lastWeekday returns the last occurrence of weekday in month
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	last := date(year, month+1, 1).AddDate(0, 0, -1)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset)
+}
+
+// easter returns Western Easter Sunday using the anonymous Gregorian algorithm
+func easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic("calendar: loading " + name + ": " + err.Error())
+	}
+	return loc
+}
@@ -1,18 +1,138 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
 	Port          string
 	PolygonAPIKey string
 	DatabaseURL   string
+	// AutoMigrate applies pending schema migrations when connecting to
+	// DATABASE_URL
+	AutoMigrate bool
+	// MemorySnapshotPath is where the in-memory store, used without
+	// DATABASE_URL, persists its data across restarts; empty disables
+	MemorySnapshotPath string
+	// MemorySnapshotInterval is how often the in-memory store is saved
+	// while running, in addition to on shutdown
+	MemorySnapshotInterval time.Duration
+
+	// DataProvider selects the market data source: "polygon" or "csv"
+	DataProvider string
+	// DataDir is the directory of OHLCV CSV files read by the csv provider
+	DataDir string
+
+	// PolygonRequestsPerMinute throttles Polygon calls (5 matches the free tier)
+	PolygonRequestsPerMinute int
+	// PolygonMaxRetries is how many times throttled or failed calls are retried
+	PolygonMaxRetries int
+	// PolygonMode is "live", "record" (save responses) or "replay" (serve saved responses)
+	PolygonMode string
+	// PolygonFixturesDir holds recorded Polygon responses
+	PolygonFixturesDir string
+	// PolygonStream enables streaming minute bars over Polygon's WebSocket
+	PolygonStream bool
+	// PolygonStreamURL is the stocks WebSocket endpoint (real-time or delayed)
+	PolygonStreamURL string
+	// StreamSymbols are the symbols whose minute bars are streamed
+	StreamSymbols []string
+	// IntradaySymbols have their minute bars fetched after each EOD ingest
+	IntradaySymbols []string
+	// SectorLookups is how many unclassified tickers each ticker refresh
+	// looks up in Polygon ticker details for a sector
+	SectorLookups int
+
+	// UWAPIKey enables polling Unusual Whales for options flow
+	UWAPIKey string
+	// UWBaseURL is the Unusual Whales API address
+	UWBaseURL string
+	// UWRequestsPerMinute throttles Unusual Whales calls
+	UWRequestsPerMinute int
+	// UWMaxRetries is how many times throttled or failed calls are retried
+	UWMaxRetries int
+	// OptionsFlowInterval is how often flow alerts are polled during the session
+	OptionsFlowInterval time.Duration
+	// OptionsFlowMinPremium, OptionsFlowMinVolumeOI and OptionsFlowMaxDTE
+	// are the flow rules' floors on premium and volume vs open interest
+	// and ceiling on days to expiration
+	OptionsFlowMinPremium  float64
+	OptionsFlowMinVolumeOI float64
+	OptionsFlowMaxDTE      int
+
+	// ChangeFallback selects how change is computed when no prior close is
+	// available for a symbol: "open" (vs same-day open) or "none" (zero)
+	ChangeFallback string
+	// PrevCloseMaxLookups is how many symbols missing a stored prior close
+	// are looked up one at a time; when more are missing, the previous
+	// day's grouped daily is fetched instead
+	PrevCloseMaxLookups int
+
+	// ScreenerTypes are the default security types in screener lists; "all" disables
+	ScreenerTypes []string
+	// ScreenerMinPrice, ScreenerMinVolume and ScreenerMinDollarVolume are
+	// default liquidity floors for screener lists
+	ScreenerMinPrice        float64
+	ScreenerMinVolume       int64
+	ScreenerMinDollarVolume float64
+
+	// StrengthTypes, StrengthMinPrice, StrengthMinVolume and
+	// StrengthMinDollarVolume narrow the tickers ranked by strength; the
+	// defaults rank every ticker with enough history
+	StrengthTypes           []string
+	StrengthMinPrice        float64
+	StrengthMinVolume       int64
+	StrengthMinDollarVolume float64
+
+	// AdminToken protects /api/v1/admin routes; empty disables them
+	AdminToken string
+	// BackfillConcurrency bounds concurrent grouped daily fetches during
+	// backfills, up to scheduler.MaxBackfillConcurrency
+	BackfillConcurrency int
 }
 
 func Load() *Config {
 	return &Config{
-		Port:          getEnv("PORT", "8080"),
-		PolygonAPIKey: getEnv("POLYGON_API_KEY", ""),
-		DatabaseURL:   getEnv("DATABASE_URL", ""),
+		Port:                     getEnv("PORT", "8080"),
+		PolygonAPIKey:            getEnv("POLYGON_API_KEY", ""),
+		DatabaseURL:              getEnv("DATABASE_URL", ""),
+		AutoMigrate:              getEnvBool("AUTO_MIGRATE", true),
+		MemorySnapshotPath:       getEnv("MEMORY_SNAPSHOT_PATH", "./data/memory.snapshot"),
+		MemorySnapshotInterval:   getEnvDuration("MEMORY_SNAPSHOT_INTERVAL", 5*time.Minute),
+		DataProvider:             getEnv("DATA_PROVIDER", "polygon"),
+		DataDir:                  getEnv("DATA_DIR", "./data"),
+		PolygonRequestsPerMinute: getEnvInt("POLYGON_REQUESTS_PER_MINUTE", 5),
+		PolygonMaxRetries:        getEnvInt("POLYGON_MAX_RETRIES", 4),
+		PolygonMode:              getEnv("POLYGON_MODE", "live"),
+		PolygonFixturesDir:       getEnv("POLYGON_FIXTURES_DIR", "./testdata/polygon"),
+		PolygonStream:            getEnvBool("POLYGON_STREAM", false),
+		PolygonStreamURL:         getEnv("POLYGON_STREAM_URL", "wss://socket.polygon.io/stocks"),
+		StreamSymbols:            getEnvList("STREAM_SYMBOLS", "SPY,QQQ,DIA,IWM"),
+		IntradaySymbols:          getEnvList("INTRADAY_SYMBOLS", ""),
+		SectorLookups:            getEnvInt("SECTOR_LOOKUPS", 50),
+		UWAPIKey:                 getEnv("UW_API_KEY", ""),
+		UWBaseURL:                getEnv("UW_BASE_URL", "https://api.unusualwhales.com"),
+		UWRequestsPerMinute:      getEnvInt("UW_REQUESTS_PER_MINUTE", 60),
+		UWMaxRetries:             getEnvInt("UW_MAX_RETRIES", 3),
+		OptionsFlowInterval:      getEnvDuration("OPTIONS_FLOW_INTERVAL", time.Minute),
+		OptionsFlowMinPremium:    getEnvFloat("OPTIONS_FLOW_MIN_PREMIUM", 50000),
+		OptionsFlowMinVolumeOI:   getEnvFloat("OPTIONS_FLOW_MIN_VOLUME_OI", 1.5),
+		OptionsFlowMaxDTE:        getEnvInt("OPTIONS_FLOW_MAX_DTE", 45),
+		ChangeFallback:           getEnv("CHANGE_FALLBACK", "open"),
+		PrevCloseMaxLookups:      getEnvInt("PREV_CLOSE_MAX_LOOKUPS", 25),
+		ScreenerTypes:            getEnvList("SCREENER_TYPES", "CS,ETF,ADRC"),
+		ScreenerMinPrice:         getEnvFloat("SCREENER_MIN_PRICE", 1),
+		ScreenerMinVolume:        int64(getEnvInt("SCREENER_MIN_VOLUME", 100000)),
+		ScreenerMinDollarVolume:  getEnvFloat("SCREENER_MIN_DOLLAR_VOLUME", 0),
+		StrengthTypes:            getEnvList("STRENGTH_TYPES", "all"),
+		StrengthMinPrice:         getEnvFloat("STRENGTH_MIN_PRICE", 0),
+		StrengthMinVolume:        int64(getEnvInt("STRENGTH_MIN_VOLUME", 0)),
+		StrengthMinDollarVolume:  getEnvFloat("STRENGTH_MIN_DOLLAR_VOLUME", 0),
+		AdminToken:               getEnv("ADMIN_TOKEN", ""),
+		BackfillConcurrency:      getEnvInt("BACKFILL_CONCURRENCY", 2),
 	}
 }
 
@@ -22,3 +142,56 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+// getEnvDuration parses a Go duration such as "90s" or "5m"
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+// getEnvList splits a comma-separated value into upper-cased items. "all"
+// yields an empty list.
+func getEnvList(key, fallback string) []string {
+	var items []string
+	for _, item := range strings.Split(getEnv(key, fallback), ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if item == "ALL" {
+			return nil
+		}
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
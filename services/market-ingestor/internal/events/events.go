@@ -0,0 +1,142 @@
+// Package events fans out service events to live subscribers, such as the
+// API's server-sent event stream, and keeps a short replay buffer so
+// reconnecting clients can resume where they left off.
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Event types published by the service
+const (
+	// TypeBars follows a day of bars being saved and its analytics run
+	TypeBars = "bars"
+	// TypeIndices carries index ETF data when it changes
+	TypeIndices = "indices"
+	// TypeAlert is raised when something a user asked to watch fires,
+	// such as symbols entering a saved screen
+	TypeAlert = "alert"
+	// TypeSnapshot carries a symbol's live session snapshot after each
+	// streamed minute bar
+	TypeSnapshot = "snapshot"
+	// TypeSignal carries each signal as it is saved
+	TypeSignal = "signal"
+)
+
+const (
+	// DefaultBufferSize is how many recent events are kept for replay
+	DefaultBufferSize = 256
+
+	// subscriberBuffer is how far a subscriber may fall behind before it
+	// is dropped; it can reconnect and replay from its last event ID
+	subscriberBuffer = 64
+)
+
+// Event is a published event. IDs increase monotonically, including
+// across restarts, so they can be used to resume a stream.
+type Event struct {
+	ID   uint64          `json:"id"`
+	Type string          `json:"type"`
+	Time time.Time       `json:"time"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Publisher accepts events; Broadcaster implements it
+type Publisher interface {
+	Publish(eventType string, data any)
+}
+
+// Broadcaster delivers published events to every subscriber
+type Broadcaster struct {
+	mu          sync.Mutex
+	nextID      uint64
+	buffer      []Event // ring of the most recent events
+	start       int     // index of the oldest event in buffer
+	subscribers map[chan Event]struct{}
+}
+
+// NewBroadcaster returns a broadcaster keeping the last bufferSize events
+func NewBroadcaster(bufferSize int) *Broadcaster {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Broadcaster{
+		// Seed IDs from the clock so they keep increasing across restarts
+		// and a client's Last-Event-ID from a previous process is older
+		// than anything this one publishes
+		nextID:      uint64(time.Now().UnixMilli()) * 1000,
+		buffer:      make([]Event, 0, bufferSize),
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Publish sends an event to all subscribers. data is encoded as JSON; an
+// unencodable value is published as null.
+func (b *Broadcaster) Publish(eventType string, data any) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		raw = json.RawMessage("null")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	ev := Event{ID: b.nextID, Type: eventType, Time: time.Now(), Data: raw}
+
+	if len(b.buffer) < cap(b.buffer) {
+		b.buffer = append(b.buffer, ev)
+	} else {
+		b.buffer[b.start] = ev
+		b.start = (b.start + 1) % len(b.buffer)
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			// Too slow; drop it rather than block publishers
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe registers a subscriber. Buffered events after lastID are
+// returned for replay; complete is false if events after lastID have
+// already left the buffer. A zero lastID replays nothing. The channel is
+// closed when the subscriber is dropped for falling behind or cancel is
+// called.
+func (b *Broadcaster) Subscribe(lastID uint64) (replay []Event, complete bool, events <-chan Event, cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete = true
+	if lastID > 0 && lastID < b.nextID {
+		for i := 0; i < len(b.buffer); i++ {
+			ev := b.buffer[(b.start+i)%len(b.buffer)]
+			if ev.ID > lastID {
+				replay = append(replay, ev)
+			}
+		}
+		// The oldest event we could replay must follow lastID directly
+		complete = len(replay) > 0 && replay[0].ID == lastID+1
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	b.subscribers[ch] = struct{}{}
+
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return replay, complete, ch, cancel
+}
+
+var _ Publisher = (*Broadcaster)(nil)
@@ -0,0 +1,152 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// SMA is the simple moving average of closes
+type SMA struct {
+	win *window
+	sum float64
+}
+
+func NewSMA(period int) *SMA {
+	return &SMA{win: newWindow(period)}
+}
+
+func (s *SMA) Update(bar models.DailyBar) {
+	s.add(bar.Close)
+}
+
+func (s *SMA) add(v float64) {
+	full := s.win.full
+	old := s.win.push(v)
+	if full {
+		s.sum -= old
+	}
+	s.sum += v
+}
+
+func (s *SMA) Ready() bool { return s.win.full }
+
+func (s *SMA) value() float64 { return s.sum / float64(s.win.len()) }
+
+func (s *SMA) Values() map[string]float64 {
+	return map[string]float64{"value": s.value()}
+}
+
+// EMA is the exponential moving average of closes, seeded with the SMA of
+// the first period values
+type EMA struct {
+	period int
+	alpha  float64
+	count  int
+	sum    float64
+	val    float64
+}
+
+func NewEMA(period int) *EMA {
+	return &EMA{period: period, alpha: 2 / float64(period+1)}
+}
+
+func (e *EMA) Update(bar models.DailyBar) {
+	e.add(bar.Close)
+}
+
+func (e *EMA) add(v float64) {
+	e.count++
+	switch {
+	case e.count < e.period:
+		e.sum += v
+	case e.count == e.period:
+		e.sum += v
+		e.val = e.sum / float64(e.period)
+	default:
+		e.val += e.alpha * (v - e.val)
+	}
+}
+
+func (e *EMA) Ready() bool { return e.count >= e.period }
+
+func (e *EMA) Values() map[string]float64 {
+	return map[string]float64{"value": e.val}
+}
+
+// MACD is the difference of a fast and slow EMA of closes, with a signal
+// line EMA of that difference
+type MACD struct {
+	fast, slow *EMA
+	signal     *EMA
+}
+
+func NewMACD(fast, slow, signal int) *MACD {
+	return &MACD{fast: NewEMA(fast), slow: NewEMA(slow), signal: NewEMA(signal)}
+}
+
+func (m *MACD) Update(bar models.DailyBar) {
+	m.fast.add(bar.Close)
+	m.slow.add(bar.Close)
+	if m.slow.Ready() {
+		m.signal.add(m.fast.val - m.slow.val)
+	}
+}
+
+func (m *MACD) Ready() bool { return m.signal.Ready() }
+
+func (m *MACD) Values() map[string]float64 {
+	macd := m.fast.val - m.slow.val
+	return map[string]float64{
+		"macd":      macd,
+		"signal":    m.signal.val,
+		"histogram": macd - m.signal.val,
+	}
+}
+
+// Bollinger bands are an SMA of closes plus and minus k population
+// standard deviations
+type Bollinger struct {
+	sma *SMA
+	k   float64
+}
+
+func NewBollinger(period int, k float64) *Bollinger {
+	return &Bollinger{sma: NewSMA(period), k: k}
+}
+
+func (b *Bollinger) Update(bar models.DailyBar) {
+	b.sma.add(bar.Close)
+}
+
+func (b *Bollinger) Ready() bool { return b.sma.Ready() }
+
+func (b *Bollinger) Values() map[string]float64 {
+	mean := b.sma.value()
+	var variance float64
+	b.sma.win.each(func(v float64) {
+		variance += (v - mean) * (v - mean)
+	})
+	sd := math.Sqrt(variance / float64(b.sma.win.len()))
+
+	upper, lower := mean+b.k*sd, mean-b.k*sd
+	last := b.sma.win.values[(b.sma.win.next+len(b.sma.win.values)-1)%len(b.sma.win.values)]
+	percentB := 0.5
+	if upper > lower {
+		percentB = (last - lower) / (upper - lower)
+	}
+	return map[string]float64{
+		"upper":     upper,
+		"middle":    mean,
+		"lower":     lower,
+		"bandwidth": safeDiv(upper-lower, mean),
+		"percent_b": percentB,
+	}
+}
+
+func safeDiv(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
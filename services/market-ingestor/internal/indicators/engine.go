@@ -0,0 +1,273 @@
+package indicators
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/adjust"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// warmupBars is how much history seeds a symbol's indicators. EMA- and
+// Wilder-smoothed values depend on their seed, so this is well beyond the
+// longest default period to let them converge.
+const warmupBars = 400
+
+// History provides stored bars and the actions to adjust them by;
+// store.Store satisfies it
+type History interface {
+	GetBars(ctx context.Context, symbol string, from, to time.Time, limit int) ([]models.DailyBar, error)
+	GetCorporateActions(ctx context.Context, symbol string) ([]models.CorporateAction, error)
+}
+
+// Result holds a symbol's indicator values as of Date. A nil entry means
+// there is not yet enough history for that indicator.
+type Result struct {
+	Symbol     string                        `json:"symbol"`
+	Date       time.Time                     `json:"date"`
+	Indicators map[string]map[string]float64 `json:"indicators"`
+}
+
+// Engine caches indicator state per symbol. State is seeded from stored
+// history, split-adjusted, the first time a symbol is requested and then
+// advanced one bar at a time by Update after each ingest. Reset discards a
+// symbol's state when a new action changes how its history adjusts.
+type Engine struct {
+	history History
+	logger  *slog.Logger
+
+	mu      sync.Mutex
+	symbols map[string]*symbolState
+}
+
+// symbolState is one symbol's indicators as of lastDate; a zero lastDate
+// means the state must be seeded from history
+type symbolState struct {
+	mu         sync.Mutex
+	lastDate   time.Time
+	indicators map[string]Indicator
+}
+
+func (st *symbolState) reset() {
+	st.lastDate = time.Time{}
+	st.indicators = make(map[string]Indicator)
+}
+
+func NewEngine(history History, logger *slog.Logger) *Engine {
+	return &Engine{
+		history: history,
+		logger:  logger,
+		symbols: make(map[string]*symbolState),
+	}
+}
+
+// Get returns the latest values of the indicators named by specs. It
+// returns a nil result if the symbol has no stored bars.
+func (e *Engine) Get(ctx context.Context, symbol string, specs []string) (*Result, error) {
+	specs, err := normalizeSpecs(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := e.history.GetBars(ctx, symbol, time.Time{}, time.Time{}, 1)
+	if err != nil {
+		return nil, fmt.Errorf("loading latest bar for %s: %w", symbol, err)
+	}
+	if len(latest) == 0 {
+		return nil, nil
+	}
+	return e.values(ctx, symbol, latest[0].Date, specs)
+}
+
+// Latest returns indicator values for the symbol of bar, which must be its
+// most recent stored bar. It saves the store lookup Get makes, for callers
+// such as screens that already hold the latest bars.
+func (e *Engine) Latest(ctx context.Context, bar models.DailyBar, specs []string) (*Result, error) {
+	specs, err := normalizeSpecs(specs)
+	if err != nil {
+		return nil, err
+	}
+	return e.values(ctx, bar.Symbol, bar.Date, specs)
+}
+
+// Update advances cached symbols by one day of freshly saved bars. A
+// symbol whose state is not for the preceding trading day is reset and
+// reseeded on its next request.
+func (e *Engine) Update(date time.Time, bars []models.DailyBar) {
+	prev := calendar.PreviousTradingDay(date)
+
+	advanced, reset := 0, 0
+	for _, bar := range bars {
+		e.mu.Lock()
+		st, ok := e.symbols[bar.Symbol]
+		e.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		st.mu.Lock()
+		switch {
+		case st.lastDate.IsZero():
+		case sameDay(st.lastDate, prev):
+			for _, ind := range st.indicators {
+				ind.Update(bar)
+			}
+			st.lastDate = bar.Date
+			advanced++
+		default:
+			st.reset()
+			reset++
+		}
+		st.mu.Unlock()
+	}
+
+	if advanced > 0 || reset > 0 {
+		e.logger.Debug("updated indicators", "date", date.Format("2006-01-02"),
+			"advanced", advanced, "reset", reset)
+	}
+}
+
+// Reset discards the cached state of symbols, so each is reseeded from
+// adjusted history on its next request
+func (e *Engine) Reset(symbols ...string) {
+	reset := 0
+	for _, symbol := range symbols {
+		e.mu.Lock()
+		st, ok := e.symbols[symbol]
+		e.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		st.mu.Lock()
+		if len(st.indicators) > 0 {
+			reset++
+		}
+		st.reset()
+		st.mu.Unlock()
+	}
+
+	if reset > 0 {
+		e.logger.Debug("reset indicators", "symbols", reset)
+	}
+}
+
+func (e *Engine) state(symbol string) *symbolState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	st, ok := e.symbols[symbol]
+	if !ok {
+		st = &symbolState{}
+		st.reset()
+		e.symbols[symbol] = st
+	}
+	return st
+}
+
+func (e *Engine) values(ctx context.Context, symbol string, latest time.Time, specs []string) (*Result, error) {
+	st := e.state(symbol)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	// Reseed if the store moved on without us, e.g. a day was re-ingested
+	// or bars were saved before this engine was attached
+	if !st.lastDate.IsZero() && !sameDay(st.lastDate, latest) {
+		st.reset()
+	}
+
+	var missing []string
+	for _, spec := range specs {
+		if _, ok := st.indicators[spec]; !ok {
+			missing = append(missing, spec)
+		}
+	}
+	if len(missing) > 0 {
+		bars, err := e.history.GetBars(ctx, symbol, time.Time{}, latest, warmupBars)
+		if err != nil {
+			return nil, fmt.Errorf("loading history for %s: %w", symbol, err)
+		}
+		actions, err := e.history.GetCorporateActions(ctx, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("loading corporate actions for %s: %w", symbol, err)
+		}
+		bars = adjust.Bars(bars, actions, adjust.Split)
+		for _, spec := range missing {
+			ind, err := Parse(spec)
+			if err != nil {
+				return nil, err
+			}
+			for _, bar := range bars {
+				ind.Update(bar)
+			}
+			st.indicators[spec] = ind
+		}
+		if len(bars) > 0 {
+			st.lastDate = bars[len(bars)-1].Date
+		}
+	}
+
+	res := &Result{
+		Symbol:     symbol,
+		Date:       st.lastDate,
+		Indicators: make(map[string]map[string]float64, len(specs)),
+	}
+	for _, spec := range specs {
+		if ind := st.indicators[spec]; ind.Ready() {
+			res.Indicators[spec] = ind.Values()
+		} else {
+			res.Indicators[spec] = nil
+		}
+	}
+	return res, nil
+}
+
+// ParseSet validates a comma-separated list of indicator specs, returning
+// DefaultSet when the list is empty
+func ParseSet(set string) ([]string, error) {
+	var specs []string
+	seen := make(map[string]bool)
+	for _, raw := range strings.Split(set, ",") {
+		raw = normalize(raw)
+		if raw == "" {
+			continue
+		}
+		if _, err := Parse(raw); err != nil {
+			return nil, err
+		}
+		if !seen[raw] {
+			seen[raw] = true
+			specs = append(specs, raw)
+		}
+	}
+	if len(specs) == 0 {
+		return DefaultSet, nil
+	}
+	if len(specs) > 32 {
+		return nil, fmt.Errorf("at most 32 indicators may be requested, got %d", len(specs))
+	}
+	return specs, nil
+}
+
+// normalizeSpecs validates specs and returns them in canonical form
+func normalizeSpecs(specs []string) ([]string, error) {
+	out := make([]string, len(specs))
+	for i, spec := range specs {
+		if _, err := Parse(spec); err != nil {
+			return nil, err
+		}
+		out[i] = normalize(spec)
+	}
+	return out, nil
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,163 @@
+// Package indicators computes technical indicators over daily bars. Every
+// indicator is incremental: it consumes one bar at a time, so values can be
+// advanced after each EOD ingest without recomputing from full history.
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// Indicator is an incrementally updated technical indicator
+type Indicator interface {
+	// Update advances the indicator by one bar; bars must arrive in date order
+	Update(bar models.DailyBar)
+	// Ready reports whether enough bars have been seen to produce values
+	Ready() bool
+	// Values returns the current outputs keyed by name. Single-output
+	// indicators use the key "value".
+	Values() map[string]float64
+}
+
+// DefaultSet is used when a request names no indicators
+var DefaultSet = []string{"sma20", "sma50", "sma200", "ema20", "rsi14", "macd", "bb20", "atr14", "adx14", "obv", "stoch14"}
+
+// ErrUnknown is returned by Parse for a spec that names no indicator
+var ErrUnknown = errors.New("unknown indicator")
+
+// maxPeriod bounds indicator parameters to about a year of sessions
+const maxPeriod = 250
+
+var specRe = regexp.MustCompile(`^([a-z]+)(\d+(?:_\d+)*)?$`)
+
+// maxParams is how many parameters each indicator takes
+var maxParams = map[string]int{
+	"sma": 1, "ema": 1, "rsi": 1, "macd": 3, "bb": 2, "atr": 1, "adx": 1, "obv": 0, "stoch": 2,
+}
+
+// Parse builds an indicator from a spec such as "rsi14", "sma50",
+// "macd12_26_9", "bb20_2", "stoch14_3" or "obv". Omitted parameters take
+// their conventional defaults. It returns either an indicator or an error,
+// never both.
+func Parse(spec string) (Indicator, error) {
+	m := specRe.FindStringSubmatch(normalize(spec))
+	if m == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknown, spec)
+	}
+	limit, ok := maxParams[m[1]]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknown, spec)
+	}
+
+	var params []int
+	if m[2] != "" {
+		for _, p := range strings.Split(m[2], "_") {
+			n, err := strconv.Atoi(p)
+			if err != nil || n <= 0 || n > maxPeriod {
+				return nil, fmt.Errorf("invalid parameter in indicator %q", spec)
+			}
+			params = append(params, n)
+		}
+	}
+	if len(params) > limit {
+		return nil, fmt.Errorf("indicator %q takes at most %d parameters", spec, limit)
+	}
+	param := func(i, fallback int) int {
+		if i < len(params) {
+			return params[i]
+		}
+		return fallback
+	}
+
+	switch m[1] {
+	case "sma":
+		if len(params) != 1 {
+			return nil, fmt.Errorf("indicator %q needs a period, e.g. sma50", spec)
+		}
+		return NewSMA(params[0]), nil
+	case "ema":
+		if len(params) != 1 {
+			return nil, fmt.Errorf("indicator %q needs a period, e.g. ema20", spec)
+		}
+		return NewEMA(params[0]), nil
+	case "rsi":
+		return NewRSI(param(0, 14)), nil
+	case "macd":
+		fast, slow := param(0, 12), param(1, 26)
+		if fast >= slow {
+			return nil, fmt.Errorf("indicator %q: fast period must be shorter than slow", spec)
+		}
+		return NewMACD(fast, slow, param(2, 9)), nil
+	case "bb":
+		return NewBollinger(param(0, 20), float64(param(1, 2))), nil
+	case "atr":
+		return NewATR(param(0, 14)), nil
+	case "adx":
+		return NewADX(param(0, 14)), nil
+	case "obv":
+		return NewOBV(), nil
+	case "stoch":
+		return NewStochastic(param(0, 14), param(1, 3)), nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknown, spec)
+}
+
+// Compute runs the indicator described by spec over bars (oldest first) and
+// returns its latest values, or nil if there is not enough history
+func Compute(spec string, bars []models.DailyBar) (map[string]float64, error) {
+	ind, err := Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	for _, bar := range bars {
+		ind.Update(bar)
+	}
+	if !ind.Ready() {
+		return nil, nil
+	}
+	return ind.Values(), nil
+}
+
+func normalize(spec string) string {
+	return strings.ToLower(strings.TrimSpace(spec))
+}
+
+// window is a fixed-size ring buffer of the most recent values
+type window struct {
+	values []float64
+	next   int
+	full   bool
+}
+
+func newWindow(size int) *window {
+	return &window{values: make([]float64, size)}
+}
+
+// push adds v, returning the value it evicted (zero until the window is full)
+func (w *window) push(v float64) float64 {
+	old := w.values[w.next]
+	w.values[w.next] = v
+	w.next = (w.next + 1) % len(w.values)
+	if w.next == 0 {
+		w.full = true
+	}
+	return old
+}
+
+func (w *window) len() int {
+	if w.full {
+		return len(w.values)
+	}
+	return w.next
+}
+
+func (w *window) each(fn func(v float64)) {
+	for i := 0; i < w.len(); i++ {
+		fn(w.values[i])
+	}
+}
@@ -0,0 +1,125 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// testBars is a deterministic 60-session series that trends up through a
+// few swings, with ranges and volume that vary from bar to bar
+func testBars() []models.DailyBar {
+	bars := make([]models.DailyBar, 60)
+	for i := range bars {
+		c := 100 + 10*math.Sin(float64(i)/5) + 0.3*float64(i)
+		bars[i] = models.DailyBar{
+			Open:   c,
+			High:   c + 1 + float64(i%3)*0.5,
+			Low:    c - 1 - float64(i%4)*0.4,
+			Close:  c,
+			Volume: int64(1000 + 100*(i%7)),
+		}
+	}
+	return bars
+}
+
+// TestReferenceValues checks each indicator's latest values over testBars
+// against a textbook batch implementation: Wilder's running sums for ATR
+// and ADX, SMA-seeded EMAs for MACD and population deviation for Bollinger
+func TestReferenceValues(t *testing.T) {
+	tests := []struct {
+		spec string
+		want map[string]float64
+	}{
+		{"sma50", map[string]float64{"value": 109.2387890445}},
+		{"ema20", map[string]float64{"value": 110.5110228795}},
+		{"macd", map[string]float64{"macd": -1.1491258002, "signal": -0.6309636013, "histogram": -0.5181621990}},
+		{"bb20", map[string]float64{"upper": 123.8946363061, "middle": 112.7663479281, "lower": 101.6380595500}},
+		{"atr14", map[string]float64{"value": 3.2656438294}},
+		{"adx14", map[string]float64{"adx": 26.2090620273, "plus_di": 19.8009379805, "minus_di": 17.5811712183}},
+		{"stoch14", map[string]float64{"k": 48.8680048692, "d": 34.8061065353}},
+		{"obv", map[string]float64{"value": 3600}},
+	}
+	bars := testBars()
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := Compute(tt.spec, bars)
+			if err != nil {
+				t.Fatalf("Compute: %v", err)
+			}
+			for key, want := range tt.want {
+				if math.Abs(got[key]-want) > 1e-6 {
+					t.Errorf("%s = %.10f, want %.10f", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+// TestRSI follows Wilder's RSI through the closes of StockCharts' RSI
+// worksheet. Its published figures round the averages at each step and so
+// differ in the second decimal; these are at full precision.
+func TestRSI(t *testing.T) {
+	closes := []float64{
+		44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08, 45.89,
+		46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64, 46.21, 46.25,
+		45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57, 43.42, 42.66, 43.13,
+	}
+	want := []float64{
+		70.46, 66.25, 66.48, 69.35, 66.29, 57.92, 62.88, 63.21, 56.01, 62.34,
+		54.67, 50.39, 40.02, 41.49, 41.90, 45.50, 37.32, 33.09, 37.79,
+	}
+
+	rsi := NewRSI(14)
+	for i, c := range closes {
+		rsi.Update(models.DailyBar{Close: c})
+		if i < 14 {
+			if rsi.Ready() {
+				t.Fatalf("ready after %d closes", i+1)
+			}
+			continue
+		}
+		if got := rsi.Values()["value"]; math.Abs(got-want[i-14]) > 0.005 {
+			t.Errorf("RSI after close %d = %.4f, want %.2f", i+1, got, want[i-14])
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		ok      bool
+		unknown bool
+	}{
+		{"rsi14", true, false},
+		{" RSI ", true, false},
+		{"macd12_26_9", true, false},
+		{"stoch14_3", true, false},
+		{"obv", true, false},
+		{"sma", false, false},
+		{"rsi0", false, false},
+		{"rsi251", false, false},
+		{"rsi14_3", false, false},
+		{"obv1", false, false},
+		{"macd26_12", false, false},
+		{"vwap20", false, true},
+		{"rsi-14", false, true},
+	}
+	for _, tt := range tests {
+		ind, err := Parse(tt.spec)
+		if tt.ok {
+			if err != nil || ind == nil {
+				t.Errorf("Parse(%q) = %v, %v; want an indicator", tt.spec, ind, err)
+			}
+			continue
+		}
+		if err == nil || ind != nil {
+			t.Errorf("Parse(%q) = %v, %v; want only an error", tt.spec, ind, err)
+		}
+		if errors.Is(err, ErrUnknown) != tt.unknown {
+			t.Errorf("Parse(%q) error %v, ErrUnknown = %v", tt.spec, err, tt.unknown)
+		}
+	}
+}
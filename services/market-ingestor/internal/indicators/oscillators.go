@@ -0,0 +1,159 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// wilder is Wilder's smoothed average: the plain mean of the first period
+// values, then avg = (avg*(period-1) + v) / period
+type wilder struct {
+	period int
+	count  int
+	val    float64
+}
+
+func (w *wilder) add(v float64) {
+	w.count++
+	if w.count <= w.period {
+		w.val += (v - w.val) / float64(w.count)
+		return
+	}
+	w.val = (w.val*float64(w.period-1) + v) / float64(w.period)
+}
+
+func (w *wilder) ready() bool { return w.count >= w.period }
+
+// RSI is Wilder's relative strength index of closes
+type RSI struct {
+	gain, loss wilder
+	prev       float64
+	started    bool
+}
+
+func NewRSI(period int) *RSI {
+	return &RSI{gain: wilder{period: period}, loss: wilder{period: period}}
+}
+
+func (r *RSI) Update(bar models.DailyBar) {
+	if r.started {
+		change := bar.Close - r.prev
+		r.gain.add(math.Max(change, 0))
+		r.loss.add(math.Max(-change, 0))
+	}
+	r.prev = bar.Close
+	r.started = true
+}
+
+func (r *RSI) Ready() bool { return r.gain.ready() }
+
+func (r *RSI) Values() map[string]float64 {
+	if r.loss.val == 0 {
+		if r.gain.val == 0 {
+			return map[string]float64{"value": 50}
+		}
+		return map[string]float64{"value": 100}
+	}
+	rs := r.gain.val / r.loss.val
+	return map[string]float64{"value": 100 - 100/(1+rs)}
+}
+
+// Stochastic is the %K position of the close within the high-low range of
+// the last k bars, with %D its d-bar simple average
+type Stochastic struct {
+	highs, lows *window
+	d           *SMA
+	k           float64
+}
+
+func NewStochastic(k, d int) *Stochastic {
+	return &Stochastic{highs: newWindow(k), lows: newWindow(k), d: NewSMA(d)}
+}
+
+func (s *Stochastic) Update(bar models.DailyBar) {
+	s.highs.push(bar.High)
+	s.lows.push(bar.Low)
+	if !s.highs.full {
+		return
+	}
+
+	highest, lowest := math.Inf(-1), math.Inf(1)
+	s.highs.each(func(v float64) { highest = math.Max(highest, v) })
+	s.lows.each(func(v float64) { lowest = math.Min(lowest, v) })
+
+	s.k = 50
+	if highest > lowest {
+		s.k = 100 * (bar.Close - lowest) / (highest - lowest)
+	}
+	s.d.add(s.k)
+}
+
+func (s *Stochastic) Ready() bool { return s.d.Ready() }
+
+func (s *Stochastic) Values() map[string]float64 {
+	return map[string]float64{"k": s.k, "d": s.d.value()}
+}
+
+// ADX is Wilder's average directional index, with the +DI and -DI lines
+type ADX struct {
+	period              int
+	tr, plusDM, minusDM wilder
+	adx                 wilder
+	prev                models.DailyBar
+	started             bool
+	plusDI, minusDI     float64
+}
+
+func NewADX(period int) *ADX {
+	return &ADX{
+		period:  period,
+		tr:      wilder{period: period},
+		plusDM:  wilder{period: period},
+		minusDM: wilder{period: period},
+		adx:     wilder{period: period},
+	}
+}
+
+func (a *ADX) Update(bar models.DailyBar) {
+	if !a.started {
+		a.prev, a.started = bar, true
+		return
+	}
+
+	up := bar.High - a.prev.High
+	down := a.prev.Low - bar.Low
+	var plus, minus float64
+	if up > down && up > 0 {
+		plus = up
+	}
+	if down > up && down > 0 {
+		minus = down
+	}
+
+	a.tr.add(trueRange(bar, a.prev.Close))
+	a.plusDM.add(plus)
+	a.minusDM.add(minus)
+	a.prev = bar
+
+	if !a.tr.ready() || a.tr.val == 0 {
+		return
+	}
+	a.plusDI = 100 * a.plusDM.val / a.tr.val
+	a.minusDI = 100 * a.minusDM.val / a.tr.val
+	dx := 0.0
+	if sum := a.plusDI + a.minusDI; sum > 0 {
+		dx = 100 * math.Abs(a.plusDI-a.minusDI) / sum
+	}
+	a.adx.add(dx)
+}
+
+func (a *ADX) Ready() bool { return a.adx.ready() }
+
+func (a *ADX) Values() map[string]float64 {
+	return map[string]float64{
+		"adx":      a.adx.val,
+		"plus_di":  a.plusDI,
+		"minus_di": a.minusDI,
+	}
+}
@@ -0,0 +1,70 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// trueRange is the greatest of the bar's range and its gaps from prevClose
+func trueRange(bar models.DailyBar, prevClose float64) float64 {
+	return math.Max(bar.High-bar.Low, math.Max(math.Abs(bar.High-prevClose), math.Abs(bar.Low-prevClose)))
+}
+
+// ATR is Wilder's average true range
+type ATR struct {
+	avg     wilder
+	prev    float64
+	started bool
+}
+
+func NewATR(period int) *ATR {
+	return &ATR{avg: wilder{period: period}}
+}
+
+func (a *ATR) Update(bar models.DailyBar) {
+	if a.started {
+		a.avg.add(trueRange(bar, a.prev))
+	} else {
+		a.avg.add(bar.High - bar.Low)
+	}
+	a.prev = bar.Close
+	a.started = true
+}
+
+func (a *ATR) Ready() bool { return a.avg.ready() }
+
+func (a *ATR) Values() map[string]float64 {
+	return map[string]float64{"value": a.avg.val}
+}
+
+// OBV is on-balance volume: volume added on up closes and subtracted on
+// down closes, starting from zero at the first bar seen
+type OBV struct {
+	val     float64
+	prev    float64
+	started bool
+}
+
+func NewOBV() *OBV {
+	return &OBV{}
+}
+
+func (o *OBV) Update(bar models.DailyBar) {
+	if o.started {
+		switch {
+		case bar.Close > o.prev:
+			o.val += float64(bar.Volume)
+		case bar.Close < o.prev:
+			o.val -= float64(bar.Volume)
+		}
+	}
+	o.prev = bar.Close
+	o.started = true
+}
+
+func (o *OBV) Ready() bool { return o.started }
+
+func (o *OBV) Values() map[string]float64 {
+	return map[string]float64{"value": o.val}
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Corporate action types
+const (
+	ActionSplit    = "split"
+	ActionDividend = "dividend"
+)
+
+// CorporateAction is a split or cash dividend. PriceFactor is what prices
+// before ExDate are multiplied by to be comparable with prices from ExDate
+// on: SplitFrom/SplitTo for a split, 1 - CashAmount/prior close for a
+// dividend. It is zero until it can be computed, e.g. while the prior
+// close has not been stored.
+type CorporateAction struct {
+	ID     string    `json:"id"`
+	Symbol string    `json:"symbol"`
+	Type   string    `json:"type"`
+	ExDate time.Time `json:"ex_date"`
+	// Splits: SplitTo new shares for every SplitFrom old ones
+	SplitFrom float64 `json:"split_from,omitempty"`
+	SplitTo   float64 `json:"split_to,omitempty"`
+	// Dividends
+	CashAmount   float64    `json:"cash_amount,omitempty"`
+	Currency     string     `json:"currency,omitempty"`
+	PayDate      *time.Time `json:"pay_date,omitempty"`
+	DividendType string     `json:"dividend_type,omitempty"` // Polygon code: CD, SC, LT, ST
+	PriceFactor  float64    `json:"price_factor"`
+}
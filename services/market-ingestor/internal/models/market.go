@@ -2,18 +2,62 @@ package models
 
 import "time"
 
+// ChangeBasis records which reference price a bar's change was computed against
+type ChangeBasis string
+
+const (
+	// ChangeBasisPrevClose means change is measured from the prior trading day's close
+	ChangeBasisPrevClose ChangeBasis = "prev_close"
+	// ChangeBasisOpen means no prior close was available and the same-day open was used
+	ChangeBasisOpen ChangeBasis = "open"
+	// ChangeBasisNone means no reference price was available and change is zero
+	ChangeBasisNone ChangeBasis = "none"
+)
+
 // DailyBar represents OHLCV data for a single trading day
 type DailyBar struct {
-	Symbol    string    `json:"symbol"`
-	Open      float64   `json:"open"`
-	High      float64   `json:"high"`
-	Low       float64   `json:"low"`
-	Close     float64   `json:"close"`
-	Volume    int64     `json:"volume"`
-	VWAP      float64   `json:"vwap"`
-	Date      time.Time `json:"date"`
-	Change    float64   `json:"change"`
-	ChangePct float64   `json:"change_pct"`
+	Symbol      string      `json:"symbol"`
+	Open        float64     `json:"open"`
+	High        float64     `json:"high"`
+	Low         float64     `json:"low"`
+	Close       float64     `json:"close"`
+	Volume      int64       `json:"volume"`
+	VWAP        float64     `json:"vwap"`
+	Date        time.Time   `json:"date"`
+	PrevClose   float64     `json:"prev_close"`
+	Change      float64     `json:"change"`
+	ChangePct   float64     `json:"change_pct"`
+	ChangeBasis ChangeBasis `json:"change_basis"`
+	// Average volume over the prior 20 and 50 sessions, and today's volume
+	// relative to the 20-session average; zero until enough history exists
+	AvgVolume20 int64   `json:"avg_volume_20"`
+	AvgVolume50 int64   `json:"avg_volume_50"`
+	VolumeRatio float64 `json:"volume_ratio"`
+}
+
+// Aggregate is an OHLCV bar over an arbitrary interval starting at Timestamp
+type Aggregate struct {
+	Symbol       string    `json:"symbol"`
+	Timestamp    time.Time `json:"timestamp"`
+	Open         float64   `json:"open"`
+	High         float64   `json:"high"`
+	Low          float64   `json:"low"`
+	Close        float64   `json:"close"`
+	Volume       int64     `json:"volume"`
+	VWAP         float64   `json:"vwap"`
+	Transactions int       `json:"transactions"`
+}
+
+// Ticker is reference data describing a listed security
+type Ticker struct {
+	Symbol          string `json:"symbol"`
+	Name            string `json:"name"`
+	Type            string `json:"type"` // Polygon type code: CS, ETF, ADRC, WARRANT...
+	PrimaryExchange string `json:"primary_exchange"`
+	CIK             string `json:"cik,omitempty"`
+	Active          bool   `json:"active"`
+	Currency        string `json:"currency"`
+	Sector          string `json:"sector,omitempty"`
 }
 
 // IndexData represents major index ETF data
@@ -33,15 +77,45 @@ type ScreenerResult struct {
 	Change      float64 `json:"change"`
 	ChangePct   float64 `json:"change_pct"`
 	Volume      int64   `json:"volume"`
-	AvgVolume   int64   `json:"avg_volume"`
+	AvgVolume   int64   `json:"avg_volume"` // 20-session average
+	AvgVolume50 int64   `json:"avg_volume_50"`
 	VolumeRatio float64 `json:"volume_ratio"`
 }
 
+// ScreenerFilter narrows screener lists to tradable, liquid securities.
+// Zero values disable the corresponding check.
+type ScreenerFilter struct {
+	// Types are Polygon security type codes to include (CS, ETF, ADRC...).
+	// Symbols without ticker reference data are included regardless.
+	Types           []string `json:"types,omitempty"`
+	MinPrice        float64  `json:"min_price,omitempty"`
+	MinVolume       int64    `json:"min_volume,omitempty"`
+	MinDollarVolume float64  `json:"min_dollar_volume,omitempty"`
+}
+
 // MarketSummary contains aggregated market data
 type MarketSummary struct {
-	Date       time.Time       `json:"date"`
-	Indices    []IndexData     `json:"indices"`
+	Date       time.Time        `json:"date"`
+	Indices    []IndexData      `json:"indices"`
 	TopGainers []ScreenerResult `json:"top_gainers"`
 	TopLosers  []ScreenerResult `json:"top_losers"`
 	MostActive []ScreenerResult `json:"most_active"`
 }
+
+// Snapshot is a symbol's trading session so far, built from streamed
+// minute bars
+type Snapshot struct {
+	Symbol    string    `json:"symbol"`
+	Date      time.Time `json:"date"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Last      float64   `json:"last"`
+	Volume    int64     `json:"volume"`
+	VWAP      float64   `json:"vwap"`
+	PrevClose float64   `json:"prev_close"`
+	Change    float64   `json:"change"`
+	ChangePct float64   `json:"change_pct"`
+	// UpdatedAt is the end of the latest minute bar applied
+	UpdatedAt time.Time `json:"updated_at"`
+}
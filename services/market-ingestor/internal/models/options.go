@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OptionType is a contract's right
+type OptionType string
+
+const (
+	Call OptionType = "CALL"
+	Put  OptionType = "PUT"
+)
+
+// OptionSide is where in the spread a trade printed: at the ask it was
+// bought, at the bid it was sold
+type OptionSide string
+
+const (
+	SideAsk OptionSide = "ASK"
+	SideBid OptionSide = "BID"
+	SideMid OptionSide = "MID"
+)
+
+// TradeType is how an order was executed
+type TradeType string
+
+const (
+	// TradeSweep is an order swept across exchanges to fill at once
+	TradeSweep TradeType = "SWEEP"
+	// TradeBlock is a single large print
+	TradeBlock TradeType = "BLOCK"
+	// TradeSplit is an order filled in several prints on one exchange
+	TradeSplit TradeType = "SPLIT"
+)
+
+// FlowDirection is the stance options flow implies for the underlying
+type FlowDirection string
+
+const (
+	FlowBullish FlowDirection = "BULLISH"
+	FlowBearish FlowDirection = "BEARISH"
+)
+
+// OptionsFlow is an unusual options order that passed the flow rules
+type OptionsFlow struct {
+	ID string `json:"id"`
+	// AlertID is the provider's ID for the order, so repeated polls store
+	// it once
+	AlertID      string     `json:"alert_id,omitempty"`
+	Timestamp    time.Time  `json:"timestamp"`
+	Ticker       string     `json:"ticker"`
+	Expiration   time.Time  `json:"expiration"`
+	Strike       float64    `json:"strike"`
+	OptionType   OptionType `json:"option_type"`
+	Side         OptionSide `json:"side"`
+	TradeType    TradeType  `json:"trade_type"`
+	Premium      float64    `json:"premium"`
+	Volume       int64      `json:"volume"`
+	OpenInterest int64      `json:"open_interest"`
+	// DTE is calendar days from the trade's session to expiration
+	DTE int `json:"dte"`
+	// Direction is empty when the side does not reveal one
+	Direction FlowDirection   `json:"direction,omitempty"`
+	RawData   json.RawMessage `json:"raw_data,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// OptionsFlowFilter narrows an options flow query. Zero fields match
+// everything.
+type OptionsFlowFilter struct {
+	Ticker    string
+	Direction FlowDirection
+	// Since excludes flow timestamped before it
+	Since time.Time
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// SavedScreen is a named custom screen, evaluated after each EOD ingest
+type SavedScreen struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Filter      string    `json:"filter"`
+	Sort        string    `json:"sort,omitempty"`
+	Order       string    `json:"order,omitempty"`
+	Limit       int       `json:"limit,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ScreenResult is the symbols a saved screen matched on one date, and how
+// they changed since its previous evaluation
+type ScreenResult struct {
+	ScreenID string    `json:"screen_id"`
+	Date     time.Time `json:"date"`
+	// PreviousDate is the evaluation Entered and Exited compare against;
+	// zero on a screen's first evaluation
+	PreviousDate time.Time `json:"previous_date,omitempty"`
+	// Symbols are every match, not only those within the screen's limit
+	Symbols     []string  `json:"symbols"`
+	Entered     []string  `json:"entered"`
+	Exited      []string  `json:"exited"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+}
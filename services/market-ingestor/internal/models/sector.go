@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// RotationSignal classifies which kind of sectors are leading the market
+type RotationSignal string
+
+const (
+	// RiskOn means cyclical sectors lead and defensive ones lag
+	RiskOn RotationSignal = "RISK_ON"
+	// RiskOff means defensive sectors lead and cyclical ones lag
+	RiskOff RotationSignal = "RISK_OFF"
+	// RiskNeutral means leadership is mixed
+	RiskNeutral RotationSignal = "NEUTRAL"
+)
+
+// SectorData is a sector ETF's session alongside how it did against SPY
+type SectorData struct {
+	Symbol    string    `json:"symbol"`
+	Name      string    `json:"name"`
+	Date      time.Time `json:"date"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    int64     `json:"volume"`
+	ChangePct float64   `json:"change_pct"`
+	// RelativeStrength is ChangePct minus SPY's, in percentage points
+	RelativeStrength float64 `json:"relative_strength"`
+	// VolumeRatio is volume relative to the 20-session average; zero until
+	// enough history exists
+	VolumeRatio float64 `json:"volume_ratio"`
+}
+
+// SectorRotation is one session's rotation signal and the sectors it was
+// derived from, strongest leader and weakest laggard first
+type SectorRotation struct {
+	Date    time.Time      `json:"date"`
+	Signal  RotationSignal `json:"signal"`
+	Leading []string       `json:"leading_sectors"`
+	Lagging []string       `json:"lagging_sectors"`
+	Notes   string         `json:"notes,omitempty"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SignalSource names the detector or tool that raised a signal. A source
+// keeps at most one signal per ticker and session.
+type SignalSource string
+
+const (
+	// SourceGap is a session opening away from the prior close
+	SourceGap SignalSource = "gap"
+	// SourceBreakout is a close beyond the prior 20-session range
+	SourceBreakout SignalSource = "breakout"
+	// SourceUnusualVolume is volume well above the 20-session average
+	SourceUnusualVolume SignalSource = "unusual_volume"
+	// SourceNewHigh is a new 52-week high
+	SourceNewHigh SignalSource = "new_high"
+	// SourceOptionsFlow is a large directional options order
+	SourceOptionsFlow SignalSource = "options_flow"
+)
+
+// SignalDirection is the move a signal points to
+type SignalDirection string
+
+const (
+	Bullish SignalDirection = "bullish"
+	Bearish SignalDirection = "bearish"
+	Neutral SignalDirection = "neutral"
+)
+
+// SignalStrength grades a signal from 1 (weak) to 4 (very strong)
+type SignalStrength int
+
+const (
+	StrengthWeak SignalStrength = iota + 1
+	StrengthModerate
+	StrengthStrong
+	StrengthVeryStrong
+)
+
+// Signal is one tool's call on a ticker, or on the whole market when
+// Ticker is empty, in the format every tool writes to the signals table
+type Signal struct {
+	ID        string       `json:"id"`
+	Source    SignalSource `json:"source"`
+	Timestamp time.Time    `json:"timestamp"`
+	// Date is the trading session the signal belongs to, its Timestamp's
+	// New York calendar date
+	Date       time.Time       `json:"date"`
+	Ticker     string          `json:"ticker,omitempty"`
+	Direction  SignalDirection `json:"direction"`
+	Strength   SignalStrength  `json:"strength"`
+	Confidence float64         `json:"confidence"` // 0-1
+	Tags       []string        `json:"tags"`
+	// RawData is the detector's inputs, as JSON
+	RawData   json.RawMessage `json:"raw_data,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// SignalFilter narrows a signals query. Zero fields match everything.
+type SignalFilter struct {
+	Source    SignalSource
+	Ticker    string
+	Direction SignalDirection
+	// Since excludes signals timestamped before it
+	Since time.Time
+	// Before and BeforeID resume a newest-first listing after the signal
+	// with that timestamp and ID
+	Before   time.Time
+	BeforeID string
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// StrengthScore is a ticker's composite strength on a date and where it
+// ranked among every ticker scored that day (1 is strongest)
+type StrengthScore struct {
+	Ticker      string             `json:"ticker"`
+	Date        time.Time          `json:"date"`
+	Composite   float64            `json:"composite_score"` // 0-100
+	Components  StrengthComponents `json:"components"`
+	Rank        int                `json:"rank"`
+	TotalRanked int                `json:"total_ranked"`
+}
+
+// StrengthComponents are the measures a composite score combines, computed
+// on split-adjusted closes
+type StrengthComponents struct {
+	// Percent change over the last 1, 5 and 20 sessions
+	Momentum1D  float64 `json:"momentum_1d"`
+	Momentum5D  float64 `json:"momentum_5d"`
+	Momentum20D float64 `json:"momentum_20d"`
+	// RSVsSPY is Momentum20D minus SPY's, in percentage points
+	RSVsSPY float64 `json:"rs_vs_spy"`
+	// VolumeTrend is average volume over 5 sessions relative to 20
+	VolumeTrend float64 `json:"volume_trend"`
+	// MAAlignment is the share (0-100) of close > SMA20 > SMA50 > SMA200
+	// orderings that hold, among those with enough history
+	MAAlignment float64 `json:"ma_alignment"`
+	// HighLowPosition is where the close sits (0-100) in its 52-week range
+	HighLowPosition float64 `json:"high_low_position"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Watchlist is a named list of tickers
+type Watchlist struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Items       []WatchlistItem `json:"items"` // ordered by ticker
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// WatchlistItem is a ticker on a watchlist
+type WatchlistItem struct {
+	Ticker  string    `json:"ticker"`
+	Notes   string    `json:"notes,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// WatchlistQuote is a watchlist item with its latest stored bar, which
+// carries the day's change and volume ratio (RVOL), and its latest strength
+// score. Either is nil when the ticker has none.
+type WatchlistQuote struct {
+	WatchlistItem
+	Bar      *DailyBar      `json:"bar"`
+	Strength *StrengthScore `json:"strength"`
+}
@@ -0,0 +1,160 @@
+package optionsflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/signals"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/unusualwhales"
+)
+
+// defaultLimit is how many of the latest alerts each poll fetches
+const defaultLimit = 200
+
+// Store is the storage an Ingestor saves to; store.Store implements it
+type Store interface {
+	// SaveOptionsFlow stores flow not already stored under the same alert
+	// ID and returns what it stored
+	SaveOptionsFlow(ctx context.Context, flows []models.OptionsFlow) ([]models.OptionsFlow, error)
+}
+
+// Options configures an Ingestor
+type Options struct {
+	// Rules filter the alerts fetched; the zero value uses DefaultRules
+	Rules Rules
+	// Limit is how many of the latest alerts each poll fetches (default 200)
+	Limit  int
+	Logger *slog.Logger
+}
+
+// Ingestor polls flow alerts, stores those passing its rules and signals
+// the directional ones
+type Ingestor struct {
+	client  *unusualwhales.Client
+	store   Store
+	signals *signals.Writer
+	rules   Rules
+	limit   int
+	logger  *slog.Logger
+}
+
+func NewIngestor(client *unusualwhales.Client, store Store, writer *signals.Writer, opts Options) *Ingestor {
+	if opts.Rules == (Rules{}) {
+		opts.Rules = DefaultRules()
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultLimit
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return &Ingestor{
+		client:  client,
+		store:   store,
+		signals: writer,
+		rules:   opts.Rules,
+		limit:   opts.Limit,
+		logger:  opts.Logger,
+	}
+}
+
+// Poll fetches the latest flow alerts and stores those passing the rules
+// that were not stored by an earlier poll, writing an options_flow signal
+// for each with a direction. It returns how many were stored.
+func (in *Ingestor) Poll(ctx context.Context) (int, error) {
+	alerts, err := in.client.GetFlowAlerts(ctx, in.limit)
+	if err != nil {
+		return 0, fmt.Errorf("fetching flow alerts: %w", err)
+	}
+
+	flows := make([]models.OptionsFlow, 0, len(alerts))
+	for _, alert := range alerts {
+		flow, err := FromAlert(alert)
+		if err != nil {
+			in.logger.Warn("skipping malformed flow alert", "error", err)
+			continue
+		}
+		if ok, reason := in.rules.Accept(flow); !ok {
+			in.logger.Debug("flow alert filtered", "id", alert.ID, "ticker", flow.Ticker, "reason", reason)
+			continue
+		}
+		flows = append(flows, flow)
+	}
+
+	saved, err := in.store.SaveOptionsFlow(ctx, flows)
+	if err != nil {
+		return 0, fmt.Errorf("saving options flow: %w", err)
+	}
+
+	sigs := make([]models.Signal, 0, len(saved))
+	for _, flow := range saved {
+		if sig, ok := Signal(flow); ok {
+			sigs = append(sigs, sig)
+		}
+	}
+	if _, err := in.signals.Write(ctx, sigs); err != nil {
+		return len(saved), fmt.Errorf("writing options flow signals: %w", err)
+	}
+
+	if len(saved) > 0 {
+		in.logger.Info("ingested options flow", "alerts", len(alerts), "accepted", len(flows),
+			"stored", len(saved), "signals", len(sigs))
+	}
+	return len(saved), nil
+}
+
+// Signal converts flow with a direction to a signal, graded by premium.
+// Sweeps, which trade urgency for price, and orders dwarfing open interest
+// raise confidence.
+func Signal(flow models.OptionsFlow) (models.Signal, bool) {
+	sig := models.Signal{
+		Source:     models.SourceOptionsFlow,
+		Timestamp:  flow.Timestamp,
+		Ticker:     flow.Ticker,
+		Confidence: 0.4,
+		Tags: []string{
+			strings.ToLower(string(flow.TradeType)),
+			strings.ToLower(string(flow.OptionType)),
+			strings.ToLower(string(flow.Side)) + "_side",
+		},
+	}
+	switch flow.Direction {
+	case models.FlowBullish:
+		sig.Direction = models.Bullish
+	case models.FlowBearish:
+		sig.Direction = models.Bearish
+	default:
+		return models.Signal{}, false
+	}
+
+	switch {
+	case flow.Premium >= 5_000_000:
+		sig.Strength = models.StrengthVeryStrong
+	case flow.Premium >= 1_000_000:
+		sig.Strength = models.StrengthStrong
+	case flow.Premium >= 250_000:
+		sig.Strength = models.StrengthModerate
+	default:
+		sig.Strength = models.StrengthWeak
+	}
+
+	if flow.TradeType == models.TradeSweep {
+		sig.Confidence += 0.2
+	}
+	if flow.OpenInterest == 0 || flow.Volume >= 2*flow.OpenInterest {
+		sig.Confidence += 0.2
+		sig.Tags = append(sig.Tags, "opening")
+	}
+
+	sig.RawData, _ = json.Marshal(map[string]any{
+		"alert_id": flow.AlertID, "option_type": flow.OptionType, "strike": flow.Strike,
+		"expiration": flow.Expiration.Format("2006-01-02"), "dte": flow.DTE,
+		"premium": flow.Premium, "volume": flow.Volume, "open_interest": flow.OpenInterest,
+		"side": flow.Side, "trade_type": flow.TradeType,
+	})
+	return sig, true
+}
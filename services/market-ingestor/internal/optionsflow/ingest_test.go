@@ -0,0 +1,141 @@
+package optionsflow
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/signals"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/unusualwhales"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/unusualwhales/uwtest"
+)
+
+const testKey = "test-key"
+
+func flowAlert(id, ticker, optionType string, at time.Time, premium, askShare float64) unusualwhales.FlowAlert {
+	return unusualwhales.FlowAlert{
+		ID: id, Ticker: ticker, Type: optionType, CreatedAt: at, Expiry: "2025-03-28", Strike: 100,
+		TotalPremium:     unusualwhales.Number(premium),
+		TotalAskSidePrem: unusualwhales.Number(premium * askShare),
+		TotalBidSidePrem: unusualwhales.Number(premium * (1 - askShare)),
+		TradeCount:       1, Volume: 3_000, OpenInterest: 1_000,
+	}
+}
+
+func newTestIngestor(t *testing.T, url, apiKey string) (*Ingestor, *store.MemoryStore) {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	mem := store.NewMemoryStore(store.MemoryOptions{Logger: logger})
+	client := unusualwhales.NewClient(apiKey, unusualwhales.Options{BaseURL: url, BaseBackoff: time.Millisecond})
+	return NewIngestor(client, mem, signals.NewWriter(mem, nil, logger), Options{Logger: logger}), mem
+}
+
+func TestPollStoresAcceptedAlertsOnce(t *testing.T) {
+	server := uwtest.NewServer(testKey)
+	defer server.Close()
+	server.AddFlowAlerts(
+		flowAlert("bull", "AAPL", "call", session, 300_000, 1),
+		flowAlert("bear", "MSFT", "put", session.Add(time.Minute), 2_000_000, 1),
+		flowAlert("mid", "GOOG", "call", session.Add(2*time.Minute), 100_000, 0.5),
+		flowAlert("small", "TSLA", "call", session, 10_000, 1),
+	)
+
+	ctx := context.Background()
+	in, mem := newTestIngestor(t, server.URL(), testKey)
+
+	n, err := in.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("first poll stored %d, want 3", n)
+	}
+	sigs, err := mem.GetSignals(ctx, models.SignalFilter{Source: models.SourceOptionsFlow}, 0)
+	if err != nil {
+		t.Fatalf("GetSignals: %v", err)
+	}
+	if len(sigs) != 2 {
+		t.Errorf("first poll wrote %d signals, want 2 (mid-market flow has no direction)", len(sigs))
+	}
+
+	// The same alerts come back on every poll until they age out
+	if n, err := in.Poll(ctx); err != nil || n != 0 {
+		t.Errorf("second poll stored %d (%v), want 0", n, err)
+	}
+
+	server.AddFlowAlerts(flowAlert("new", "NVDA", "put", session.Add(3*time.Minute), 500_000, 0))
+	if n, err := in.Poll(ctx); err != nil || n != 1 {
+		t.Errorf("third poll stored %d (%v), want 1", n, err)
+	}
+
+	flows, err := mem.GetOptionsFlow(ctx, models.OptionsFlowFilter{}, 0)
+	if err != nil {
+		t.Fatalf("GetOptionsFlow: %v", err)
+	}
+	if len(flows) != 4 {
+		t.Errorf("stored %d flows, want 4", len(flows))
+	}
+	sigs, _ = mem.GetSignals(ctx, models.SignalFilter{Source: models.SourceOptionsFlow}, 0)
+	if len(sigs) != 3 {
+		t.Errorf("wrote %d signals in all, want 3", len(sigs))
+	}
+}
+
+func TestPollReturnsClientErrors(t *testing.T) {
+	server := uwtest.NewServer(testKey)
+	defer server.Close()
+
+	in, _ := newTestIngestor(t, server.URL(), "wrong-key")
+	if _, err := in.Poll(context.Background()); !errors.Is(err, unusualwhales.ErrUnauthorized) {
+		t.Errorf("Poll returned %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestSignal(t *testing.T) {
+	tests := []struct {
+		name       string
+		flow       models.OptionsFlow
+		ok         bool
+		direction  models.SignalDirection
+		strength   models.SignalStrength
+		confidence float64
+	}{
+		{"no direction", models.OptionsFlow{Premium: 1_000_000}, false, "", 0, 0},
+		{"weak block", models.OptionsFlow{
+			Direction: models.FlowBullish, TradeType: models.TradeBlock, Premium: 100_000, Volume: 1_500, OpenInterest: 1_000,
+		}, true, models.Bullish, models.StrengthWeak, 0.4},
+		{"moderate opening sweep", models.OptionsFlow{
+			Direction: models.FlowBearish, TradeType: models.TradeSweep, Premium: 250_000, Volume: 2_000, OpenInterest: 1_000,
+		}, true, models.Bearish, models.StrengthModerate, 0.8},
+		{"strong", models.OptionsFlow{
+			Direction: models.FlowBullish, TradeType: models.TradeSplit, Premium: 1_000_000, Volume: 100,
+		}, true, models.Bullish, models.StrengthStrong, 0.6},
+		{"very strong", models.OptionsFlow{
+			Direction: models.FlowBearish, TradeType: models.TradeBlock, Premium: 5_000_000, Volume: 1_500, OpenInterest: 1_000,
+		}, true, models.Bearish, models.StrengthVeryStrong, 0.4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, ok := Signal(tt.flow)
+			if ok != tt.ok {
+				t.Fatalf("Signal() ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if sig.Direction != tt.direction || sig.Strength != tt.strength || !approx(sig.Confidence, tt.confidence) {
+				t.Errorf("Signal() = %s %v %.2f, want %s %v %.2f",
+					sig.Direction, sig.Strength, sig.Confidence, tt.direction, tt.strength, tt.confidence)
+			}
+		})
+	}
+}
+
+func approx(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
@@ -0,0 +1,139 @@
+// Package optionsflow turns Unusual Whales flow alerts into stored options
+// flow and signals, keeping only large, fresh, short-dated orders placed
+// during the settled part of the session.
+package optionsflow
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/unusualwhales"
+)
+
+// sideShare is the share of an order's premium that must trade on one side
+// of the spread for the order to count as bought or sold there
+const sideShare = 0.6
+
+// Rules decide which flow is worth recording
+type Rules struct {
+	// MinPremium is the smallest order, in dollars, kept
+	MinPremium float64
+	// MinVolumeOIRatio is how far the contract's day volume must exceed its
+	// open interest, a sign the order opens new positions
+	MinVolumeOIRatio float64
+	// MaxDTE is the furthest expiration, in days, kept
+	MaxDTE int
+	// IgnoreBefore and IgnoreAfter bound the part of the session kept, as
+	// New York time since midnight; the open and close are noisy
+	IgnoreBefore time.Duration
+	IgnoreAfter  time.Duration
+}
+
+// DefaultRules returns the rules from the options flow plan: $50,000 or
+// more, volume 1.5x open interest, 45 days or less to expiration, between
+// 9:45 and 15:45 ET
+func DefaultRules() Rules {
+	return Rules{
+		MinPremium:       50000,
+		MinVolumeOIRatio: 1.5,
+		MaxDTE:           45,
+		IgnoreBefore:     9*time.Hour + 45*time.Minute,
+		IgnoreAfter:      15*time.Hour + 45*time.Minute,
+	}
+}
+
+// Accept reports whether flow passes the rules, and if not, why
+func (r Rules) Accept(flow models.OptionsFlow) (bool, string) {
+	if flow.Premium < r.MinPremium {
+		return false, "premium below minimum"
+	}
+	if flow.DTE < 0 || flow.DTE > r.MaxDTE {
+		return false, "expiration out of range"
+	}
+	// Any volume on a contract with no open interest opens positions
+	if flow.OpenInterest > 0 && float64(flow.Volume) < r.MinVolumeOIRatio*float64(flow.OpenInterest) {
+		return false, "volume too low against open interest"
+	}
+	at := flow.Timestamp.In(calendar.Location)
+	if tod := at.Sub(calendar.Date(at)); tod < r.IgnoreBefore || tod > r.IgnoreAfter {
+		return false, "outside trading window"
+	}
+	return true, ""
+}
+
+// Direction is the stance implied by buying or selling a call or put:
+// calls bought and puts sold are bullish, calls sold and puts bought
+// bearish. Mid-market orders have no direction.
+func Direction(optionType models.OptionType, side models.OptionSide) models.FlowDirection {
+	switch {
+	case optionType == models.Call && side == models.SideAsk,
+		optionType == models.Put && side == models.SideBid:
+		return models.FlowBullish
+	case optionType == models.Call && side == models.SideBid,
+		optionType == models.Put && side == models.SideAsk:
+		return models.FlowBearish
+	}
+	return ""
+}
+
+// FromAlert converts a flow alert to options flow, deriving its side, trade
+// type, days to expiration and direction
+func FromAlert(alert unusualwhales.FlowAlert) (models.OptionsFlow, error) {
+	if alert.Ticker == "" || alert.CreatedAt.IsZero() {
+		return models.OptionsFlow{}, fmt.Errorf("flow alert %s has no ticker or time", alert.ID)
+	}
+	expiration, err := time.Parse("2006-01-02", alert.Expiry)
+	if err != nil {
+		return models.OptionsFlow{}, fmt.Errorf("flow alert %s has invalid expiry %q", alert.ID, alert.Expiry)
+	}
+
+	flow := models.OptionsFlow{
+		AlertID:      alert.ID,
+		Timestamp:    alert.CreatedAt,
+		Ticker:       strings.ToUpper(alert.Ticker),
+		Expiration:   expiration,
+		Strike:       float64(alert.Strike),
+		Premium:      float64(alert.TotalPremium),
+		Volume:       alert.Volume,
+		OpenInterest: alert.OpenInterest,
+		RawData:      alert.Raw,
+	}
+	switch strings.ToLower(alert.Type) {
+	case "call":
+		flow.OptionType = models.Call
+	case "put":
+		flow.OptionType = models.Put
+	default:
+		return models.OptionsFlow{}, fmt.Errorf("flow alert %s has invalid type %q", alert.ID, alert.Type)
+	}
+
+	flow.Side = models.SideMid
+	if premium := float64(alert.TotalPremium); premium > 0 {
+		switch {
+		case float64(alert.TotalAskSidePrem) >= sideShare*premium:
+			flow.Side = models.SideAsk
+		case float64(alert.TotalBidSidePrem) >= sideShare*premium:
+			flow.Side = models.SideBid
+		}
+	}
+
+	switch {
+	case alert.HasSweep:
+		flow.TradeType = models.TradeSweep
+	case alert.TradeCount > 1:
+		flow.TradeType = models.TradeSplit
+	default:
+		flow.TradeType = models.TradeBlock
+	}
+
+	// Expirations are dates, so count days from the trade's session date
+	y, m, d := alert.CreatedAt.In(calendar.Location).Date()
+	session := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	flow.DTE = int(math.Round(expiration.Sub(session).Hours() / 24))
+	flow.Direction = Direction(flow.OptionType, flow.Side)
+	return flow, nil
+}
@@ -0,0 +1,176 @@
+package optionsflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/unusualwhales"
+)
+
+// session is a trading day midmorning, inside the default window
+var session = time.Date(2025, 3, 14, 11, 0, 0, 0, calendar.Location)
+
+func TestRulesAccept(t *testing.T) {
+	// passing is an order every default rule accepts
+	passing := models.OptionsFlow{
+		Timestamp: session, Premium: 100_000, DTE: 14, Volume: 3_000, OpenInterest: 1_000,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*models.OptionsFlow)
+		want   bool
+		reason string
+	}{
+		{"accepted", func(f *models.OptionsFlow) {}, true, ""},
+		{"minimum premium", func(f *models.OptionsFlow) { f.Premium = 50_000 }, true, ""},
+		{"premium below minimum", func(f *models.OptionsFlow) { f.Premium = 49_999 }, false, "premium below minimum"},
+		{"expiring today", func(f *models.OptionsFlow) { f.DTE = 0 }, true, ""},
+		{"longest expiration", func(f *models.OptionsFlow) { f.DTE = 45 }, true, ""},
+		{"expiration too far", func(f *models.OptionsFlow) { f.DTE = 46 }, false, "expiration out of range"},
+		{"expired", func(f *models.OptionsFlow) { f.DTE = -1 }, false, "expiration out of range"},
+		{"volume at ratio", func(f *models.OptionsFlow) { f.Volume = 1_500 }, true, ""},
+		{"volume below ratio", func(f *models.OptionsFlow) { f.Volume = 1_499 }, false, "volume too low against open interest"},
+		{"no open interest", func(f *models.OptionsFlow) { f.Volume, f.OpenInterest = 10, 0 }, true, ""},
+		{"window start", func(f *models.OptionsFlow) {
+			f.Timestamp = time.Date(2025, 3, 14, 9, 45, 0, 0, calendar.Location)
+		}, true, ""},
+		{"before window", func(f *models.OptionsFlow) {
+			f.Timestamp = time.Date(2025, 3, 14, 9, 44, 59, 0, calendar.Location)
+		}, false, "outside trading window"},
+		{"window end", func(f *models.OptionsFlow) {
+			f.Timestamp = time.Date(2025, 3, 14, 15, 45, 0, 0, calendar.Location)
+		}, true, ""},
+		{"after window", func(f *models.OptionsFlow) {
+			f.Timestamp = time.Date(2025, 3, 14, 15, 46, 0, 0, calendar.Location)
+		}, false, "outside trading window"},
+		{"window read in New York time", func(f *models.OptionsFlow) {
+			f.Timestamp = time.Date(2025, 3, 14, 13, 30, 0, 0, time.UTC) // 9:30 EDT
+		}, false, "outside trading window"},
+	}
+
+	rules := DefaultRules()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := passing
+			tt.modify(&flow)
+			ok, reason := rules.Accept(flow)
+			if ok != tt.want || reason != tt.reason {
+				t.Errorf("Accept() = %v, %q; want %v, %q", ok, reason, tt.want, tt.reason)
+			}
+		})
+	}
+}
+
+func TestDirection(t *testing.T) {
+	tests := []struct {
+		optionType models.OptionType
+		side       models.OptionSide
+		want       models.FlowDirection
+	}{
+		{models.Call, models.SideAsk, models.FlowBullish},
+		{models.Put, models.SideBid, models.FlowBullish},
+		{models.Call, models.SideBid, models.FlowBearish},
+		{models.Put, models.SideAsk, models.FlowBearish},
+		{models.Call, models.SideMid, ""},
+		{models.Put, models.SideMid, ""},
+	}
+	for _, tt := range tests {
+		if got := Direction(tt.optionType, tt.side); got != tt.want {
+			t.Errorf("Direction(%s, %s) = %q, want %q", tt.optionType, tt.side, got, tt.want)
+		}
+	}
+}
+
+func TestFromAlert(t *testing.T) {
+	base := unusualwhales.FlowAlert{
+		ID: "a1", Ticker: "aapl", CreatedAt: session, Expiry: "2025-03-28", Strike: 215, Type: "call",
+		TotalPremium: 100_000, TotalAskSidePrem: 80_000, TotalBidSidePrem: 20_000,
+		TradeCount: 1, Volume: 3_000, OpenInterest: 1_000,
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(*unusualwhales.FlowAlert)
+		side      models.OptionSide
+		tradeType models.TradeType
+		direction models.FlowDirection
+	}{
+		{"call bought", func(a *unusualwhales.FlowAlert) {}, models.SideAsk, models.TradeBlock, models.FlowBullish},
+		{"call sold", func(a *unusualwhales.FlowAlert) {
+			a.TotalAskSidePrem, a.TotalBidSidePrem = 20_000, 80_000
+		}, models.SideBid, models.TradeBlock, models.FlowBearish},
+		{"put bought", func(a *unusualwhales.FlowAlert) { a.Type = "put" }, models.SideAsk, models.TradeBlock, models.FlowBearish},
+		{"put sold", func(a *unusualwhales.FlowAlert) {
+			a.Type, a.TotalAskSidePrem, a.TotalBidSidePrem = "PUT", 0, 60_000
+		}, models.SideBid, models.TradeBlock, models.FlowBullish},
+		{"mid market", func(a *unusualwhales.FlowAlert) {
+			a.TotalAskSidePrem, a.TotalBidSidePrem = 59_999, 40_001
+		}, models.SideMid, models.TradeBlock, ""},
+		{"sweep", func(a *unusualwhales.FlowAlert) { a.HasSweep, a.TradeCount = true, 4 }, models.SideAsk, models.TradeSweep, models.FlowBullish},
+		{"split fill", func(a *unusualwhales.FlowAlert) { a.TradeCount = 3 }, models.SideAsk, models.TradeSplit, models.FlowBullish},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := base
+			tt.modify(&alert)
+			flow, err := FromAlert(alert)
+			if err != nil {
+				t.Fatalf("FromAlert: %v", err)
+			}
+			if flow.Side != tt.side || flow.TradeType != tt.tradeType || flow.Direction != tt.direction {
+				t.Errorf("got side %s, trade %s, direction %q; want %s, %s, %q",
+					flow.Side, flow.TradeType, flow.Direction, tt.side, tt.tradeType, tt.direction)
+			}
+		})
+	}
+
+	flow, err := FromAlert(base)
+	if err != nil {
+		t.Fatalf("FromAlert: %v", err)
+	}
+	if flow.AlertID != "a1" || flow.Ticker != "AAPL" || flow.OptionType != models.Call ||
+		flow.Strike != 215 || flow.Premium != 100_000 || flow.DTE != 14 {
+		t.Errorf("FromAlert() = %+v", flow)
+	}
+}
+
+func TestFromAlertDTEUsesSessionDate(t *testing.T) {
+	// 9 PM in New York is already the next day in UTC
+	alert := unusualwhales.FlowAlert{
+		ID: "late", Ticker: "SPY", Type: "put", Expiry: "2025-03-17",
+		CreatedAt: time.Date(2025, 3, 14, 21, 0, 0, 0, calendar.Location),
+	}
+	flow, err := FromAlert(alert)
+	if err != nil {
+		t.Fatalf("FromAlert: %v", err)
+	}
+	if flow.DTE != 3 {
+		t.Errorf("DTE = %d, want 3", flow.DTE)
+	}
+}
+
+func TestFromAlertRejectsMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*unusualwhales.FlowAlert)
+	}{
+		{"no ticker", func(a *unusualwhales.FlowAlert) { a.Ticker = "" }},
+		{"no time", func(a *unusualwhales.FlowAlert) { a.CreatedAt = time.Time{} }},
+		{"bad expiry", func(a *unusualwhales.FlowAlert) { a.Expiry = "03/28/2025" }},
+		{"bad type", func(a *unusualwhales.FlowAlert) { a.Type = "straddle" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := unusualwhales.FlowAlert{
+				ID: "bad", Ticker: "AAPL", CreatedAt: session, Expiry: "2025-03-28", Type: "call",
+			}
+			tt.modify(&alert)
+			if _, err := FromAlert(alert); err == nil {
+				t.Error("FromAlert returned nil error")
+			}
+		})
+	}
+}
@@ -3,27 +3,75 @@ package polygon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/ratelimit"
 )
 
 const baseURL = "https://api.polygon.io"
 
+const (
+	// maxBodyPreview caps how much of an error body is kept in errors
+	maxBodyPreview = 500
+	// maxBackoff caps the delay between retries
+	maxBackoff = time.Minute
+)
+
+// Options configures the client's rate limiting and retry behavior
+type Options struct {
+	// RequestsPerMinute limits outgoing requests; zero disables limiting
+	RequestsPerMinute int
+	// Burst is the number of requests allowed back to back (default 1)
+	Burst int
+	// MaxRetries is how many times a 429, 5xx or transport error is retried
+	MaxRetries int
+	// BaseBackoff is the initial retry delay, doubled each attempt (default 1s)
+	BaseBackoff time.Duration
+	// Mode is ModeLive (default), ModeRecord or ModeReplay
+	Mode string
+	// FixturesDir is where ModeRecord saves and ModeReplay reads responses
+	FixturesDir string
+}
+
 type Client struct {
 	apiKey     string
 	httpClient *http.Client
+	limiter    *ratelimit.TokenBucket
+	opts       Options
 }
 
-func NewClient(apiKey string) *Client {
+func NewClient(apiKey string, opts Options) *Client {
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = time.Second
+	}
+
+	httpClient := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	limiter := ratelimit.New(opts.RequestsPerMinute, opts.Burst)
+
+	switch opts.Mode {
+	case ModeRecord:
+		httpClient.Transport = &RecordingTransport{Dir: opts.FixturesDir}
+	case ModeReplay:
+		// Replayed responses never hit Polygon, so there is nothing to throttle
+		httpClient.Transport = &ReplayTransport{Dir: opts.FixturesDir}
+		limiter = nil
+	}
+
 	return &Client{
-		apiKey: apiKey,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		apiKey:     apiKey,
+		httpClient: httpClient,
+		limiter:    limiter,
+		opts:       opts,
 	}
 }
 
@@ -64,37 +112,14 @@ type PreviousCloseResponse struct {
 // GetGroupedDaily fetches all US stock data for a given date
 func (c *Client) GetGroupedDaily(ctx context.Context, date time.Time) ([]models.DailyBar, error) {
 	dateStr := date.Format("2006-01-02")
-	url := fmt.Sprintf("%s/v2/aggs/grouped/locale/us/market/stocks/%s?apiKey=%s", baseURL, dateStr, c.apiKey)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	// Read body for debugging
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("reading response body: %w", err)
-	}
+	// Store bars as traded; split and dividend adjustments are applied when
+	// history is read, so bars fetched before and after a split agree
+	query := url.Values{"adjusted": {"false"}}
 
 	var result GroupedDailyResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		// Log first 500 chars for debugging
-		preview := string(body)
-		if len(preview) > 500 {
-			preview = preview[:500]
-		}
-		return nil, fmt.Errorf("decoding response: %w (preview: %s)", err, preview)
+	if err := c.get(ctx, "/v2/aggs/grouped/locale/us/market/stocks/"+dateStr, query, &result); err != nil {
+		return nil, err
 	}
 
 	bars := make([]models.DailyBar, 0, len(result.Results))
@@ -116,30 +141,13 @@ func (c *Client) GetGroupedDaily(ctx context.Context, date time.Time) ([]models.
 
 // GetPreviousClose fetches previous day's close for a single ticker
 func (c *Client) GetPreviousClose(ctx context.Context, symbol string) (*models.DailyBar, error) {
-	url := fmt.Sprintf("%s/v2/aggs/ticker/%s/prev?apiKey=%s", baseURL, symbol, c.apiKey)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
 	var result PreviousCloseResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	if err := c.get(ctx, "/v2/aggs/ticker/"+url.PathEscape(symbol)+"/prev", nil, &result); err != nil {
+		return nil, err
 	}
 
 	if len(result.Results) == 0 {
-		return nil, fmt.Errorf("no data found for %s", symbol)
+		return nil, fmt.Errorf("%w: no data found for %s", ErrNotFound, symbol)
 	}
 
 	r := result.Results[0]
@@ -151,5 +159,230 @@ func (c *Client) GetPreviousClose(ctx context.Context, symbol string) (*models.D
 		Close:  r.C,
 		Volume: int64(r.V),
 		VWAP:   r.VW,
+		Date:   time.UnixMilli(r.Ts),
 	}, nil
 }
+
+// AggregatesResponse represents the Polygon custom bars (aggregates) API response
+type AggregatesResponse struct {
+	Ticker       string `json:"ticker"`
+	Status       string `json:"status"`
+	ResultsCount int    `json:"resultsCount"`
+	Results      []struct {
+		O  float64 `json:"o"`
+		H  float64 `json:"h"`
+		L  float64 `json:"l"`
+		C  float64 `json:"c"`
+		V  float64 `json:"v"`
+		VW float64 `json:"vw"`
+		Ts int64   `json:"t"`
+		N  int     `json:"n"`
+	} `json:"results"`
+	NextURL string `json:"next_url"`
+}
+
+// GetAggregates fetches bars for a symbol over a date range, following
+// next_url pagination for ranges beyond one page (e.g. months of minute bars)
+func (c *Client) GetAggregates(ctx context.Context, req provider.AggregatesRequest) ([]models.Aggregate, error) {
+	multiplier := req.Multiplier
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+	path := fmt.Sprintf("/v2/aggs/ticker/%s/range/%d/%s/%s/%s", url.PathEscape(req.Symbol), multiplier,
+		req.Timespan, req.From.Format("2006-01-02"), req.To.Format("2006-01-02"))
+	// As traded, like grouped daily bars; adjustments are applied on read
+	query := url.Values{
+		"adjusted": {"false"},
+		"sort":     {"asc"},
+		"limit":    {"50000"},
+	}
+
+	var aggs []models.Aggregate
+	next := baseURL + path
+	for next != "" {
+		var page AggregatesResponse
+		if err := c.fetch(ctx, next, query, &page); err != nil {
+			return nil, err
+		}
+		for _, r := range page.Results {
+			aggs = append(aggs, models.Aggregate{
+				Symbol:       req.Symbol,
+				Timestamp:    time.UnixMilli(r.Ts),
+				Open:         r.O,
+				High:         r.H,
+				Low:          r.L,
+				Close:        r.C,
+				Volume:       int64(r.V),
+				VWAP:         r.VW,
+				Transactions: r.N,
+			})
+		}
+		// next_url already carries the cursor and original parameters
+		next, query = page.NextURL, nil
+	}
+
+	return aggs, nil
+}
+
+// TickersResponse represents one page of the Polygon reference tickers API response
+type TickersResponse struct {
+	Status  string `json:"status"`
+	Count   int    `json:"count"`
+	Results []struct {
+		Ticker          string `json:"ticker"`
+		Name            string `json:"name"`
+		Market          string `json:"market"`
+		Type            string `json:"type"`
+		PrimaryExchange string `json:"primary_exchange"`
+		CIK             string `json:"cik"`
+		Active          bool   `json:"active"`
+		CurrencyName    string `json:"currency_name"`
+	} `json:"results"`
+	NextURL string `json:"next_url"`
+}
+
+// GetTickers fetches reference data for all active US stock tickers,
+// following next_url pagination
+func (c *Client) GetTickers(ctx context.Context) ([]models.Ticker, error) {
+	query := url.Values{
+		"market": {"stocks"},
+		"active": {"true"},
+		"limit":  {"1000"},
+	}
+
+	var tickers []models.Ticker
+	next := baseURL + "/v3/reference/tickers"
+	for next != "" {
+		var page TickersResponse
+		if err := c.fetch(ctx, next, query, &page); err != nil {
+			return nil, err
+		}
+		for _, r := range page.Results {
+			tickers = append(tickers, models.Ticker{
+				Symbol:          r.Ticker,
+				Name:            r.Name,
+				Type:            r.Type,
+				PrimaryExchange: r.PrimaryExchange,
+				CIK:             r.CIK,
+				Active:          r.Active,
+				Currency:        strings.ToUpper(r.CurrencyName),
+			})
+		}
+		// next_url already carries the cursor and original filters
+		next, query = page.NextURL, nil
+	}
+
+	return tickers, nil
+}
+
+// get performs a rate-limited GET against path, retrying throttled and
+// transient failures, and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	return c.fetch(ctx, baseURL+path, query, out)
+}
+
+// fetch is get for an absolute URL, such as a pagination next_url. Query
+// parameters are merged into any already present and the API key is added.
+func (c *Client) fetch(ctx context.Context, rawURL string, query url.Values, out any) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parsing request URL: %w", err)
+	}
+	q := u.Query()
+	for k, vs := range query {
+		q[k] = vs
+	}
+	q.Set("apiKey", c.apiKey)
+	u.RawQuery = q.Encode()
+	reqURL := u.String()
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		body, retryAfter, err := c.do(ctx, reqURL)
+		if err == nil {
+			if err := json.Unmarshal(body, out); err != nil {
+				// Include the start of the body for debugging
+				return fmt.Errorf("decoding response: %w (preview: %s)", err, preview(body))
+			}
+			return nil
+		}
+		lastErr = err
+
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && !retryable(statusErr.StatusCode) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt >= c.opts.MaxRetries {
+			return lastErr
+		}
+
+		delay := c.backoff(attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// do executes a single request and returns the body of a 200 response, or
+// an error along with any Retry-After delay the server asked for
+func (c *Client) do(ctx context.Context, reqURL string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Drop the URL from the error so the API key is never logged
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, 0, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After")), &StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       preview(body),
+		}
+	}
+
+	return body, 0, nil
+}
+
+// backoff returns the delay before retry attempt (0-based)
+func (c *Client) backoff(attempt int) time.Duration {
+	return ratelimit.Backoff(c.opts.BaseBackoff, maxBackoff, attempt)
+}
+
+func preview(body []byte) string {
+	if len(body) > maxBodyPreview {
+		return string(body[:maxBodyPreview])
+	}
+	return string(body)
+}
+
+// Ensure Client implements provider.MarketData
+var _ provider.MarketData = (*Client)(nil)
@@ -0,0 +1,50 @@
+package polygon
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+)
+
+// Typed errors returned by the client. They are the provider package's
+// sentinels, so callers can handle any data source uniformly.
+var (
+	// ErrRateLimited is returned when Polygon keeps answering 429 after all retries
+	ErrRateLimited = provider.ErrRateLimited
+	// ErrUnauthorized is returned for a missing, invalid or under-entitled API key
+	ErrUnauthorized = provider.ErrUnauthorized
+	// ErrNotFound is returned when the requested resource or data does not exist
+	ErrNotFound = provider.ErrNotFound
+)
+
+// StatusError describes a non-200 response from Polygon. It unwraps to one
+// of the sentinel errors above when the status code maps to one.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status code: %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *StatusError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	}
+	return nil
+}
+
+// retryable reports whether a request that failed with status code may succeed if retried
+func retryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
@@ -0,0 +1,173 @@
+package polygon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Transport modes selectable via Options.Mode
+const (
+	ModeLive   = "live"
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+// fixture is a recorded response as stored on disk
+type fixture struct {
+	Method     string          `json:"method"`
+	URL        string          `json:"url"`
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	BodyText   string          `json:"body_text,omitempty"`
+}
+
+// RecordingTransport performs requests with Next and saves each response to
+// Dir, with the apiKey query parameter stripped, for later replay
+type RecordingTransport struct {
+	Dir  string
+	Next http.RoundTripper
+}
+
+// RoundTrip performs the request and records the response
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	f := fixture{
+		Method:     req.Method,
+		URL:        redactedURL(req.URL),
+		StatusCode: resp.StatusCode,
+		Header:     recordedHeaders(resp.Header),
+	}
+	if json.Valid(body) {
+		f.Body = body
+	} else {
+		f.BodyText = string(body)
+	}
+
+	if err := writeFixture(filepath.Join(t.Dir, fixtureName(req)), f); err != nil {
+		return nil, fmt.Errorf("recording fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ReplayTransport serves responses previously saved by RecordingTransport.
+// Requests without a fixture get a 404 naming the missing file.
+type ReplayTransport struct {
+	Dir string
+}
+
+// RoundTrip returns the recorded response for req
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := fixtureName(req)
+	data, err := os.ReadFile(filepath.Join(t.Dir, name))
+	if os.IsNotExist(err) {
+		msg := fmt.Sprintf(`{"status":"NOT_FOUND","error":"no recorded fixture %s for %s"}`, name, redactedURL(req.URL))
+		return newResponse(req, http.StatusNotFound, http.Header{"Content-Type": {"application/json"}}, []byte(msg)), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture: %w", err)
+	}
+
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decoding fixture %s: %w", name, err)
+	}
+
+	body := []byte(f.Body)
+	if f.BodyText != "" {
+		body = []byte(f.BodyText)
+	}
+	return newResponse(req, f.StatusCode, f.Header, body), nil
+}
+
+func newResponse(req *http.Request, status int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+var unsafeFixtureChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fixtureName derives a stable file name from the request method, path and
+// query (excluding apiKey), e.g. GET_v2_aggs_ticker_AAPL_prev.json
+func fixtureName(req *http.Request) string {
+	path := strings.Trim(req.URL.Path, "/")
+	name := req.Method + "_" + unsafeFixtureChars.ReplaceAllString(path, "_")
+
+	query := req.URL.Query()
+	query.Del("apiKey")
+	if len(query) > 0 {
+		sum := sha256.Sum256([]byte(query.Encode()))
+		name += "__" + hex.EncodeToString(sum[:6])
+	}
+	return name + ".json"
+}
+
+// redactedURL returns u without the apiKey query parameter
+func redactedURL(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	query.Del("apiKey")
+	clean.RawQuery = query.Encode()
+	return clean.String()
+}
+
+// recordedHeaders keeps only the headers the client reads
+func recordedHeaders(h http.Header) http.Header {
+	kept := http.Header{}
+	for _, key := range []string{"Content-Type", "Retry-After"} {
+		if v := h.Get(key); v != "" {
+			kept.Set(key, v)
+		}
+	}
+	return kept
+}
+
+func writeFixture(path string, f fixture) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
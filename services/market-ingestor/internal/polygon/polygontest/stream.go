@@ -0,0 +1,249 @@
+// Package polygontest provides local stand-ins for Polygon services, so the
+// ingestor's streaming mode can be exercised without an API key or network.
+package polygontest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/gorilla/websocket"
+)
+
+// StreamServer speaks the stocks WebSocket protocol: it greets clients with
+// a connected status, checks the key sent in the auth action, records
+// subscriptions and pushes AM (per-minute aggregate) messages to subscribers.
+type StreamServer struct {
+	server   *httptest.Server
+	apiKey   string
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[*streamClient]bool
+	session map[string]*sessionTotals
+	// changed is closed and replaced whenever clients or subscriptions change
+	changed chan struct{}
+}
+
+type streamClient struct {
+	conn *websocket.Conn
+	// writeMu serializes writes; gorilla allows one concurrent writer
+	writeMu sync.Mutex
+	// channels are the subscribed AM channels; "*" means every symbol
+	channels map[string]bool
+}
+
+// sessionTotals accumulates the day fields AM messages carry
+type sessionTotals struct {
+	date     string
+	open     float64
+	volume   int64
+	notional float64
+}
+
+// NewStreamServer starts a stand-in that accepts apiKey
+func NewStreamServer(apiKey string) *StreamServer {
+	s := &StreamServer{
+		apiKey:  apiKey,
+		clients: make(map[*streamClient]bool),
+		session: make(map[string]*sessionTotals),
+		changed: make(chan struct{}),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// URL is the ws:// address to pass as StreamOptions.URL
+func (s *StreamServer) URL() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+// Close disconnects all clients and stops the server
+func (s *StreamServer) Close() {
+	s.DropConnections()
+	s.server.Close()
+}
+
+// DropConnections closes every client connection without a close frame,
+// as a network failure would
+func (s *StreamServer) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		c.conn.Close()
+		delete(s.clients, c)
+	}
+	s.notify()
+}
+
+// Connections returns the number of authenticated clients
+func (s *StreamServer) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// WaitSubscribed blocks until some client is subscribed to symbol's AM
+// channel, or ctx is done
+func (s *StreamServer) WaitSubscribed(ctx context.Context, symbol string) error {
+	for {
+		s.mu.Lock()
+		changed := s.changed
+		for c := range s.clients {
+			if c.channels["AM."+symbol] || c.channels["AM.*"] {
+				s.mu.Unlock()
+				return nil
+			}
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// SendMinute pushes bar as an AM message to the clients subscribed to its
+// symbol. Session open, volume and VWAP are accumulated from the bars sent
+// for the same New York date, as Polygon reports them.
+func (s *StreamServer) SendMinute(bar models.Aggregate) {
+	s.mu.Lock()
+	date := bar.Timestamp.In(calendar.Location).Format("2006-01-02")
+	totals := s.session[bar.Symbol]
+	if totals == nil || totals.date != date {
+		totals = &sessionTotals{date: date, open: bar.Open}
+		s.session[bar.Symbol] = totals
+	}
+	vwap := bar.VWAP
+	if vwap == 0 {
+		vwap = (bar.High + bar.Low + bar.Close) / 3
+	}
+	totals.volume += bar.Volume
+	totals.notional += vwap * float64(bar.Volume)
+
+	var dayVWAP float64
+	if totals.volume > 0 {
+		dayVWAP = totals.notional / float64(totals.volume)
+	}
+	msg := map[string]any{
+		"ev":  "AM",
+		"sym": bar.Symbol,
+		"v":   bar.Volume,
+		"av":  totals.volume,
+		"op":  totals.open,
+		"vw":  vwap,
+		"o":   bar.Open,
+		"c":   bar.Close,
+		"h":   bar.High,
+		"l":   bar.Low,
+		"a":   dayVWAP,
+		"z":   0,
+		"s":   bar.Timestamp.UnixMilli(),
+		"e":   bar.Timestamp.Add(time.Minute).UnixMilli(),
+	}
+
+	var targets []*streamClient
+	for c := range s.clients {
+		if c.channels["AM."+bar.Symbol] || c.channels["AM.*"] {
+			targets = append(targets, c)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range targets {
+		c.send([]any{msg})
+	}
+}
+
+func (s *StreamServer) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &streamClient{conn: conn, channels: make(map[string]bool)}
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.notify()
+		s.mu.Unlock()
+	}()
+
+	c.send([]any{status("connected", "Connected Successfully")})
+
+	authenticated := false
+	for {
+		var action struct {
+			Action string `json:"action"`
+			Params string `json:"params"`
+		}
+		if err := conn.ReadJSON(&action); err != nil {
+			return
+		}
+
+		switch {
+		case action.Action == "auth":
+			if action.Params != s.apiKey {
+				c.send([]any{status("auth_failed", "authentication failed")})
+				return
+			}
+			authenticated = true
+			s.mu.Lock()
+			s.clients[c] = true
+			s.notify()
+			s.mu.Unlock()
+			c.send([]any{status("auth_success", "authenticated")})
+		case !authenticated:
+			c.send([]any{status("error", "not authorized")})
+		case action.Action == "subscribe" || action.Action == "unsubscribe":
+			var replies []any
+			s.mu.Lock()
+			for _, channel := range strings.Split(action.Params, ",") {
+				channel = strings.TrimSpace(channel)
+				if channel == "" {
+					continue
+				}
+				if action.Action == "subscribe" {
+					c.channels[channel] = true
+					replies = append(replies, status("success", "subscribed to: "+channel))
+				} else {
+					delete(c.channels, channel)
+					replies = append(replies, status("success", "unsubscribed to: "+channel))
+				}
+			}
+			s.notify()
+			s.mu.Unlock()
+			c.send(replies)
+		default:
+			c.send([]any{status("error", "unknown action")})
+		}
+	}
+}
+
+func (c *streamClient) send(msgs []any) {
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		return
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// notify wakes WaitSubscribed callers; s.mu must be held
+func (s *StreamServer) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func status(status, message string) map[string]string {
+	return map[string]string{"ev": "status", "status": status, "message": message}
+}
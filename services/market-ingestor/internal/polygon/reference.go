@@ -0,0 +1,132 @@
+package polygon
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+)
+
+// SplitsResponse represents one page of the Polygon reference splits API response
+type SplitsResponse struct {
+	Status  string `json:"status"`
+	Results []struct {
+		ID            string  `json:"id"`
+		Ticker        string  `json:"ticker"`
+		ExecutionDate string  `json:"execution_date"`
+		SplitFrom     float64 `json:"split_from"`
+		SplitTo       float64 `json:"split_to"`
+	} `json:"results"`
+	NextURL string `json:"next_url"`
+}
+
+// DividendsResponse represents one page of the Polygon reference dividends API response
+type DividendsResponse struct {
+	Status  string `json:"status"`
+	Results []struct {
+		ID             string  `json:"id"`
+		Ticker         string  `json:"ticker"`
+		CashAmount     float64 `json:"cash_amount"`
+		Currency       string  `json:"currency"`
+		DividendType   string  `json:"dividend_type"`
+		ExDividendDate string  `json:"ex_dividend_date"`
+		PayDate        string  `json:"pay_date"`
+	} `json:"results"`
+	NextURL string `json:"next_url"`
+}
+
+// GetCorporateActions fetches splits and cash dividends with ex dates
+// between from and to, following next_url pagination
+func (c *Client) GetCorporateActions(ctx context.Context, from, to time.Time) ([]models.CorporateAction, error) {
+	splits, err := c.getSplits(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	dividends, err := c.getDividends(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(splits, dividends...), nil
+}
+
+func (c *Client) getSplits(ctx context.Context, from, to time.Time) ([]models.CorporateAction, error) {
+	query := url.Values{
+		"execution_date.gte": {from.Format("2006-01-02")},
+		"execution_date.lte": {to.Format("2006-01-02")},
+		"limit":              {"1000"},
+	}
+
+	var actions []models.CorporateAction
+	next := baseURL + "/v3/reference/splits"
+	for next != "" {
+		var page SplitsResponse
+		if err := c.fetch(ctx, next, query, &page); err != nil {
+			return nil, err
+		}
+		for _, r := range page.Results {
+			exDate, err := time.Parse("2006-01-02", r.ExecutionDate)
+			if err != nil || r.SplitFrom <= 0 || r.SplitTo <= 0 {
+				continue
+			}
+			actions = append(actions, models.CorporateAction{
+				ID:        r.ID,
+				Symbol:    r.Ticker,
+				Type:      models.ActionSplit,
+				ExDate:    calendar.Date(exDate),
+				SplitFrom: r.SplitFrom,
+				SplitTo:   r.SplitTo,
+			})
+		}
+		// next_url already carries the cursor and original filters
+		next, query = page.NextURL, nil
+	}
+
+	return actions, nil
+}
+
+func (c *Client) getDividends(ctx context.Context, from, to time.Time) ([]models.CorporateAction, error) {
+	query := url.Values{
+		"ex_dividend_date.gte": {from.Format("2006-01-02")},
+		"ex_dividend_date.lte": {to.Format("2006-01-02")},
+		"limit":                {"1000"},
+	}
+
+	var actions []models.CorporateAction
+	next := baseURL + "/v3/reference/dividends"
+	for next != "" {
+		var page DividendsResponse
+		if err := c.fetch(ctx, next, query, &page); err != nil {
+			return nil, err
+		}
+		for _, r := range page.Results {
+			exDate, err := time.Parse("2006-01-02", r.ExDividendDate)
+			if err != nil || r.CashAmount <= 0 {
+				continue
+			}
+			action := models.CorporateAction{
+				ID:           r.ID,
+				Symbol:       r.Ticker,
+				Type:         models.ActionDividend,
+				ExDate:       calendar.Date(exDate),
+				CashAmount:   r.CashAmount,
+				Currency:     r.Currency,
+				DividendType: r.DividendType,
+			}
+			// Pay date is missing for some announcements
+			if payDate, err := time.Parse("2006-01-02", r.PayDate); err == nil {
+				payDate = calendar.Date(payDate)
+				action.PayDate = &payDate
+			}
+			actions = append(actions, action)
+		}
+		next, query = page.NextURL, nil
+	}
+
+	return actions, nil
+}
+
+// Ensure Client reports corporate actions
+var _ provider.CorporateActions = (*Client)(nil)
@@ -0,0 +1,91 @@
+package polygon
+
+import (
+	"context"
+	"net/url"
+)
+
+// Sectors, named after the Select Sector SPDR funds that track them
+const (
+	sectorCommunication = "Communication Services"
+	sectorDiscretionary = "Consumer Discretionary"
+	sectorStaples       = "Consumer Staples"
+	sectorEnergy        = "Energy"
+	sectorFinancials    = "Financials"
+	sectorHealthCare    = "Health Care"
+	sectorIndustrials   = "Industrials"
+	sectorMaterials     = "Materials"
+	sectorRealEstate    = "Real Estate"
+	sectorTechnology    = "Technology"
+	sectorUtilities     = "Utilities"
+)
+
+// sicSectors maps SIC code prefixes to sectors. Major groups (two digits)
+// carry the default; longer prefixes override it for industries that
+// belong elsewhere, such as pharmaceuticals within chemicals.
+var sicSectors = map[string]string{
+	"01": sectorStaples, "02": sectorStaples, "07": sectorStaples,
+	"08": sectorMaterials, "09": sectorStaples,
+	"10": sectorMaterials, "12": sectorEnergy, "13": sectorEnergy, "14": sectorMaterials,
+	"15": sectorIndustrials, "1531": sectorDiscretionary, "16": sectorIndustrials, "17": sectorIndustrials,
+	"20": sectorStaples, "21": sectorStaples,
+	"22": sectorDiscretionary, "23": sectorDiscretionary,
+	"24": sectorMaterials, "25": sectorDiscretionary, "26": sectorMaterials,
+	"27": sectorCommunication,
+	"28": sectorMaterials, "283": sectorHealthCare, "284": sectorStaples,
+	"29": sectorEnergy, "30": sectorMaterials, "31": sectorDiscretionary,
+	"32": sectorMaterials, "33": sectorMaterials, "34": sectorIndustrials,
+	"35": sectorIndustrials, "357": sectorTechnology,
+	"36": sectorTechnology, "363": sectorDiscretionary,
+	"37": sectorIndustrials, "371": sectorDiscretionary,
+	"38": sectorTechnology, "384": sectorHealthCare, "385": sectorHealthCare,
+	"39": sectorDiscretionary,
+	"40": sectorIndustrials, "41": sectorIndustrials, "42": sectorIndustrials,
+	"44": sectorIndustrials, "45": sectorIndustrials, "46": sectorEnergy, "47": sectorIndustrials,
+	"48": sectorCommunication,
+	"49": sectorUtilities, "495": sectorIndustrials,
+	"50": sectorIndustrials, "51": sectorIndustrials, "5122": sectorHealthCare,
+	"52": sectorDiscretionary, "53": sectorDiscretionary, "54": sectorStaples,
+	"55": sectorDiscretionary, "56": sectorDiscretionary, "57": sectorDiscretionary,
+	"58": sectorDiscretionary, "59": sectorDiscretionary, "5912": sectorStaples,
+	"60": sectorFinancials, "61": sectorFinancials, "62": sectorFinancials,
+	"63": sectorFinancials, "64": sectorFinancials, "65": sectorRealEstate,
+	"67": sectorFinancials, "6798": sectorRealEstate,
+	"70": sectorDiscretionary, "72": sectorDiscretionary,
+	"73": sectorIndustrials, "737": sectorTechnology,
+	"75": sectorDiscretionary, "76": sectorIndustrials, "78": sectorCommunication,
+	"79": sectorDiscretionary, "80": sectorHealthCare, "81": sectorIndustrials,
+	"82": sectorDiscretionary, "83": sectorHealthCare,
+	"87": sectorIndustrials, "8731": sectorHealthCare,
+}
+
+// sicSector returns the sector of a four-digit SIC code, or "" when it has
+// none, such as 9995 (non-operating establishments)
+func sicSector(code string) string {
+	for n := len(code); n >= 2; n-- {
+		if sector, ok := sicSectors[code[:n]]; ok {
+			return sector
+		}
+	}
+	return ""
+}
+
+// TickerDetailsResponse represents the Polygon ticker details API response
+type TickerDetailsResponse struct {
+	Status  string `json:"status"`
+	Results struct {
+		Ticker         string `json:"ticker"`
+		SICCode        string `json:"sic_code"`
+		SICDescription string `json:"sic_description"`
+	} `json:"results"`
+}
+
+// GetTickerSector classifies symbol by the SIC code in its ticker details.
+// Funds and other tickers without an SIC code have no sector.
+func (c *Client) GetTickerSector(ctx context.Context, symbol string) (string, error) {
+	var result TickerDetailsResponse
+	if err := c.get(ctx, "/v3/reference/tickers/"+url.PathEscape(symbol), nil, &result); err != nil {
+		return "", err
+	}
+	return sicSector(result.Results.SICCode), nil
+}
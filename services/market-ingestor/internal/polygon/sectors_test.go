@@ -0,0 +1,34 @@
+package polygon
+
+import "testing"
+
+func TestSICSector(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"3571", sectorTechnology},    // electronic computers
+		{"3674", sectorTechnology},    // semiconductors
+		{"7372", sectorTechnology},    // prepackaged software
+		{"2834", sectorHealthCare},    // pharmaceutical preparations
+		{"2821", sectorMaterials},     // plastics materials
+		{"3711", sectorDiscretionary}, // motor vehicles
+		{"3721", sectorIndustrials},   // aircraft
+		{"1311", sectorEnergy},        // crude petroleum and natural gas
+		{"4911", sectorUtilities},     // electric services
+		{"4953", sectorIndustrials},   // refuse systems
+		{"6022", sectorFinancials},    // state commercial banks
+		{"6798", sectorRealEstate},    // real estate investment trusts
+		{"4813", sectorCommunication}, // telephone communications
+		{"5411", sectorStaples},       // grocery stores
+		{"5912", sectorStaples},       // drug stores
+		{"5961", sectorDiscretionary}, // catalog and mail-order houses
+		{"9995", ""},                  // non-operating establishments
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := sicSector(tt.code); got != tt.want {
+			t.Errorf("sicSector(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
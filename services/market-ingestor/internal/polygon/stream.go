@@ -0,0 +1,285 @@
+package polygon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+	"github.com/gorilla/websocket"
+)
+
+// Stocks WebSocket endpoints. The delayed feed is the one available to plans
+// without real-time entitlement.
+const (
+	StreamURL        = "wss://socket.polygon.io/stocks"
+	DelayedStreamURL = "wss://delayed.polygon.io/stocks"
+)
+
+const (
+	// pingInterval is how often the connection is pinged; a connection that
+	// answers nothing for pongWait is considered dead
+	pingInterval = 30 * time.Second
+	pongWait     = 75 * time.Second
+	// handshakeTimeout bounds dialing and each authentication step
+	handshakeTimeout = 15 * time.Second
+	// stableSession is how long a connection must stay up before the
+	// reconnect backoff starts over
+	stableSession = time.Minute
+)
+
+// StreamOptions configures the WebSocket stream
+type StreamOptions struct {
+	// URL is the stocks cluster endpoint (default StreamURL)
+	URL string
+	// MinBackoff is the first reconnect delay, doubled after each failed
+	// attempt up to MaxBackoff (defaults 1s and 1m)
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Logger receives connection lifecycle messages (default slog.Default())
+	Logger *slog.Logger
+}
+
+// Stream subscribes to Polygon's per-minute aggregate (AM) channel
+type Stream struct {
+	apiKey string
+	opts   StreamOptions
+	dialer *websocket.Dialer
+}
+
+func NewStream(apiKey string, opts StreamOptions) *Stream {
+	if opts.URL == "" {
+		opts.URL = StreamURL
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = maxBackoff
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+
+	return &Stream{
+		apiKey: apiKey,
+		opts:   opts,
+		dialer: &websocket.Dialer{HandshakeTimeout: handshakeTimeout},
+	}
+}
+
+// streamMessage is one element of the JSON arrays the socket sends: a
+// status message or a minute aggregate
+type streamMessage struct {
+	Event   string `json:"ev"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+
+	Symbol    string  `json:"sym"`
+	Volume    float64 `json:"v"`
+	DayVolume float64 `json:"av"` // accumulated volume for the session
+	DayOpen   float64 `json:"op"` // official opening price
+	VWAP      float64 `json:"vw"`
+	Open      float64 `json:"o"`
+	Close     float64 `json:"c"`
+	High      float64 `json:"h"`
+	Low       float64 `json:"l"`
+	DayVWAP   float64 `json:"a"` // session VWAP
+	Start     int64   `json:"s"` // bar start, Unix ms
+	End       int64   `json:"e"`
+}
+
+// streamAction is a message sent to the socket
+type streamAction struct {
+	Action string `json:"action"`
+	Params string `json:"params"`
+}
+
+// StreamMinutes connects, authenticates, subscribes to AM.<symbol> for each
+// symbol and delivers bars to handle until ctx is done. Dropped connections
+// are retried with jittered exponential backoff; a rejected API key is not.
+func (s *Stream) StreamMinutes(ctx context.Context, symbols []string, handle func(provider.MinuteUpdate)) error {
+	if len(symbols) == 0 {
+		return errors.New("no symbols to stream")
+	}
+
+	params := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		params[i] = "AM." + symbol
+	}
+	subscription := strings.Join(params, ",")
+
+	attempt := 0
+	for {
+		started := time.Now()
+		err := s.session(ctx, subscription, handle)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, ErrUnauthorized) {
+			return err
+		}
+
+		if time.Since(started) >= stableSession {
+			attempt = 0
+		}
+		delay := s.backoff(attempt)
+		attempt++
+		s.opts.Logger.Warn("polygon stream disconnected, reconnecting",
+			"error", err, "attempt", attempt, "delay", delay.Round(time.Millisecond))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// session runs one connection from dial to disconnect
+func (s *Stream) session(ctx context.Context, subscription string, handle func(provider.MinuteUpdate)) error {
+	dialCtx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	conn, _, err := s.dialer.DialContext(dialCtx, s.opts.URL, nil)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("dialing stream: %w", err)
+	}
+	defer conn.Close()
+
+	// Unblock reads when the caller gives up, and keep the connection
+	// alive between bars, which only arrive while symbols trade
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+				conn.Close()
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(handshakeTimeout))
+			}
+		}
+	}()
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	if err := s.expectStatus(conn, "connected"); err != nil {
+		return err
+	}
+	if err := conn.WriteJSON(streamAction{Action: "auth", Params: s.apiKey}); err != nil {
+		return fmt.Errorf("sending auth: %w", err)
+	}
+	if err := s.expectStatus(conn, "auth_success"); err != nil {
+		return err
+	}
+	if err := conn.WriteJSON(streamAction{Action: "subscribe", Params: subscription}); err != nil {
+		return fmt.Errorf("sending subscribe: %w", err)
+	}
+	s.opts.Logger.Info("polygon stream subscribed", "url", s.opts.URL, "channels", strings.Count(subscription, ",")+1)
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		msgs, err := readMessages(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			switch m.Event {
+			case "AM":
+				handle(m.update())
+			case "status":
+				if m.Status == "max_connections" || m.Status == "auth_failed" {
+					return fmt.Errorf("stream closed by server: %s", m.Message)
+				}
+				s.opts.Logger.Debug("polygon stream status", "status", m.Status, "message", m.Message)
+			}
+		}
+	}
+}
+
+// expectStatus reads until a status message arrives and checks it is want
+func (s *Stream) expectStatus(conn *websocket.Conn, want string) error {
+	for {
+		msgs, err := readMessages(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if m.Event != "status" {
+				continue
+			}
+			switch m.Status {
+			case want:
+				return nil
+			case "auth_failed":
+				return fmt.Errorf("%w: %s", ErrUnauthorized, m.Message)
+			default:
+				return fmt.Errorf("unexpected stream status %q waiting for %q: %s", m.Status, want, m.Message)
+			}
+		}
+	}
+}
+
+func readMessages(conn *websocket.Conn) ([]streamMessage, error) {
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return nil, fmt.Errorf("reading stream: %w", err)
+	}
+	var msgs []streamMessage
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, fmt.Errorf("decoding stream message: %w (preview: %s)", err, preview(data))
+	}
+	return msgs, nil
+}
+
+func (m streamMessage) update() provider.MinuteUpdate {
+	return provider.MinuteUpdate{
+		Bar: models.Aggregate{
+			Symbol:    m.Symbol,
+			Timestamp: time.UnixMilli(m.Start),
+			Open:      m.Open,
+			High:      m.High,
+			Low:       m.Low,
+			Close:     m.Close,
+			Volume:    int64(m.Volume),
+			VWAP:      m.VWAP,
+		},
+		DayOpen:   m.DayOpen,
+		DayVolume: int64(m.DayVolume),
+		DayVWAP:   m.DayVWAP,
+	}
+}
+
+// backoff returns an exponential reconnect delay with full jitter for
+// attempt (0-based), never less than MinBackoff/2
+func (s *Stream) backoff(attempt int) time.Duration {
+	ceiling := s.opts.MinBackoff << attempt
+	if ceiling <= 0 || ceiling > s.opts.MaxBackoff {
+		ceiling = s.opts.MaxBackoff
+	}
+	floor := s.opts.MinBackoff / 2
+	if floor >= ceiling {
+		return ceiling
+	}
+	return floor + time.Duration(rand.Int64N(int64(ceiling-floor)))
+}
+
+// Ensure Stream implements provider.Streamer
+var _ provider.Streamer = (*Stream)(nil)
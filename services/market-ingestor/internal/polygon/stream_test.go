@@ -0,0 +1,152 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/polygon/polygontest"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+)
+
+const testKey = "test-key"
+
+func newTestStream(url, apiKey string) *Stream {
+	return NewStream(apiKey, StreamOptions{
+		URL:        url,
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 50 * time.Millisecond,
+		Logger:     slog.New(slog.DiscardHandler),
+	})
+}
+
+// runStream streams symbols in the background, returning the delivered
+// updates and the error StreamMinutes returns once ctx is done
+func runStream(ctx context.Context, s *Stream, symbols ...string) (<-chan provider.MinuteUpdate, <-chan error) {
+	updates := make(chan provider.MinuteUpdate, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.StreamMinutes(ctx, symbols, func(u provider.MinuteUpdate) { updates <- u })
+	}()
+	return updates, done
+}
+
+func minute(symbol string, at time.Time, close float64, volume int64) models.Aggregate {
+	return models.Aggregate{
+		Symbol: symbol, Timestamp: at,
+		Open: close - 1, High: close + 1, Low: close - 2, Close: close,
+		Volume: volume, VWAP: close,
+	}
+}
+
+func receive(t *testing.T, updates <-chan provider.MinuteUpdate) provider.MinuteUpdate {
+	t.Helper()
+	select {
+	case u := <-updates:
+		return u
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a minute update")
+		return provider.MinuteUpdate{}
+	}
+}
+
+func TestStreamMinutesAuthenticatesAndSubscribes(t *testing.T) {
+	server := polygontest.NewStreamServer(testKey)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	updates, done := runStream(ctx, newTestStream(server.URL(), testKey), "AAPL", "SPY")
+
+	if err := server.WaitSubscribed(ctx, "AAPL"); err != nil {
+		t.Fatalf("waiting for AAPL subscription: %v", err)
+	}
+	if err := server.WaitSubscribed(ctx, "SPY"); err != nil {
+		t.Fatalf("waiting for SPY subscription: %v", err)
+	}
+	if n := server.Connections(); n != 1 {
+		t.Fatalf("Connections() = %d, want 1", n)
+	}
+
+	open := time.Date(2025, 3, 14, calendar.OpenHour, calendar.OpenMinute, 0, 0, calendar.Location)
+	server.SendMinute(minute("MSFT", open, 400, 500)) // not subscribed
+	server.SendMinute(minute("AAPL", open, 200, 1000))
+	server.SendMinute(minute("AAPL", open.Add(time.Minute), 202, 3000))
+
+	first := receive(t, updates)
+	if first.Bar.Symbol != "AAPL" || first.Bar.Close != 200 || !first.Bar.Timestamp.Equal(open) {
+		t.Errorf("first update = %+v, want AAPL 200 at %v", first.Bar, open)
+	}
+	second := receive(t, updates)
+	if second.DayOpen != 199 || second.DayVolume != 4000 {
+		t.Errorf("second update day open %v volume %d, want 199 and 4000", second.DayOpen, second.DayVolume)
+	}
+	if want := (200.0*1000 + 202*3000) / 4000; second.DayVWAP != want {
+		t.Errorf("second update day VWAP = %v, want %v", second.DayVWAP, want)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("StreamMinutes returned %v, want context.Canceled", err)
+	}
+}
+
+func TestStreamMinutesReconnectsAfterDrop(t *testing.T) {
+	server := polygontest.NewStreamServer(testKey)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	updates, done := runStream(ctx, newTestStream(server.URL(), testKey), "AAPL")
+
+	if err := server.WaitSubscribed(ctx, "AAPL"); err != nil {
+		t.Fatalf("waiting for subscription: %v", err)
+	}
+	server.DropConnections()
+	if err := server.WaitSubscribed(ctx, "AAPL"); err != nil {
+		t.Fatalf("waiting for resubscription: %v", err)
+	}
+
+	open := time.Date(2025, 3, 14, calendar.OpenHour, calendar.OpenMinute, 0, 0, calendar.Location)
+	server.SendMinute(minute("AAPL", open, 200, 1000))
+	if u := receive(t, updates); u.Bar.Symbol != "AAPL" {
+		t.Errorf("update after reconnect = %+v, want AAPL", u.Bar)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("StreamMinutes returned %v, want context.Canceled", err)
+	}
+}
+
+func TestStreamMinutesStopsOnRejectedKey(t *testing.T) {
+	server := polygontest.NewStreamServer(testKey)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, done := runStream(ctx, newTestStream(server.URL(), "wrong-key"), "AAPL")
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("StreamMinutes returned %v, want ErrUnauthorized", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("StreamMinutes kept retrying a rejected key")
+	}
+	if n := server.Connections(); n != 0 {
+		t.Errorf("Connections() = %d, want 0", n)
+	}
+}
+
+func TestStreamMinutesRequiresSymbols(t *testing.T) {
+	s := newTestStream("ws://127.0.0.1:0", testKey)
+	if err := s.StreamMinutes(context.Background(), nil, func(provider.MinuteUpdate) {}); err == nil {
+		t.Error("StreamMinutes with no symbols returned nil, want an error")
+	}
+}
@@ -0,0 +1,302 @@
+// Package csvdir is a provider.MarketData backed by a directory of CSV files,
+// so the ingestor can run fully offline.
+//
+// Each <SYMBOL>.csv file holds that symbol's daily bars with a header row:
+//
+//	date,open,high,low,close,volume[,vwap]
+//
+// Dates are YYYY-MM-DD and columns may appear in any order. An optional
+// tickers.csv provides reference data:
+//
+//	symbol,name,type,primary_exchange,cik,active,currency[,sector]
+//
+// Symbols without a tickers.csv row, or with a blank type, are treated as
+// common stock so the default screener type filter still admits them.
+package csvdir
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+)
+
+const tickersFile = "tickers.csv"
+
+// defaultType is the ticker type assumed when tickers.csv does not give one
+const defaultType = "CS"
+
+// Provider serves bars and tickers loaded from a directory of CSV files
+type Provider struct {
+	dir string
+
+	mu      sync.RWMutex
+	bars    map[string][]models.DailyBar // symbol -> bars ordered by date
+	byDate  map[string][]models.DailyBar // YYYY-MM-DD -> bars
+	tickers []models.Ticker
+}
+
+// New loads every CSV file in dir
+func New(dir string) (*Provider, error) {
+	p := &Provider{dir: dir}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload re-reads the directory, picking up added or edited files
+func (p *Provider) Reload() error {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return fmt.Errorf("reading data directory: %w", err)
+	}
+
+	bars := make(map[string][]models.DailyBar)
+	byDate := make(map[string][]models.DailyBar)
+	var tickers []models.Ticker
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(name), ".csv") {
+			continue
+		}
+		path := filepath.Join(p.dir, name)
+
+		if strings.EqualFold(name, tickersFile) {
+			if tickers, err = readTickers(path); err != nil {
+				return fmt.Errorf("reading %s: %w", name, err)
+			}
+			continue
+		}
+
+		symbol := strings.ToUpper(strings.TrimSuffix(name, filepath.Ext(name)))
+		symbolBars, err := readBars(path, symbol)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		bars[symbol] = symbolBars
+		for _, bar := range symbolBars {
+			key := bar.Date.Format("2006-01-02")
+			byDate[key] = append(byDate[key], bar)
+		}
+	}
+
+	p.mu.Lock()
+	p.bars, p.byDate, p.tickers = bars, byDate, tickers
+	p.mu.Unlock()
+
+	return nil
+}
+
+// GetGroupedDaily returns the bar of every symbol with a row for date
+func (p *Provider) GetGroupedDaily(ctx context.Context, date time.Time) ([]models.DailyBar, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	day := p.byDate[date.Format("2006-01-02")]
+	bars := make([]models.DailyBar, len(day))
+	copy(bars, day)
+	for i := range bars {
+		bars[i].Date = date
+	}
+	return bars, nil
+}
+
+// GetPreviousClose returns the last bar in the symbol's file
+func (p *Provider) GetPreviousClose(ctx context.Context, symbol string) (*models.DailyBar, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	bars := p.bars[strings.ToUpper(symbol)]
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("%w: no data found for %s", provider.ErrNotFound, symbol)
+	}
+	bar := bars[len(bars)-1]
+	return &bar, nil
+}
+
+// GetAggregates returns daily bars in the range. Only 1-day bars are available.
+func (p *Provider) GetAggregates(ctx context.Context, req provider.AggregatesRequest) ([]models.Aggregate, error) {
+	if req.Timespan != provider.TimespanDay || req.Multiplier > 1 {
+		return nil, fmt.Errorf("%w: csv files only hold 1 day bars", provider.ErrUnsupported)
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	from, to := req.From.Format("2006-01-02"), req.To.Format("2006-01-02")
+	var aggs []models.Aggregate
+	for _, bar := range p.bars[strings.ToUpper(req.Symbol)] {
+		day := bar.Date.Format("2006-01-02")
+		if day < from || day > to {
+			continue
+		}
+		aggs = append(aggs, models.Aggregate{
+			Symbol:    bar.Symbol,
+			Timestamp: bar.Date,
+			Open:      bar.Open,
+			High:      bar.High,
+			Low:       bar.Low,
+			Close:     bar.Close,
+			Volume:    bar.Volume,
+			VWAP:      bar.VWAP,
+		})
+	}
+	return aggs, nil
+}
+
+// GetTickers returns the rows of tickers.csv, or a common stock entry per
+// symbol file
+func (p *Provider) GetTickers(ctx context.Context) ([]models.Ticker, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.tickers != nil {
+		return append([]models.Ticker(nil), p.tickers...), nil
+	}
+
+	tickers := make([]models.Ticker, 0, len(p.bars))
+	for symbol := range p.bars {
+		tickers = append(tickers, models.Ticker{Symbol: symbol, Type: defaultType, Active: true})
+	}
+	sort.Slice(tickers, func(i, j int) bool {
+		return tickers[i].Symbol < tickers[j].Symbol
+	})
+	return tickers, nil
+}
+
+// readCSV reads a file with a header row, returning rows keyed by lowercase column name
+func readCSV(path string) ([]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]string, len(header))
+		for i, col := range header {
+			if i < len(record) {
+				row[col] = strings.TrimSpace(record[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func readBars(path, symbol string) ([]models.DailyBar, error) {
+	rows, err := readCSV(path)
+	if err != nil {
+		return nil, err
+	}
+
+	bars := make([]models.DailyBar, 0, len(rows))
+	for i, row := range rows {
+		date, err := time.Parse("2006-01-02", row["date"])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid date %q", i+2, row["date"])
+		}
+
+		var values [6]float64
+		for j, col := range []string{"open", "high", "low", "close", "volume", "vwap"} {
+			v := row[col]
+			if v == "" {
+				if col == "vwap" {
+					continue
+				}
+				return nil, fmt.Errorf("row %d: missing %s", i+2, col)
+			}
+			if values[j], err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("row %d: invalid %s %q", i+2, col, v)
+			}
+		}
+
+		bars = append(bars, models.DailyBar{
+			Symbol: symbol,
+			Date:   date,
+			Open:   values[0],
+			High:   values[1],
+			Low:    values[2],
+			Close:  values[3],
+			Volume: int64(values[4]),
+			VWAP:   values[5],
+		})
+	}
+
+	sort.Slice(bars, func(i, j int) bool {
+		return bars[i].Date.Before(bars[j].Date)
+	})
+	return bars, nil
+}
+
+func readTickers(path string) ([]models.Ticker, error) {
+	rows, err := readCSV(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tickers := make([]models.Ticker, 0, len(rows))
+	for _, row := range rows {
+		if row["symbol"] == "" {
+			continue
+		}
+		typ := row["type"]
+		if typ == "" {
+			typ = defaultType
+		}
+		active := true
+		if v := row["active"]; v != "" {
+			active, _ = strconv.ParseBool(v)
+		}
+		tickers = append(tickers, models.Ticker{
+			Symbol:          strings.ToUpper(row["symbol"]),
+			Name:            row["name"],
+			Type:            typ,
+			PrimaryExchange: row["primary_exchange"],
+			CIK:             row["cik"],
+			Active:          active,
+			Currency:        strings.ToUpper(row["currency"]),
+			Sector:          row["sector"],
+		})
+	}
+	return tickers, nil
+}
+
+// Ensure Provider implements provider.MarketData
+var _ provider.MarketData = (*Provider)(nil)
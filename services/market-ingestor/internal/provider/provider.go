@@ -0,0 +1,90 @@
+// Package provider defines the market data source the ingestor reads from,
+// so vendors (Polygon) and offline sources (CSV files) are interchangeable.
+package provider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+var (
+	// ErrRateLimited is returned when the source keeps throttling requests
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnauthorized is returned when the source rejects our credentials
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is returned when the requested data does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrUnsupported is returned for requests a source cannot serve
+	ErrUnsupported = errors.New("unsupported request")
+)
+
+// Timespans accepted in AggregatesRequest
+const (
+	TimespanMinute = "minute"
+	TimespanHour   = "hour"
+	TimespanDay    = "day"
+)
+
+// AggregatesRequest selects bars of Multiplier x Timespan for Symbol
+// between From and To (inclusive)
+type AggregatesRequest struct {
+	Symbol     string
+	Multiplier int
+	Timespan   string
+	From       time.Time
+	To         time.Time
+}
+
+// MarketData is a source of market data for ingestion
+type MarketData interface {
+	// GetGroupedDaily returns the daily bar of every symbol traded on date
+	GetGroupedDaily(ctx context.Context, date time.Time) ([]models.DailyBar, error)
+
+	// GetPreviousClose returns the most recent completed daily bar for symbol
+	GetPreviousClose(ctx context.Context, symbol string) (*models.DailyBar, error)
+
+	// GetAggregates returns bars for a symbol over a range as traded, ordered
+	// by time
+	GetAggregates(ctx context.Context, req AggregatesRequest) ([]models.Aggregate, error)
+
+	// GetTickers returns reference data for all active tickers
+	GetTickers(ctx context.Context) ([]models.Ticker, error)
+}
+
+// MinuteUpdate is one streamed minute bar together with the session totals
+// the source reports alongside it
+type MinuteUpdate struct {
+	Bar models.Aggregate
+	// DayOpen, DayVolume and DayVWAP describe the session so far; zero when
+	// the source does not report them
+	DayOpen   float64
+	DayVolume int64
+	DayVWAP   float64
+}
+
+// Streamer is a live source of per-minute bars
+type Streamer interface {
+	// StreamMinutes calls handle with each minute bar for symbols until ctx
+	// is done, reconnecting after dropped connections. It returns ctx's error,
+	// or a permanent failure such as ErrUnauthorized.
+	StreamMinutes(ctx context.Context, symbols []string, handle func(MinuteUpdate)) error
+}
+
+// CorporateActions is implemented by sources that report splits and
+// dividends
+type CorporateActions interface {
+	// GetCorporateActions returns the splits and cash dividends of every
+	// symbol whose ex date falls between from and to (inclusive)
+	GetCorporateActions(ctx context.Context, from, to time.Time) ([]models.CorporateAction, error)
+}
+
+// TickerSectors is implemented by sources that classify tickers by sector
+// one request at a time, too slowly to include in GetTickers
+type TickerSectors interface {
+	// GetTickerSector returns symbol's sector, or "" when the source does
+	// not classify it
+	GetTickerSector(ctx context.Context, symbol string) (string, error)
+}
@@ -0,0 +1,100 @@
+// Package ratelimit throttles outgoing API requests and paces their
+// retries.
+package ratelimit
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a simple token-bucket rate limiter. Tokens refill
+// continuously at rate per second up to burst. A nil *TokenBucket never
+// blocks.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// New returns a limiter allowing perMinute requests per minute with bursts
+// of up to burst requests. A non-positive perMinute disables limiting.
+func New(perMinute, burst int) *TokenBucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   float64(perMinute) / 60,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Backoff returns an exponential delay with full jitter for a 0-based retry
+// attempt, starting from base and capped at ceiling
+func Backoff(base, ceiling time.Duration, attempt int) time.Duration {
+	limit := base << attempt
+	if limit <= 0 || limit > ceiling {
+		limit = ceiling
+	}
+	return time.Duration(rand.Int64N(int64(limit))) + 1
+}
+
+// ParseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date
+func ParseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+)
+
+// actionsLookback is how far before the ingested date the nightly run
+// re-fetches corporate actions, catching late announcements and corrections
+const actionsLookback = 7 * 24 * time.Hour
+
+// ActionsHook runs after corporate actions are saved
+type ActionsHook func(ctx context.Context, actions []models.CorporateAction)
+
+// OnActions registers a hook to run after each batch of saved corporate
+// actions, such as one discarding state derived from the symbols' old
+// adjustments. Hooks must be registered before Start or any backfill.
+func (s *Scheduler) OnActions(hook ActionsHook) {
+	s.actHooks = append(s.actHooks, hook)
+}
+
+// BackfillCorporateActions fetches and saves splits and dividends with ex
+// dates from..to (inclusive). Saving recomputes the adjustment factors of
+// every symbol involved.
+func (s *Scheduler) BackfillCorporateActions(ctx context.Context, from, to time.Time) error {
+	source, ok := s.data.(provider.CorporateActions)
+	if !ok {
+		return fmt.Errorf("fetching corporate actions: %w", provider.ErrUnsupported)
+	}
+
+	actions, err := source.GetCorporateActions(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("fetching corporate actions: %w", err)
+	}
+	if err := s.store.SaveCorporateActions(ctx, actions); err != nil {
+		return fmt.Errorf("saving corporate actions: %w", err)
+	}
+	s.logger.Info("saved corporate actions", "count", len(actions),
+		"from", from.Format("2006-01-02"), "to", to.Format("2006-01-02"))
+
+	if len(actions) > 0 {
+		for _, hook := range s.actHooks {
+			hook(ctx, actions)
+		}
+	}
+	return nil
+}
+
+// ingestCorporateActions refreshes the week of actions up to date, when the
+// provider publishes them
+func (s *Scheduler) ingestCorporateActions(date time.Time) {
+	if _, ok := s.data.(provider.CorporateActions); !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := s.BackfillCorporateActions(ctx, date.Add(-actionsLookback), date); err != nil {
+		s.logger.Error("failed to fetch corporate actions", "error", err)
+	}
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// IngestEvent describes a day of bars that has just been saved
+type IngestEvent struct {
+	Date time.Time
+	Bars []models.DailyBar
+	// Backfill is set when the day was saved by a backfill rather than the
+	// scheduled EOD ingest
+	Backfill bool
+}
+
+// IngestHook runs after each saved day, once the built-in stages are done
+type IngestHook func(ctx context.Context, ev IngestEvent)
+
+// OnIngest registers a hook to run after each saved day. Hooks run in
+// registration order and must be registered before Start or any backfill.
+func (s *Scheduler) OnIngest(hook IngestHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// postIngest runs the analytics stages that derive data from a freshly
+// saved day of bars. Stages log their own failures so one failing stage
+// does not block the rest.
+func (s *Scheduler) postIngest(ctx context.Context, ev IngestEvent) {
+	day := ev.Date.Format("2006-01-02")
+
+	if err := s.store.UpdateVolumeStats(ctx, ev.Date); err != nil {
+		s.logger.Error("failed to update volume stats", "date", day, "error", err)
+	}
+
+	if err := s.updateSectors(ctx, ev.Date); err != nil {
+		s.logger.Error("failed to update sectors", "date", day, "error", err)
+	}
+
+	// Strength and signals judge the latest session against a year of
+	// history, which backfilled days are not
+	if !ev.Backfill {
+		if err := s.updateStrength(ctx, ev.Date); err != nil {
+			s.logger.Error("failed to update strength scores", "date", day, "error", err)
+		}
+		if err := s.updateSignals(ctx, ev.Date); err != nil {
+			s.logger.Error("failed to detect signals", "date", day, "error", err)
+		}
+	}
+
+	for _, hook := range s.hooks {
+		hook(ctx, ev)
+	}
+
+	s.publishIngest(ctx, ev)
+}
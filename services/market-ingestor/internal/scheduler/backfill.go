@@ -0,0 +1,294 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+)
+
+// ErrBackfillRunning is returned when a backfill is requested while one is in progress
+var ErrBackfillRunning = errors.New("backfill already running")
+
+// MaxBackfillConcurrency caps simultaneous grouped daily fetches so a
+// request cannot exhaust the provider's rate limit or spawn unbounded workers
+const MaxBackfillConcurrency = 8
+
+// BackfillRequest describes a range of trading days to ingest
+type BackfillRequest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Concurrency bounds simultaneous grouped daily fetches, up to
+	// MaxBackfillConcurrency; zero uses Options.BackfillConcurrency
+	Concurrency int `json:"concurrency"`
+	// Force re-ingests dates that already have stored bars
+	Force bool `json:"force"`
+}
+
+// BackfillProgress reports the state of the current or most recent backfill
+type BackfillProgress struct {
+	Running     bool      `json:"running"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	TradingDays int       `json:"trading_days"`
+	Skipped     int       `json:"skipped"`
+	Completed   int       `json:"completed"`
+	Failed      int       `json:"failed"`
+	FailedDates []string  `json:"failed_dates"`
+	LastDate    string    `json:"last_date,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+}
+
+// backfillState guards the progress of the single backfill a scheduler runs at a time
+type backfillState struct {
+	mu       sync.Mutex
+	running  bool
+	cancel   context.CancelFunc
+	progress BackfillProgress
+}
+
+type backfillResult struct {
+	index int
+	date  time.Time
+	bars  []models.DailyBar
+	err   error
+}
+
+// StartBackfill runs a backfill in the background. Use BackfillStatus to
+// follow its progress; Stop cancels it.
+func (s *Scheduler) StartBackfill(req BackfillRequest) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := s.beginBackfill(req, cancel); err != nil {
+		cancel()
+		return err
+	}
+
+	go func() {
+		defer cancel()
+		if err := s.runBackfill(ctx, req); err != nil {
+			s.logger.Error("backfill failed", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// Backfill runs a backfill synchronously and returns its final progress
+func (s *Scheduler) Backfill(ctx context.Context, req BackfillRequest) (BackfillProgress, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if err := s.beginBackfill(req, cancel); err != nil {
+		return BackfillProgress{}, err
+	}
+
+	err := s.runBackfill(ctx, req)
+	return s.BackfillStatus(), err
+}
+
+// BackfillStatus returns a snapshot of the current or most recent backfill
+func (s *Scheduler) BackfillStatus() BackfillProgress {
+	s.backfill.mu.Lock()
+	defer s.backfill.mu.Unlock()
+
+	progress := s.backfill.progress
+	progress.FailedDates = append([]string(nil), progress.FailedDates...)
+	return progress
+}
+
+func (s *Scheduler) beginBackfill(req BackfillRequest, cancel context.CancelFunc) error {
+	if req.From.IsZero() || req.To.IsZero() {
+		return errors.New("backfill requires both from and to dates")
+	}
+	if req.From.After(req.To) {
+		return errors.New("backfill from date must not be after to date")
+	}
+	if req.Concurrency < 0 || req.Concurrency > MaxBackfillConcurrency {
+		return fmt.Errorf("backfill concurrency must be between 0 and %d", MaxBackfillConcurrency)
+	}
+
+	s.backfill.mu.Lock()
+	defer s.backfill.mu.Unlock()
+
+	if s.backfill.running {
+		return ErrBackfillRunning
+	}
+	s.backfill.running = true
+	s.backfill.cancel = cancel
+	s.backfill.progress = BackfillProgress{
+		Running:   true,
+		From:      calendar.Date(req.From),
+		To:        calendar.Date(req.To),
+		StartedAt: time.Now(),
+	}
+	return nil
+}
+
+// runBackfill fetches pending trading days concurrently but saves them in
+// date order, so each day's change is computed from the prior day's stored
+// closes and an interrupted run can resume where it stopped.
+func (s *Scheduler) runBackfill(ctx context.Context, req BackfillRequest) error {
+	defer s.updateBackfill(func(p *BackfillProgress) {
+		p.Running = false
+		p.FinishedAt = time.Now()
+	})
+	defer func() {
+		s.backfill.mu.Lock()
+		s.backfill.running = false
+		s.backfill.cancel = nil
+		s.backfill.mu.Unlock()
+	}()
+
+	days := calendar.TradingDays(req.From, req.To)
+	pending := days
+	if !req.Force {
+		stored := make(map[string]bool)
+		dates, err := s.store.GetStoredDates(ctx, calendar.Date(req.From), calendar.Date(req.To))
+		if err != nil {
+			return fmt.Errorf("loading stored dates: %w", err)
+		}
+		for _, d := range dates {
+			stored[d.Format("2006-01-02")] = true
+		}
+		pending = make([]time.Time, 0, len(days))
+		for _, d := range days {
+			if !stored[d.Format("2006-01-02")] {
+				pending = append(pending, d)
+			}
+		}
+	}
+
+	s.updateBackfill(func(p *BackfillProgress) {
+		p.TradingDays = len(days)
+		p.Skipped = len(days) - len(pending)
+	})
+	s.logger.Info("starting backfill",
+		"from", req.From.Format("2006-01-02"), "to", req.To.Format("2006-01-02"),
+		"trading_days", len(days), "pending", len(pending))
+
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = s.opts.BackfillConcurrency
+	}
+	if concurrency <= 0 {
+		concurrency = 2
+	}
+	concurrency = min(concurrency, MaxBackfillConcurrency)
+
+	jobs := make(chan int)
+	results := make(chan backfillResult)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				bars, err := s.data.GetGroupedDaily(ctx, pending[i])
+				select {
+				case results <- backfillResult{index: i, date: pending[i], bars: bars, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range pending {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Buffer out-of-order results until the next date in sequence arrives
+	buffered := make(map[int]backfillResult)
+	next := 0
+	for res := range results {
+		buffered[res.index] = res
+		for {
+			r, ok := buffered[next]
+			if !ok {
+				break
+			}
+			delete(buffered, next)
+			next++
+			s.saveBackfillDay(ctx, r)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("backfill interrupted: %w", err)
+	}
+
+	progress := s.BackfillStatus()
+	s.logger.Info("backfill complete",
+		"completed", progress.Completed, "skipped", progress.Skipped, "failed", progress.Failed)
+	return nil
+}
+
+func (s *Scheduler) saveBackfillDay(ctx context.Context, r backfillResult) {
+	day := r.date.Format("2006-01-02")
+
+	err := r.err
+	if err == nil && len(r.bars) == 0 {
+		err = errors.New("no bars returned")
+	}
+	if err == nil {
+		s.applyChanges(ctx, r.date, r.bars)
+		err = s.store.SaveDailyBars(ctx, r.bars)
+	}
+
+	if errors.Is(err, provider.ErrUnauthorized) {
+		// Every remaining day would fail the same way
+		s.logger.Error("aborting backfill, API key rejected", "date", day, "error", err)
+		s.cancelBackfill()
+	}
+	if err != nil {
+		s.logger.Error("backfill day failed", "date", day, "error", err)
+		s.updateBackfill(func(p *BackfillProgress) {
+			p.Failed++
+			p.FailedDates = append(p.FailedDates, day)
+		})
+		return
+	}
+
+	s.postIngest(ctx, IngestEvent{Date: r.date, Bars: r.bars, Backfill: true})
+
+	progress := s.updateBackfill(func(p *BackfillProgress) {
+		p.Completed++
+		p.LastDate = day
+	})
+	s.logger.Info("backfilled day", "date", day, "bars", len(r.bars),
+		"done", progress.Completed+progress.Failed, "pending", progress.TradingDays-progress.Skipped)
+}
+
+func (s *Scheduler) updateBackfill(fn func(p *BackfillProgress)) BackfillProgress {
+	s.backfill.mu.Lock()
+	defer s.backfill.mu.Unlock()
+	fn(&s.backfill.progress)
+	return s.backfill.progress
+}
+
+// cancelBackfill stops any running backfill
+func (s *Scheduler) cancelBackfill() {
+	s.backfill.mu.Lock()
+	defer s.backfill.mu.Unlock()
+	if s.backfill.cancel != nil {
+		s.backfill.cancel()
+	}
+}
@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+)
+
+// applyChanges fills PrevClose, Change, ChangePct and ChangeBasis for bars
+// traded on date. Prior closes come from the store; symbols missing one are
+// looked up one at a time, or, when more than PrevCloseMaxLookups are
+// missing, from the previous trading day's grouped daily. Anything still
+// unresolved, such as a new listing, uses the configured fallback basis.
+// Prior closes of symbols splitting on date are restated in post-split
+// terms, so the ex date does not read as a crash.
+func (s *Scheduler) applyChanges(ctx context.Context, date time.Time, bars []models.DailyBar) {
+	prevDay := calendar.PreviousTradingDay(date)
+	closes, err := s.store.GetClosesOn(ctx, prevDay)
+	if err != nil {
+		s.logger.Warn("failed to load stored prior closes", "error", err)
+		closes = make(map[string]float64)
+	}
+
+	missing := missingCloses(bars, closes)
+	switch {
+	case len(missing) == 0:
+	case len(missing) <= s.opts.PrevCloseMaxLookups:
+		s.lookupCloses(ctx, prevDay, missing, closes)
+	default:
+		s.logger.Info("fetching previous day grouped data for prior closes",
+			"date", prevDay.Format("2006-01-02"), "missing", len(missing))
+		prevBars, err := s.data.GetGroupedDaily(ctx, prevDay)
+		if err != nil {
+			s.logger.Warn("failed to fetch previous day grouped data", "error", err)
+		}
+		for _, bar := range prevBars {
+			if _, ok := closes[bar.Symbol]; !ok {
+				closes[bar.Symbol] = bar.Close
+			}
+		}
+	}
+
+	factors, err := s.splitFactors(ctx, date)
+	if err != nil {
+		s.logger.Warn("failed to load splits", "error", err)
+	}
+	for symbol, factor := range factors {
+		if c, ok := closes[symbol]; ok {
+			closes[symbol] = c * factor
+		}
+	}
+
+	counts := make(map[models.ChangeBasis]int)
+	for i := range bars {
+		computeChange(&bars[i], closes[bars[i].Symbol], s.opts.ChangeFallback)
+		counts[bars[i].ChangeBasis]++
+	}
+
+	s.logger.Info("computed daily changes",
+		"prev_close", counts[models.ChangeBasisPrevClose],
+		"open", counts[models.ChangeBasisOpen],
+		"none", counts[models.ChangeBasisNone])
+}
+
+// lookupCloses fetches the daily bar on prevDay of each symbol, adding its
+// close to closes. Symbols that did not trade that day are left out.
+func (s *Scheduler) lookupCloses(ctx context.Context, prevDay time.Time, symbols []string, closes map[string]float64) {
+	found := 0
+	for _, symbol := range symbols {
+		aggs, err := s.data.GetAggregates(ctx, provider.AggregatesRequest{
+			Symbol:     symbol,
+			Multiplier: 1,
+			Timespan:   provider.TimespanDay,
+			From:       prevDay,
+			To:         prevDay,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Warn("failed to look up prior close", "symbol", symbol, "error", err)
+			continue
+		}
+		if len(aggs) > 0 && aggs[len(aggs)-1].Close > 0 {
+			closes[symbol] = aggs[len(aggs)-1].Close
+			found++
+		}
+	}
+	s.logger.Info("looked up prior closes", "date", prevDay.Format("2006-01-02"),
+		"missing", len(symbols), "found", found)
+}
+
+// splitFactors returns the price factor of each symbol's splits taking
+// effect on date
+func (s *Scheduler) splitFactors(ctx context.Context, date time.Time) (map[string]float64, error) {
+	actions, err := s.store.GetCorporateActionsBetween(ctx, date, date)
+	if err != nil {
+		return nil, err
+	}
+	factors := make(map[string]float64)
+	for _, a := range actions {
+		if a.Type != models.ActionSplit || a.PriceFactor <= 0 {
+			continue
+		}
+		if f, ok := factors[a.Symbol]; ok {
+			factors[a.Symbol] = f * a.PriceFactor
+		} else {
+			factors[a.Symbol] = a.PriceFactor
+		}
+	}
+	return factors, nil
+}
+
+// computeChange sets change fields on bar against prevClose, or against the
+// fallback basis when prevClose is not positive
+func computeChange(bar *models.DailyBar, prevClose float64, fallback models.ChangeBasis) {
+	bar.PrevClose = 0
+	bar.Change = 0
+	bar.ChangePct = 0
+
+	switch {
+	case prevClose > 0:
+		bar.PrevClose = prevClose
+		bar.Change = bar.Close - prevClose
+		bar.ChangePct = (bar.Change / prevClose) * 100
+		bar.ChangeBasis = models.ChangeBasisPrevClose
+	case fallback == models.ChangeBasisOpen && bar.Open > 0:
+		bar.Change = bar.Close - bar.Open
+		bar.ChangePct = (bar.Change / bar.Open) * 100
+		bar.ChangeBasis = models.ChangeBasisOpen
+	default:
+		bar.ChangeBasis = models.ChangeBasisNone
+	}
+}
+
+// missingCloses returns the symbols in bars that have no entry in closes
+func missingCloses(bars []models.DailyBar, closes map[string]float64) []string {
+	var missing []string
+	for _, bar := range bars {
+		if _, ok := closes[bar.Symbol]; !ok {
+			missing = append(missing, bar.Symbol)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,170 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
+)
+
+// fakeData serves grouped daily bars by date, and daily aggregates cut
+// from them, counting the requests of each kind
+type fakeData struct {
+	grouped map[string][]models.DailyBar // YYYY-MM-DD -> bars
+
+	groupedCalls int
+	aggCalls     int
+}
+
+func (f *fakeData) GetGroupedDaily(ctx context.Context, date time.Time) ([]models.DailyBar, error) {
+	f.groupedCalls++
+	return append([]models.DailyBar(nil), f.grouped[date.Format("2006-01-02")]...), nil
+}
+
+func (f *fakeData) GetPreviousClose(ctx context.Context, symbol string) (*models.DailyBar, error) {
+	return nil, provider.ErrUnsupported
+}
+
+func (f *fakeData) GetAggregates(ctx context.Context, req provider.AggregatesRequest) ([]models.Aggregate, error) {
+	f.aggCalls++
+	var aggs []models.Aggregate
+	for _, day := range calendar.TradingDays(req.From, req.To) {
+		for _, bar := range f.grouped[day.Format("2006-01-02")] {
+			if bar.Symbol == req.Symbol {
+				aggs = append(aggs, models.Aggregate{
+					Symbol: bar.Symbol, Timestamp: day,
+					Open: bar.Open, High: bar.High, Low: bar.Low, Close: bar.Close, Volume: bar.Volume,
+				})
+			}
+		}
+	}
+	return aggs, nil
+}
+
+func (f *fakeData) GetTickers(ctx context.Context) ([]models.Ticker, error) {
+	return []models.Ticker{}, nil
+}
+
+func TestApplyChangesFindsMissingPriorCloses(t *testing.T) {
+	date := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	prevDay := calendar.PreviousTradingDay(date)
+	stored := models.DailyBar{Symbol: "KEPT", Date: prevDay, Open: 10, High: 10, Low: 10, Close: 10, Volume: 100}
+	data := &fakeData{grouped: map[string][]models.DailyBar{
+		prevDay.Format("2006-01-02"): {
+			stored,
+			{Symbol: "GONE", Date: prevDay, Open: 20, High: 20, Low: 20, Close: 20, Volume: 100},
+			{Symbol: "LATE", Date: prevDay, Open: 40, High: 40, Low: 40, Close: 40, Volume: 100},
+		},
+	}}
+	today := func() []models.DailyBar {
+		return []models.DailyBar{
+			{Symbol: "KEPT", Date: date, Open: 10, Close: 11},
+			{Symbol: "GONE", Date: date, Open: 20, Close: 22},
+			{Symbol: "LATE", Date: date, Open: 40, Close: 30},
+			{Symbol: "NEW", Date: date, Open: 5, Close: 6},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		maxLookups int
+		grouped    int
+		aggs       int
+	}{
+		{"looked up one at a time", 25, 0, 3},
+		{"from the grouped daily", 2, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.DiscardHandler)
+			mem := store.NewMemoryStore(store.MemoryOptions{Logger: logger})
+			if err := mem.SaveDailyBars(context.Background(), []models.DailyBar{stored}); err != nil {
+				t.Fatalf("saving prior bar: %v", err)
+			}
+			data.groupedCalls, data.aggCalls = 0, 0
+			s := New(data, mem, logger, Options{
+				ChangeFallback:      models.ChangeBasisOpen,
+				PrevCloseMaxLookups: tt.maxLookups,
+			})
+
+			bars := today()
+			s.applyChanges(context.Background(), date, bars)
+
+			if data.groupedCalls != tt.grouped || data.aggCalls != tt.aggs {
+				t.Errorf("made %d grouped and %d aggregate requests, want %d and %d",
+					data.groupedCalls, data.aggCalls, tt.grouped, tt.aggs)
+			}
+			want := []struct {
+				basis     models.ChangeBasis
+				changePct float64
+			}{
+				{models.ChangeBasisPrevClose, 10},
+				{models.ChangeBasisPrevClose, 10},
+				{models.ChangeBasisPrevClose, -25},
+				{models.ChangeBasisOpen, 20},
+			}
+			for i, bar := range bars {
+				if bar.ChangeBasis != want[i].basis || bar.ChangePct != want[i].changePct {
+					t.Errorf("%s: basis %s change %.2f%%, want %s %.2f%%",
+						bar.Symbol, bar.ChangeBasis, bar.ChangePct, want[i].basis, want[i].changePct)
+				}
+			}
+		})
+	}
+}
+
+// sectorData classifies tickers from a fixed map, counting lookups
+type sectorData struct {
+	fakeData
+	sectors map[string]string
+	lookups int
+}
+
+func (d *sectorData) GetTickerSector(ctx context.Context, symbol string) (string, error) {
+	d.lookups++
+	return d.sectors[symbol], nil
+}
+
+func TestFillSectors(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.DiscardHandler)
+	mem := store.NewMemoryStore(store.MemoryOptions{Logger: logger})
+	err := mem.SaveTickers(ctx, []models.Ticker{{Symbol: "KNOWN", Type: "CS", Sector: "Energy"}})
+	if err != nil {
+		t.Fatalf("saving tickers: %v", err)
+	}
+
+	data := &sectorData{sectors: map[string]string{"AAPL": "Technology", "BANK": "Financials"}}
+	s := New(data, mem, logger, Options{SectorLookups: 10})
+	tickers := []models.Ticker{
+		{Symbol: "AAPL", Type: "CS"},
+		{Symbol: "BANK", Type: "ADRC"},
+		{Symbol: "KNOWN", Type: "CS"},
+		{Symbol: "LISTED", Type: "CS", Sector: "Utilities"},
+		{Symbol: "SPY", Type: "ETF"},
+		{Symbol: "SHELL", Type: "CS"},
+	}
+	s.fillSectors(ctx, tickers)
+
+	want := []string{"Technology", "Financials", "", "Utilities", "", ""}
+	for i, ticker := range tickers {
+		if ticker.Sector != want[i] {
+			t.Errorf("%s sector = %q, want %q", ticker.Symbol, ticker.Sector, want[i])
+		}
+	}
+	if data.lookups != 3 {
+		t.Errorf("made %d lookups, want 3 (stored, listed and fund sectors skipped)", data.lookups)
+	}
+
+	data.lookups = 0
+	s.opts.SectorLookups = 1
+	s.fillSectors(ctx, []models.Ticker{{Symbol: "AAPL", Type: "CS"}, {Symbol: "BANK", Type: "CS"}})
+	if data.lookups != 1 {
+		t.Errorf("made %d lookups, want SectorLookups = 1", data.lookups)
+	}
+}
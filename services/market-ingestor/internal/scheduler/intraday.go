@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+)
+
+// BackfillIntraday fetches and saves minute bars for each symbol over the
+// dates from..to (inclusive). Failures for one symbol do not stop the
+// others, except a rejected API key, which would fail them all.
+func (s *Scheduler) BackfillIntraday(ctx context.Context, symbols []string, from, to time.Time) error {
+	var errs []error
+	for _, symbol := range symbols {
+		bars, err := s.data.GetAggregates(ctx, provider.AggregatesRequest{
+			Symbol:     symbol,
+			Multiplier: 1,
+			Timespan:   provider.TimespanMinute,
+			From:       from,
+			To:         to,
+		})
+		if err == nil {
+			err = s.store.SaveMinuteBars(ctx, bars)
+		}
+		if err != nil {
+			if errors.Is(err, provider.ErrUnauthorized) || errors.Is(err, provider.ErrUnsupported) || ctx.Err() != nil {
+				return fmt.Errorf("fetching minute bars for %s: %w", symbol, err)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+		s.logger.Info("saved minute bars", "symbol", symbol, "bars", len(bars),
+			"from", from.Format("2006-01-02"), "to", to.Format("2006-01-02"))
+	}
+	return errors.Join(errs...)
+}
+
+// ingestIntraday fills in the day's minute bars for Options.IntradaySymbols,
+// covering anything the stream missed or all of it when streaming is off
+func (s *Scheduler) ingestIntraday(date time.Time) {
+	if len(s.opts.IntradaySymbols) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	s.logger.Info("fetching minute bars", "date", date.Format("2006-01-02"), "symbols", len(s.opts.IntradaySymbols))
+	if err := s.BackfillIntraday(ctx, s.opts.IntradaySymbols, date, date); err != nil {
+		s.logger.Error("failed to fetch minute bars", "error", err)
+	}
+}
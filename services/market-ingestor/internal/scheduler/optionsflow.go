@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/robfig/cron/v3"
+)
+
+// defaultOptionsFlowInterval is how often flow alerts are polled when
+// Options.OptionsFlowInterval is not set
+const defaultOptionsFlowInterval = time.Minute
+
+// scheduleOptionsFlow polls options flow every interval while the session
+// is open. A poll due while the last is still running is skipped.
+func (s *Scheduler) scheduleOptionsFlow() {
+	if s.opts.OptionsFlow == nil {
+		return
+	}
+	interval := s.opts.OptionsFlowInterval
+	if interval <= 0 {
+		interval = defaultOptionsFlowInterval
+	}
+
+	job := cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).Then(cron.FuncJob(func() {
+		s.pollOptionsFlow(interval)
+	}))
+	s.cron.Schedule(cron.Every(interval), job)
+	s.logger.Info("options flow polling enabled", "interval", interval)
+}
+
+// pollOptionsFlow polls once if now is within a trading session, giving the
+// poll up to twice the interval
+func (s *Scheduler) pollOptionsFlow(interval time.Duration) {
+	now := time.Now().In(s.location)
+	if !calendar.IsTradingDay(now) || now.Before(calendar.OpenTime(now)) || now.After(calendar.CloseTime(now)) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*interval)
+	defer cancel()
+
+	if _, err := s.opts.OptionsFlow.Poll(ctx); err != nil {
+		s.logger.Error("failed to poll options flow", "error", err)
+	}
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/events"
+)
+
+// BarsEvent is the payload of an events.TypeBars event
+type BarsEvent struct {
+	Date     time.Time `json:"date"`
+	Symbols  int       `json:"symbols"`
+	Backfill bool      `json:"backfill"`
+}
+
+// Publish sends an event to live clients, if a publisher is configured
+func (s *Scheduler) Publish(eventType string, data any) {
+	if s.opts.Events != nil {
+		s.opts.Events.Publish(eventType, data)
+	}
+}
+
+// publishIngest announces a saved day and any change to index data
+func (s *Scheduler) publishIngest(ctx context.Context, ev IngestEvent) {
+	if s.opts.Events == nil {
+		return
+	}
+
+	s.Publish(events.TypeBars, BarsEvent{Date: ev.Date, Symbols: len(ev.Bars), Backfill: ev.Backfill})
+
+	indices, err := s.store.GetIndices(ctx)
+	if err != nil {
+		s.logger.Error("failed to load indices for publishing", "error", err)
+		return
+	}
+	// Stores return indices in no particular order; sort them so the
+	// comparison with the last published set is stable
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i].Symbol < indices[j].Symbol
+	})
+
+	s.publishMu.Lock()
+	defer s.publishMu.Unlock()
+	if slices.Equal(indices, s.lastIndices) {
+		return
+	}
+	s.lastIndices = indices
+	s.Publish(events.TypeIndices, indices)
+}
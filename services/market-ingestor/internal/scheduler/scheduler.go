@@ -2,51 +2,152 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"math/rand/v2"
+	"sync"
 	"time"
 
-	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/polygon"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/events"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/optionsflow"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/signals"
 	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
 	"github.com/robfig/cron/v3"
 )
 
+// tickerRefreshInterval is how stale reference data may get before startup refreshes it
+const tickerRefreshInterval = 7 * 24 * time.Hour
+
+// sectorLookupTimeout bounds sector lookups, leaving the rest of a ticker
+// refresh's time to save the list
+const sectorLookupTimeout = 10 * time.Minute
+
+// Options configures ingestion behavior
+type Options struct {
+	// ChangeFallback is the basis used when a symbol has no prior close
+	ChangeFallback models.ChangeBasis
+	// PrevCloseMaxLookups is how many symbols missing a stored prior close
+	// are looked up one at a time; when more are missing, the previous
+	// day's grouped daily is fetched instead
+	PrevCloseMaxLookups int
+	// BackfillConcurrency is the default number of concurrent backfill fetches
+	BackfillConcurrency int
+	// SectorLookups is how many tickers without a sector each ticker
+	// refresh classifies, when the source implements provider.TickerSectors
+	SectorLookups int
+	// Events receives bars and index updates for live clients; nil disables
+	Events events.Publisher
+	// Stream, when set, delivers live minute bars for StreamSymbols, which
+	// are kept as intraday bars and session snapshots
+	Stream        provider.Streamer
+	StreamSymbols []string
+	// IntradaySymbols have their minute bars fetched after each EOD ingest
+	IntradaySymbols []string
+	// StrengthUniverse selects the tickers ranked by strength after each
+	// EOD ingest; the zero filter ranks every ticker
+	StrengthUniverse models.ScreenerFilter
+	// SignalUniverse selects the tickers the signal detectors run over
+	// after each EOD ingest; the zero filter checks every ticker
+	SignalUniverse models.ScreenerFilter
+	// OptionsFlow, when set, is polled for options flow every
+	// OptionsFlowInterval (default one minute) during the session
+	OptionsFlow         *optionsflow.Ingestor
+	OptionsFlowInterval time.Duration
+}
+
 type Scheduler struct {
-	cron    *cron.Cron
-	polygon *polygon.Client
-	store   store.Store
-	logger  *slog.Logger
+	cron     *cron.Cron
+	data     provider.MarketData
+	store    store.Store
+	logger   *slog.Logger
+	location *time.Location
+	opts     Options
+	backfill backfillState
+	hooks    []IngestHook
+	actHooks []ActionsHook
+	stream   streamState
+	signals  *signals.Writer
+
+	publishMu   sync.Mutex
+	lastIndices []models.IndexData
 }
 
-func New(polygonClient *polygon.Client, store store.Store, logger *slog.Logger) *Scheduler {
+func New(data provider.MarketData, store store.Store, logger *slog.Logger, opts Options) *Scheduler {
 	// Use Eastern Time for market hours
-	loc, _ := time.LoadLocation("America/New_York")
+	loc := calendar.Location
 	c := cron.New(cron.WithLocation(loc))
 
 	return &Scheduler{
-		cron:    c,
-		polygon: polygonClient,
-		store:   store,
-		logger:  logger,
+		cron:     c,
+		data:     data,
+		store:    store,
+		logger:   logger,
+		location: loc,
+		opts:     opts,
+		signals:  signals.NewWriter(store, opts.Events, logger),
 	}
 }
 
 func (s *Scheduler) Start() {
-	// Run EOD data ingestion at 4:30 PM ET on weekdays
+	// Run EOD data ingestion 30 minutes after the close on trading days:
+	// 4:30 PM ET normally, 1:30 PM ET on early-close days
 	s.cron.AddFunc("30 16 * * 1-5", func() {
-		s.logger.Info("running scheduled EOD data ingestion")
-		s.ingestDailyData()
+		s.runEOD(false)
+	})
+	s.cron.AddFunc("30 13 * * 1-5", func() {
+		s.runEOD(true)
+	})
+
+	// Refresh ticker reference data weekly, Saturday 6:00 AM ET
+	s.cron.AddFunc("0 6 * * 6", func() {
+		s.logger.Info("running scheduled ticker refresh")
+		s.refreshTickers()
 	})
 
 	// Also run on startup to populate initial data
 	go func() {
+		updated, err := s.store.GetTickersUpdated(context.Background())
+		if err != nil {
+			s.logger.Warn("failed to check ticker refresh time", "error", err)
+		}
+		if err != nil || time.Since(updated) > tickerRefreshInterval {
+			s.logger.Info("running initial ticker refresh")
+			s.refreshTickers()
+		}
 		s.logger.Info("running initial data ingestion")
 		s.ingestDailyData()
 	}()
 
+	s.scheduleOptionsFlow()
+
 	s.cron.Start()
+	s.startStream()
+}
+
+// runEOD runs the scheduled ingestion if today is a trading day whose
+// early-close status matches the cron slot that fired
+func (s *Scheduler) runEOD(earlySlot bool) {
+	today := time.Now().In(s.location)
+	if !calendar.IsTradingDay(today) {
+		if !earlySlot {
+			s.logger.Info("skipping EOD data ingestion on non-trading day", "date", today.Format("2006-01-02"))
+		}
+		return
+	}
+	if calendar.IsEarlyClose(today) != earlySlot {
+		return
+	}
+
+	s.logger.Info("running scheduled EOD data ingestion", "early_close", earlySlot)
+	s.ingestDailyData()
 }
 
 func (s *Scheduler) Stop() {
+	s.cancelBackfill()
+	s.stopStream()
 	ctx := s.cron.Stop()
 	<-ctx.Done()
 }
@@ -55,12 +156,12 @@ func (s *Scheduler) ingestDailyData() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
-	// Get previous trading day (simplified - doesn't account for holidays)
+	// Get previous trading day from the exchange calendar
 	date := getPreviousTradingDay()
 
 	s.logger.Info("fetching grouped daily data", "date", date.Format("2006-01-02"))
 
-	bars, err := s.polygon.GetGroupedDaily(ctx, date)
+	bars, err := s.data.GetGroupedDaily(ctx, date)
 	if err != nil {
 		s.logger.Error("failed to fetch grouped daily data", "error", err)
 		return
@@ -68,39 +169,111 @@ func (s *Scheduler) ingestDailyData() {
 
 	s.logger.Info("fetched daily bars", "count", len(bars))
 
-	// Calculate change percentages
-	for i := range bars {
-		if bars[i].Open > 0 {
-			bars[i].Change = bars[i].Close - bars[i].Open
-			bars[i].ChangePct = (bars[i].Change / bars[i].Open) * 100
-		}
-	}
+	// Save the day's splits first: change, volume stats, strength and
+	// signals judge the new bars against split-adjusted history, and an ex
+	// date without its split looks like a crash
+	s.ingestCorporateActions(date)
+
+	// Calculate change vs the prior trading day close
+	s.applyChanges(ctx, date, bars)
 
 	// Store the data
-	if err := s.store.SaveDailyBars(bars); err != nil {
+	if err := s.store.SaveDailyBars(ctx, bars); err != nil {
 		s.logger.Error("failed to save daily bars", "error", err)
 		return
 	}
 
+	s.postIngest(ctx, IngestEvent{Date: date, Bars: bars})
+
 	s.logger.Info("daily data ingestion complete", "symbols", len(bars))
+
+	s.ingestIntraday(date)
 }
 
-func getPreviousTradingDay() time.Time {
-	loc, _ := time.LoadLocation("America/New_York")
-	now := time.Now().In(loc)
+// refreshTickers replaces stored ticker reference data with the provider's list
+func (s *Scheduler) refreshTickers() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
 
-	// If it's before 4:30 PM, use the day before yesterday
-	// Otherwise use yesterday
-	cutoff := time.Date(now.Year(), now.Month(), now.Day(), 16, 30, 0, 0, loc)
-	if now.Before(cutoff) {
-		now = now.AddDate(0, 0, -1)
+	tickers, err := s.data.GetTickers(ctx)
+	if err != nil {
+		s.logger.Error("failed to fetch tickers", "error", err)
+		return
+	}
+	if len(tickers) == 0 {
+		s.logger.Warn("provider returned no tickers, keeping existing reference data")
+		return
+	}
+
+	s.fillSectors(ctx, tickers)
+
+	if err := s.store.SaveTickers(ctx, tickers); err != nil {
+		s.logger.Error("failed to save tickers", "error", err)
+		return
+	}
+
+	s.logger.Info("ticker refresh complete", "tickers", len(tickers))
+}
+
+// fillSectors classifies up to SectorLookups operating companies that have
+// no sector, neither from the provider's list nor stored, one request each.
+// Candidates are shuffled so tickers the source cannot classify do not hold
+// back the rest from one refresh to the next.
+func (s *Scheduler) fillSectors(ctx context.Context, tickers []models.Ticker) {
+	source, ok := s.data.(provider.TickerSectors)
+	if !ok || s.opts.SectorLookups <= 0 {
+		return
+	}
+
+	var candidates []int
+	for i, t := range tickers {
+		// Funds and warrants carry no industry classification
+		if t.Sector != "" || (t.Type != "CS" && t.Type != "ADRC") {
+			continue
+		}
+		if stored, err := s.store.GetTicker(ctx, t.Symbol); err == nil && stored.Sector != "" {
+			continue
+		}
+		candidates = append(candidates, i)
+	}
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	candidates = candidates[:min(len(candidates), s.opts.SectorLookups)]
+
+	ctx, cancel := context.WithTimeout(ctx, sectorLookupTimeout)
+	defer cancel()
+
+	found := 0
+	for _, i := range candidates {
+		sector, err := source.GetTickerSector(ctx, tickers[i].Symbol)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			if !errors.Is(err, provider.ErrNotFound) {
+				s.logger.Warn("failed to look up sector", "symbol", tickers[i].Symbol, "error", err)
+			}
+			continue
+		}
+		if sector != "" {
+			tickers[i].Sector = sector
+			found++
+		}
 	}
+	s.logger.Info("looked up sectors", "candidates", len(candidates), "found", found)
+}
 
-	// Go back to the previous weekday
-	date := now.AddDate(0, 0, -1)
-	for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
-		date = date.AddDate(0, 0, -1)
+func getPreviousTradingDay() time.Time {
+	now := time.Now().In(calendar.Location)
+	today := calendar.Date(now)
+
+	// If it's before today's EOD run (30 minutes after the close), use the
+	// trading day before yesterday. Otherwise use the one before today.
+	cutoff := calendar.CloseTime(today).Add(30 * time.Minute)
+	if now.Before(cutoff) {
+		today = today.AddDate(0, 0, -1)
 	}
 
-	return date
+	return calendar.PreviousTradingDay(today)
 }
@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
+)
+
+// splitProvider serves one grouped daily session and the split taking
+// effect on it
+type splitProvider struct {
+	day    []models.DailyBar
+	action models.CorporateAction
+}
+
+func (p *splitProvider) GetGroupedDaily(ctx context.Context, date time.Time) ([]models.DailyBar, error) {
+	if !calendar.Date(date).Equal(calendar.Date(p.action.ExDate)) {
+		return []models.DailyBar{}, nil
+	}
+	return append([]models.DailyBar(nil), p.day...), nil
+}
+
+func (p *splitProvider) GetPreviousClose(ctx context.Context, symbol string) (*models.DailyBar, error) {
+	return nil, provider.ErrUnsupported
+}
+
+func (p *splitProvider) GetAggregates(ctx context.Context, req provider.AggregatesRequest) ([]models.Aggregate, error) {
+	return []models.Aggregate{}, nil
+}
+
+func (p *splitProvider) GetTickers(ctx context.Context) ([]models.Ticker, error) {
+	return []models.Ticker{}, nil
+}
+
+func (p *splitProvider) GetCorporateActions(ctx context.Context, from, to time.Time) ([]models.CorporateAction, error) {
+	ex := calendar.Date(p.action.ExDate)
+	if ex.Before(calendar.Date(from)) || ex.After(calendar.Date(to)) {
+		return []models.CorporateAction{}, nil
+	}
+	return []models.CorporateAction{p.action}, nil
+}
+
+// TestIngestSplitDay ingests the ex date of a 2-for-1 split through the
+// EOD run and checks the stored change and volume ratio, and the
+// detectors, saw split-adjusted history: the halved price is neither a
+// loss nor a gap down or breakdown, and the doubled share count is not
+// unusual volume.
+func TestIngestSplitDay(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.DiscardHandler)
+	mem := store.NewMemoryStore(store.MemoryOptions{Logger: logger})
+
+	exDate := getPreviousTradingDay()
+	var history []models.DailyBar
+	for d := exDate; len(history) < 30; {
+		d = calendar.PreviousTradingDay(d)
+		history = append(history, models.DailyBar{
+			Symbol: "SPLT", Date: d,
+			Open: 200, High: 202, Low: 198, Close: 200, Volume: 1_000_000,
+		})
+	}
+	if err := mem.SaveDailyBars(ctx, history); err != nil {
+		t.Fatalf("saving history: %v", err)
+	}
+
+	data := &splitProvider{
+		day: []models.DailyBar{{
+			Symbol: "SPLT", Date: exDate,
+			Open: 100.5, High: 101.5, Low: 99, Close: 100, Volume: 2_000_000,
+		}},
+		action: models.CorporateAction{
+			ID: "split-1", Symbol: "SPLT", Type: models.ActionSplit, ExDate: exDate,
+			SplitFrom: 1, SplitTo: 2,
+		},
+	}
+	s := New(data, mem, logger, Options{})
+	s.ingestDailyData()
+
+	bars, err := mem.GetBars(ctx, "SPLT", exDate, exDate, 1)
+	if err != nil || len(bars) != 1 {
+		t.Fatalf("ex-date bar not saved: %v %v", bars, err)
+	}
+	if bar := bars[0]; bar.PrevClose != 100 || bar.ChangePct != 0 {
+		t.Errorf("stored prior close %.2f and change %.2f%%, want 100 and 0%%", bar.PrevClose, bar.ChangePct)
+	}
+	if bar := bars[0]; bar.AvgVolume20 != 2_000_000 || bar.VolumeRatio != 1 {
+		t.Errorf("stored average volume %d and ratio %.2f, want 2000000 and 1", bar.AvgVolume20, bar.VolumeRatio)
+	}
+	actions, err := mem.GetCorporateActions(ctx, "SPLT")
+	if err != nil || len(actions) != 1 || actions[0].PriceFactor != 0.5 {
+		t.Fatalf("split not saved with its factor: %+v %v", actions, err)
+	}
+
+	sigs, err := mem.GetSignals(ctx, models.SignalFilter{Ticker: "SPLT"}, 0)
+	if err != nil {
+		t.Fatalf("loading signals: %v", err)
+	}
+	for _, sig := range sigs {
+		t.Errorf("split day raised %s %v", sig.Source, sig.Tags)
+	}
+}
+
+// TestApplyMinuteSplitDay checks a live snapshot on an ex date measures
+// its change against the prior close in post-split terms
+func TestApplyMinuteSplitDay(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.DiscardHandler)
+	mem := store.NewMemoryStore(store.MemoryOptions{Logger: logger})
+
+	exDate := time.Date(2025, 3, 14, 0, 0, 0, 0, calendar.Location)
+	prior := models.DailyBar{
+		Symbol: "SPLT", Date: calendar.PreviousTradingDay(exDate),
+		Open: 200, High: 202, Low: 198, Close: 200, Volume: 1_000_000,
+	}
+	if err := mem.SaveDailyBars(ctx, []models.DailyBar{prior}); err != nil {
+		t.Fatalf("saving prior bar: %v", err)
+	}
+	err := mem.SaveCorporateActions(ctx, []models.CorporateAction{{
+		ID: "split-1", Symbol: "SPLT", Type: models.ActionSplit, ExDate: exDate, SplitFrom: 1, SplitTo: 2,
+	}})
+	if err != nil {
+		t.Fatalf("saving split: %v", err)
+	}
+
+	s := New(&splitProvider{}, mem, logger, Options{})
+	snap := s.applyMinute(ctx, models.Snapshot{}, provider.MinuteUpdate{Bar: models.Aggregate{
+		Symbol: "SPLT", Timestamp: calendar.OpenTime(exDate),
+		Open: 100, High: 102, Low: 100, Close: 102, Volume: 10_000,
+	}})
+	if snap.PrevClose != 100 || snap.ChangePct != 2 {
+		t.Errorf("snapshot prior close %.2f and change %.2f%%, want 100 and 2%%", snap.PrevClose, snap.ChangePct)
+	}
+}
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/sectors"
+)
+
+// updateSectors derives sector rows and the rotation signal for date from
+// its stored bars, which carry the volume stats computed just before
+func (s *Scheduler) updateSectors(ctx context.Context, date time.Time) error {
+	bars := make(map[string]models.DailyBar)
+	for _, symbol := range sectors.Symbols() {
+		symbolBars, err := s.store.GetBars(ctx, symbol, date, date, 1)
+		if err != nil {
+			return fmt.Errorf("loading %s bar: %w", symbol, err)
+		}
+		if len(symbolBars) > 0 {
+			bars[symbol] = symbolBars[0]
+		}
+	}
+
+	data := sectors.Analyze(bars)
+	if len(data) == 0 {
+		s.logger.Debug("no sector ETF bars to analyze", "date", date.Format("2006-01-02"))
+		return nil
+	}
+	if err := s.store.SaveSectorData(ctx, data); err != nil {
+		return fmt.Errorf("saving sector data: %w", err)
+	}
+
+	rotation, ok := sectors.Rotation(date, data)
+	if !ok {
+		return nil
+	}
+	if err := s.store.SaveSectorRotation(ctx, rotation); err != nil {
+		return fmt.Errorf("saving sector rotation: %w", err)
+	}
+	s.logger.Info("sector rotation", "date", date.Format("2006-01-02"), "signal", rotation.Signal,
+		"leading", rotation.Leading, "lagging", rotation.Lagging)
+	return nil
+}
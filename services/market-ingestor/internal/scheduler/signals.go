@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/adjust"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/signals"
+)
+
+// updateSignals runs the signal detectors over the SignalUniverse tickers
+// with a bar on date, the latest stored session, and writes what they find
+func (s *Scheduler) updateSignals(ctx context.Context, date time.Time) error {
+	symbols, err := s.universeOn(ctx, s.opts.SignalUniverse, date)
+	if err != nil {
+		return err
+	}
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	from := date.Add(-signals.Lookback)
+	actions, err := s.actionsBySymbol(ctx, from, date)
+	if err != nil {
+		return err
+	}
+
+	detector := signals.NewDetector(date)
+	err = s.store.ScanBars(ctx, symbols, from, date, func(symbol string, bars []models.DailyBar) error {
+		detector.Add(symbol, adjust.Bars(bars, actions[symbol], adjust.Split))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("scanning history: %w", err)
+	}
+
+	detected := detector.Signals()
+	saved, err := s.signals.Write(ctx, detected)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("detected signals", "date", date.Format("2006-01-02"),
+		"detected", len(detected), "saved", len(saved), "universe", len(symbols))
+	return nil
+}
@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/events"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+)
+
+// streamState tracks the streaming goroutine Start launches
+type streamState struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startStream begins streaming minute bars when a streamer and symbols are
+// configured
+func (s *Scheduler) startStream() {
+	if s.opts.Stream == nil || len(s.opts.StreamSymbols) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stream = streamState{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(s.stream.done)
+		s.runStream(ctx)
+	}()
+}
+
+// stopStream cancels streaming and waits for the goroutine to exit
+func (s *Scheduler) stopStream() {
+	if s.stream.cancel == nil {
+		return
+	}
+	s.stream.cancel()
+	<-s.stream.done
+}
+
+// runStream saves each streamed minute bar and folds it into its symbol's
+// session snapshot until ctx is done. The first bar of each session
+// refreshes corporate actions, so snapshots on an ex date see its splits.
+func (s *Scheduler) runStream(ctx context.Context) {
+	s.logger.Info("starting minute bar stream", "symbols", len(s.opts.StreamSymbols))
+
+	snapshots := make(map[string]models.Snapshot)
+	stored, err := s.store.GetSnapshots(ctx, s.opts.StreamSymbols)
+	if err != nil {
+		s.logger.Warn("failed to load live snapshots", "error", err)
+	}
+	for _, snap := range stored {
+		snapshots[snap.Symbol] = snap
+	}
+
+	var session time.Time
+	err = s.opts.Stream.StreamMinutes(ctx, s.opts.StreamSymbols, func(u provider.MinuteUpdate) {
+		if date := calendar.Date(u.Bar.Timestamp.In(calendar.Location)); date.After(session) {
+			session = date
+			s.ingestCorporateActions(date)
+		}
+
+		if err := s.store.SaveMinuteBars(ctx, []models.Aggregate{u.Bar}); err != nil {
+			s.logger.Error("failed to save minute bar", "symbol", u.Bar.Symbol, "error", err)
+		}
+
+		snap := s.applyMinute(ctx, snapshots[u.Bar.Symbol], u)
+		snapshots[snap.Symbol] = snap
+		if err := s.store.SaveSnapshot(ctx, snap); err != nil {
+			s.logger.Error("failed to save snapshot", "symbol", snap.Symbol, "error", err)
+			return
+		}
+		s.Publish(events.TypeSnapshot, snap)
+	})
+
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+		s.logger.Info("minute bar stream stopped")
+	case errors.Is(err, provider.ErrUnauthorized):
+		s.logger.Error("minute bar stream stopped, API key rejected", "error", err)
+	default:
+		s.logger.Error("minute bar stream stopped", "error", err)
+	}
+}
+
+// applyMinute returns snap advanced by one minute bar. A bar from a new
+// session starts a fresh snapshot measured against the prior trading day's
+// stored close, restated in post-split terms on an ex date.
+func (s *Scheduler) applyMinute(ctx context.Context, snap models.Snapshot, u provider.MinuteUpdate) models.Snapshot {
+	bar := u.Bar
+	date := calendar.Date(bar.Timestamp.In(calendar.Location))
+
+	if snap.Symbol == "" || !snap.Date.Equal(date) {
+		snap = models.Snapshot{
+			Symbol: bar.Symbol,
+			Date:   date,
+			Open:   bar.Open,
+			High:   bar.High,
+			Low:    bar.Low,
+		}
+		prior, err := s.store.GetBars(ctx, bar.Symbol, time.Time{}, calendar.PreviousTradingDay(date), 1)
+		if err != nil {
+			s.logger.Warn("failed to load prior close", "symbol", bar.Symbol, "error", err)
+		}
+		if len(prior) > 0 {
+			snap.PrevClose = prior[0].Close
+			factors, err := s.splitFactors(ctx, date)
+			if err != nil {
+				s.logger.Warn("failed to load splits", "symbol", bar.Symbol, "error", err)
+			}
+			if f, ok := factors[bar.Symbol]; ok {
+				snap.PrevClose *= f
+			}
+		}
+	}
+
+	// Prefer the session totals the stream reports; they survive a missed
+	// bar or a mid-session restart, while sums of received bars do not
+	if u.DayOpen > 0 {
+		snap.Open = u.DayOpen
+	}
+	snap.High = math.Max(snap.High, bar.High)
+	snap.Low = math.Min(snap.Low, bar.Low)
+	snap.Last = bar.Close
+
+	switch {
+	case u.DayVolume > 0:
+		snap.Volume = u.DayVolume
+		snap.VWAP = u.DayVWAP
+	case snap.Volume+bar.Volume > 0:
+		snap.VWAP = (snap.VWAP*float64(snap.Volume) + bar.VWAP*float64(bar.Volume)) /
+			float64(snap.Volume+bar.Volume)
+		snap.Volume += bar.Volume
+	}
+
+	if snap.PrevClose > 0 {
+		snap.Change = snap.Last - snap.PrevClose
+		snap.ChangePct = (snap.Change / snap.PrevClose) * 100
+	}
+	snap.UpdatedAt = bar.Timestamp.Add(time.Minute)
+	return snap
+}
@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/adjust"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/strength"
+)
+
+// updateStrength scores and ranks the StrengthUniverse tickers with a bar
+// on date, the latest stored session, over their split-adjusted history
+func (s *Scheduler) updateStrength(ctx context.Context, date time.Time) error {
+	symbols, err := s.universeOn(ctx, s.opts.StrengthUniverse, date)
+	if err != nil {
+		return err
+	}
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	from := date.Add(-strength.Lookback)
+	bySymbol, err := s.actionsBySymbol(ctx, from, date)
+	if err != nil {
+		return err
+	}
+
+	spy, err := s.store.GetBars(ctx, strength.Benchmark, from, date, 0)
+	if err != nil {
+		return fmt.Errorf("loading %s history: %w", strength.Benchmark, err)
+	}
+	ranker := strength.NewRanker(date, adjust.Bars(spy, bySymbol[strength.Benchmark], adjust.Split))
+
+	err = s.store.ScanBars(ctx, symbols, from, date, func(symbol string, bars []models.DailyBar) error {
+		ranker.Add(symbol, adjust.Bars(bars, bySymbol[symbol], adjust.Split))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("scanning history: %w", err)
+	}
+
+	scores := ranker.Scores()
+	if err := s.store.SaveStrengthScores(ctx, date, scores); err != nil {
+		return fmt.Errorf("saving strength scores: %w", err)
+	}
+	s.logger.Info("ranked strength scores", "date", date.Format("2006-01-02"),
+		"ranked", len(scores), "universe", len(symbols))
+	return nil
+}
+
+// universeOn returns the symbols passing filter that have a bar on date,
+// which must be the latest stored session
+func (s *Scheduler) universeOn(ctx context.Context, filter models.ScreenerFilter, date time.Time) ([]string, error) {
+	latest, err := s.store.GetScreenerBars(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("loading universe: %w", err)
+	}
+	day := calendar.Date(date)
+	var symbols []string
+	for _, bar := range latest {
+		if calendar.Date(bar.Date).Equal(day) {
+			symbols = append(symbols, bar.Symbol)
+		}
+	}
+	return symbols, nil
+}
+
+// actionsBySymbol returns the corporate actions with ex dates from..to,
+// grouped by symbol, for adjusting history
+func (s *Scheduler) actionsBySymbol(ctx context.Context, from, to time.Time) (map[string][]models.CorporateAction, error) {
+	actions, err := s.store.GetCorporateActionsBetween(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("loading corporate actions: %w", err)
+	}
+	bySymbol := make(map[string][]models.CorporateAction)
+	for _, a := range actions {
+		bySymbol[a.Symbol] = append(bySymbol[a.Symbol], a)
+	}
+	return bySymbol, nil
+}
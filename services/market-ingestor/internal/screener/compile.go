@@ -0,0 +1,349 @@
+package screener
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/indicators"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+type kind int
+
+const (
+	kindNumber kind = iota
+	kindString
+	kindBool
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindNumber:
+		return "number"
+	case kindString:
+		return "string"
+	}
+	return "boolean"
+}
+
+// value is the result of evaluating an expression for one symbol. null
+// marks an unknown value, such as an indicator without enough history;
+// comparisons with an unknown value are unknown and never match.
+type value struct {
+	num  float64
+	str  string
+	b    bool
+	null bool
+}
+
+var null = value{null: true}
+
+func number(n float64) value {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return null
+	}
+	return value{num: n}
+}
+
+func boolean(b bool) value {
+	return value{b: b}
+}
+
+// env is what an expression is evaluated against
+type env struct {
+	bar        models.DailyBar
+	ticker     models.Ticker
+	indicators *indicators.Result
+}
+
+type expr struct {
+	kind kind
+	eval func(e *env) value
+}
+
+// metric is a named value referenced by a screen, reported with each match
+type metric struct {
+	name string
+	expr expr
+}
+
+// compiler type-checks a parsed expression into evaluator closures,
+// collecting the metrics and indicators it references
+type compiler struct {
+	metrics    []metric
+	seen       map[string]bool
+	indicators map[string]bool
+	opts       CompileOptions
+}
+
+func newCompiler(opts CompileOptions) *compiler {
+	return &compiler{seen: make(map[string]bool), indicators: make(map[string]bool), opts: opts}
+}
+
+// specs returns the indicator specs the compiled expressions need
+func (c *compiler) specs() []string {
+	specs := make([]string, 0, len(c.indicators))
+	for spec := range c.indicators {
+		specs = append(specs, spec)
+	}
+	sort.Strings(specs)
+	return specs
+}
+
+func (c *compiler) compile(n node) (expr, error) {
+	switch n := n.(type) {
+	case *numberLit:
+		v := number(n.value)
+		return expr{kind: kindNumber, eval: func(*env) value { return v }}, nil
+	case *stringLit:
+		v := value{str: n.value}
+		return expr{kind: kindString, eval: func(*env) value { return v }}, nil
+	case *ident:
+		return c.ident(n)
+	case *unary:
+		return c.unary(n)
+	case *binary:
+		return c.binary(n)
+	case *inList:
+		return c.inList(n)
+	}
+	panic("screener: unknown node type")
+}
+
+func (c *compiler) ident(n *ident) (expr, error) {
+	name := strings.ToLower(n.name)
+
+	if name == "sector" && !c.opts.Sectors {
+		return expr{}, errorf(n.pos, "sector is unavailable: the data provider supplies no sector data")
+	}
+
+	e, ok := fields[name]
+	if !ok {
+		var err error
+		if e, err = c.indicator(n.pos, name); err != nil {
+			return expr{}, err
+		}
+	}
+
+	if !c.seen[name] {
+		c.seen[name] = true
+		c.metrics = append(c.metrics, metric{name: name, expr: e})
+	}
+	return e, nil
+}
+
+// indicator resolves names such as rsi14, macd.signal or bb20.upper
+func (c *compiler) indicator(pos int, name string) (expr, error) {
+	spec, output, hasOutput := strings.Cut(name, ".")
+
+	ind, err := indicators.Parse(spec)
+	if errors.Is(err, indicators.ErrUnknown) {
+		return expr{}, errorf(pos, "unknown field %q", name)
+	}
+	if err != nil {
+		return expr{}, errorf(pos, "%v", err)
+	}
+
+	outputs := ind.Values()
+	if !hasOutput {
+		// Bare names pick the single output, or the one named after the
+		// indicator itself (macd -> macd.macd, adx14 -> adx14.adx)
+		base := strings.TrimRight(spec, "0123456789_")
+		if _, ok := outputs["value"]; ok {
+			output = "value"
+		} else if _, ok := outputs[base]; ok {
+			output = base
+		} else {
+			return expr{}, errorf(pos, "indicator %q has several outputs, choose one of %s", spec, outputList(spec, outputs))
+		}
+	}
+	if _, ok := outputs[output]; !ok {
+		return expr{}, errorf(pos, "indicator %q has no output %q, choose one of %s", spec, output, outputList(spec, outputs))
+	}
+
+	c.indicators[spec] = true
+	return expr{kind: kindNumber, eval: func(e *env) value {
+		if e.indicators == nil {
+			return null
+		}
+		values := e.indicators.Indicators[spec]
+		if values == nil {
+			return null
+		}
+		return number(values[output])
+	}}, nil
+}
+
+func outputList(spec string, outputs map[string]float64) string {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, spec+"."+name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func (c *compiler) unary(n *unary) (expr, error) {
+	x, err := c.compile(n.x)
+	if err != nil {
+		return expr{}, err
+	}
+
+	if n.op == "NOT" {
+		if x.kind != kindBool {
+			return expr{}, errorf(n.pos, "NOT needs a condition, found a %s", x.kind)
+		}
+		return expr{kind: kindBool, eval: func(e *env) value {
+			v := x.eval(e)
+			if v.null {
+				return null
+			}
+			return boolean(!v.b)
+		}}, nil
+	}
+
+	if x.kind != kindNumber {
+		return expr{}, errorf(n.pos, "cannot negate a %s", x.kind)
+	}
+	return expr{kind: kindNumber, eval: func(e *env) value {
+		v := x.eval(e)
+		if v.null {
+			return null
+		}
+		return number(-v.num)
+	}}, nil
+}
+
+func (c *compiler) binary(n *binary) (expr, error) {
+	x, err := c.compile(n.x)
+	if err != nil {
+		return expr{}, err
+	}
+	y, err := c.compile(n.y)
+	if err != nil {
+		return expr{}, err
+	}
+
+	switch n.op {
+	case "AND", "OR":
+		if x.kind != kindBool || y.kind != kindBool {
+			return expr{}, errorf(n.pos, "%s needs conditions on both sides", n.op)
+		}
+		return expr{kind: kindBool, eval: logical(n.op == "AND", x, y)}, nil
+
+	case "+", "-", "*", "/":
+		if x.kind != kindNumber || y.kind != kindNumber {
+			return expr{}, errorf(n.pos, "%s needs numbers on both sides", n.op)
+		}
+		op := n.op
+		return expr{kind: kindNumber, eval: func(e *env) value {
+			a, b := x.eval(e), y.eval(e)
+			if a.null || b.null {
+				return null
+			}
+			switch op {
+			case "+":
+				return number(a.num + b.num)
+			case "-":
+				return number(a.num - b.num)
+			case "*":
+				return number(a.num * b.num)
+			}
+			if b.num == 0 {
+				return null
+			}
+			return number(a.num / b.num)
+		}}, nil
+	}
+
+	// Comparison
+	if x.kind != y.kind || x.kind == kindBool {
+		return expr{}, errorf(n.pos, "cannot compare %s with %s", x.kind, y.kind)
+	}
+	if x.kind == kindString && n.op != "=" && n.op != "!=" {
+		return expr{}, errorf(n.pos, "strings can only be compared with = or !=")
+	}
+	op := n.op
+	return expr{kind: kindBool, eval: func(e *env) value {
+		a, b := x.eval(e), y.eval(e)
+		if a.null || b.null {
+			return null
+		}
+		if x.kind == kindString {
+			return boolean(strings.EqualFold(a.str, b.str) == (op == "="))
+		}
+		switch op {
+		case ">":
+			return boolean(a.num > b.num)
+		case ">=":
+			return boolean(a.num >= b.num)
+		case "<":
+			return boolean(a.num < b.num)
+		case "<=":
+			return boolean(a.num <= b.num)
+		case "=":
+			return boolean(a.num == b.num)
+		}
+		return boolean(a.num != b.num)
+	}}, nil
+}
+
+// logical evaluates AND/OR with SQL-style three-valued logic, so a known
+// false (AND) or true (OR) side decides the result even if the other is
+// unknown
+func logical(and bool, x, y expr) func(e *env) value {
+	return func(e *env) value {
+		a := x.eval(e)
+		if !a.null && a.b != and {
+			return a
+		}
+		b := y.eval(e)
+		if !b.null && b.b != and {
+			return b
+		}
+		if a.null || b.null {
+			return null
+		}
+		return boolean(and)
+	}
+}
+
+func (c *compiler) inList(n *inList) (expr, error) {
+	x, err := c.compile(n.x)
+	if err != nil {
+		return expr{}, err
+	}
+	if x.kind == kindBool {
+		return expr{}, errorf(n.pos, "IN needs a number or string on the left")
+	}
+
+	items := make([]expr, len(n.list))
+	for i, item := range n.list {
+		if items[i], err = c.compile(item); err != nil {
+			return expr{}, err
+		}
+		if items[i].kind != x.kind {
+			return expr{}, errorf(item.position(), "list item is a %s, expected a %s", items[i].kind, x.kind)
+		}
+	}
+
+	not := n.not
+	return expr{kind: kindBool, eval: func(e *env) value {
+		v := x.eval(e)
+		if v.null {
+			return null
+		}
+		for _, item := range items {
+			iv := item.eval(e)
+			if iv.null {
+				continue
+			}
+			if (x.kind == kindString && strings.EqualFold(v.str, iv.str)) || (x.kind == kindNumber && v.num == iv.num) {
+				return boolean(!not)
+			}
+		}
+		return boolean(not)
+	}}, nil
+}
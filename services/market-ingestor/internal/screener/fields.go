@@ -0,0 +1,71 @@
+package screener
+
+import "github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+
+// fields are the bar and reference data values a screen can reference by
+// name. Stats that are stored as zero when unknown evaluate as unknown.
+var fields = map[string]expr{
+	"open":          numberField(func(b models.DailyBar) float64 { return b.Open }),
+	"high":          numberField(func(b models.DailyBar) float64 { return b.High }),
+	"low":           numberField(func(b models.DailyBar) float64 { return b.Low }),
+	"close":         numberField(func(b models.DailyBar) float64 { return b.Close }),
+	"price":         numberField(func(b models.DailyBar) float64 { return b.Close }),
+	"volume":        numberField(func(b models.DailyBar) float64 { return float64(b.Volume) }),
+	"dollar_volume": numberField(func(b models.DailyBar) float64 { return b.Close * float64(b.Volume) }),
+	"vwap":          optionalField(func(b models.DailyBar) float64 { return b.VWAP }),
+	"prev_close":    optionalField(func(b models.DailyBar) float64 { return b.PrevClose }),
+	"avg_volume":    optionalField(func(b models.DailyBar) float64 { return float64(b.AvgVolume20) }),
+	"avg_volume_20": optionalField(func(b models.DailyBar) float64 { return float64(b.AvgVolume20) }),
+	"avg_volume_50": optionalField(func(b models.DailyBar) float64 { return float64(b.AvgVolume50) }),
+	"volume_ratio":  optionalField(func(b models.DailyBar) float64 { return b.VolumeRatio }),
+	"change":        changeField(func(b models.DailyBar) float64 { return b.Change }),
+	"change_pct":    changeField(func(b models.DailyBar) float64 { return b.ChangePct }),
+	"gap_pct": {kind: kindNumber, eval: func(e *env) value {
+		if e.bar.PrevClose == 0 {
+			return null
+		}
+		return number((e.bar.Open - e.bar.PrevClose) / e.bar.PrevClose * 100)
+	}},
+
+	"symbol":   {kind: kindString, eval: func(e *env) value { return value{str: e.bar.Symbol} }},
+	"name":     stringField(func(t models.Ticker) string { return t.Name }),
+	"type":     stringField(func(t models.Ticker) string { return t.Type }),
+	"exchange": stringField(func(t models.Ticker) string { return t.PrimaryExchange }),
+	"sector":   stringField(func(t models.Ticker) string { return t.Sector }),
+}
+
+func numberField(get func(models.DailyBar) float64) expr {
+	return expr{kind: kindNumber, eval: func(e *env) value {
+		return number(get(e.bar))
+	}}
+}
+
+// optionalField is a bar value that is zero when it has not been computed
+func optionalField(get func(models.DailyBar) float64) expr {
+	return expr{kind: kindNumber, eval: func(e *env) value {
+		if v := get(e.bar); v != 0 {
+			return number(v)
+		}
+		return null
+	}}
+}
+
+// changeField is a change value, unknown when the bar has no change basis
+func changeField(get func(models.DailyBar) float64) expr {
+	return expr{kind: kindNumber, eval: func(e *env) value {
+		if e.bar.ChangeBasis == models.ChangeBasisNone {
+			return null
+		}
+		return number(get(e.bar))
+	}}
+}
+
+// stringField is ticker reference data, unknown when empty
+func stringField(get func(models.Ticker) string) expr {
+	return expr{kind: kindString, eval: func(e *env) value {
+		if v := get(e.ticker); v != "" {
+			return value{str: v}
+		}
+		return null
+	}}
+}
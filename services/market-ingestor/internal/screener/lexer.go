@@ -0,0 +1,147 @@
+package screener
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error is a problem in a screen expression, located by 1-based column
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	if e.Pos > 0 {
+		return fmt.Sprintf("column %d: %s", e.Pos, e.Msg)
+	}
+	return e.Msg
+}
+
+func errorf(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// keyword reports whether t is the case-insensitive keyword kw
+func (t token) keyword(kw string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, kw)
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// twoCharOps are matched before single-character operators
+var twoCharOps = []string{">=", "<=", "!=", "<>", "=="}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		pos := i + 1
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			j := i
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			if j < len(src) && (src[j] == 'e' || src[j] == 'E') {
+				k := j + 1
+				if k < len(src) && (src[k] == '+' || src[k] == '-') {
+					k++
+				}
+				if k < len(src) && isDigit(src[k]) {
+					for j = k; j < len(src) && isDigit(src[j]); j++ {
+					}
+				}
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, errorf(pos, "invalid number %q", src[i:j])
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: src[i:j], num: n, pos: pos})
+			i = j
+		case c == '\'' || c == '"':
+			j := strings.IndexByte(src[i+1:], c)
+			if j < 0 {
+				return nil, errorf(pos, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokString, text: src[i+1 : i+1+j], pos: pos})
+			i += j + 2
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(src) && (isIdentStart(src[j]) || isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[i:j], pos: pos})
+			i = j
+		default:
+			kind, width := tokOp, 1
+			switch c {
+			case '(':
+				kind = tokLParen
+			case ')':
+				kind = tokRParen
+			case '[':
+				kind = tokLBracket
+			case ']':
+				kind = tokRBracket
+			case ',':
+				kind = tokComma
+			case '>', '<', '=', '!', '+', '-', '*', '/':
+				for _, op := range twoCharOps {
+					if strings.HasPrefix(src[i:], op) {
+						width = 2
+						break
+					}
+				}
+				if c == '!' && width == 1 {
+					return nil, errorf(pos, "unexpected '!', use != or NOT")
+				}
+			default:
+				return nil, errorf(pos, "unexpected character %q", c)
+			}
+			tokens = append(tokens, token{kind: kind, text: src[i : i+width], pos: pos})
+			i += width
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(src) + 1}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
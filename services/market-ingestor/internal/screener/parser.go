@@ -0,0 +1,263 @@
+package screener
+
+// node is a parsed expression
+type node interface {
+	position() int
+}
+
+type (
+	numberLit struct {
+		pos   int
+		value float64
+	}
+	stringLit struct {
+		pos   int
+		value string
+	}
+	ident struct {
+		pos  int
+		name string
+	}
+	unary struct {
+		pos int
+		op  string // NOT or -
+		x   node
+	}
+	binary struct {
+		pos  int
+		op   string // AND, OR, comparison or arithmetic operator
+		x, y node
+	}
+	inList struct {
+		pos  int
+		not  bool
+		x    node
+		list []node
+	}
+)
+
+func (n *numberLit) position() int { return n.pos }
+func (n *stringLit) position() int { return n.pos }
+func (n *ident) position() int     { return n.pos }
+func (n *unary) position() int     { return n.pos }
+func (n *binary) position() int    { return n.pos }
+func (n *inList) position() int    { return n.pos }
+
+// parser is a recursive descent parser over the grammar, lowest
+// precedence first:
+//
+//	or      = and { OR and }
+//	and     = not { AND not }
+//	not     = NOT not | compare
+//	compare = sum [ ( op sum ) | [ NOT ] IN list ]
+//	sum     = product { ( + | - ) product }
+//	product = unary { ( * | / ) unary }
+//	unary   = - unary | primary
+//	primary = number | string | ident | ( or )
+//	list    = ( "[" | "(" ) sum { , sum } ( "]" | ")" )
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func parse(src string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errorf(t.pos, "unexpected %s", t)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) or() (node, error) {
+	x, err := p.and()
+	for err == nil && p.peek().keyword("OR") {
+		t := p.advance()
+		var y node
+		if y, err = p.and(); err == nil {
+			x = &binary{pos: t.pos, op: "OR", x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) and() (node, error) {
+	x, err := p.not()
+	for err == nil && p.peek().keyword("AND") {
+		t := p.advance()
+		var y node
+		if y, err = p.not(); err == nil {
+			x = &binary{pos: t.pos, op: "AND", x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) not() (node, error) {
+	if t := p.peek(); t.keyword("NOT") {
+		p.advance()
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{pos: t.pos, op: "NOT", x: x}, nil
+	}
+	return p.compare()
+}
+
+func (p *parser) compare() (node, error) {
+	x, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && isComparison(t.text):
+		p.advance()
+		y, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		return &binary{pos: t.pos, op: canonicalOp(t.text), x: x, y: y}, nil
+	case t.keyword("IN"):
+		p.advance()
+		return p.list(&inList{pos: t.pos, x: x})
+	case t.keyword("NOT") && p.tokens[p.next+1].keyword("IN"):
+		p.advance()
+		p.advance()
+		return p.list(&inList{pos: t.pos, not: true, x: x})
+	}
+	return x, nil
+}
+
+func (p *parser) list(n *inList) (node, error) {
+	open := p.advance()
+	var closer tokenKind
+	switch open.kind {
+	case tokLBracket:
+		closer = tokRBracket
+	case tokLParen:
+		closer = tokRParen
+	default:
+		return nil, errorf(open.pos, "expected [ or ( after IN, found %s", open)
+	}
+
+	for {
+		item, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		n.list = append(n.list, item)
+
+		t := p.advance()
+		if t.kind == closer {
+			return n, nil
+		}
+		if t.kind != tokComma {
+			return nil, errorf(t.pos, "expected , or end of list, found %s", t)
+		}
+	}
+}
+
+func (p *parser) sum() (node, error) {
+	x, err := p.product()
+	for err == nil && p.peek().kind == tokOp && (p.peek().text == "+" || p.peek().text == "-") {
+		t := p.advance()
+		var y node
+		if y, err = p.product(); err == nil {
+			x = &binary{pos: t.pos, op: t.text, x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) product() (node, error) {
+	x, err := p.unary()
+	for err == nil && p.peek().kind == tokOp && (p.peek().text == "*" || p.peek().text == "/") {
+		t := p.advance()
+		var y node
+		if y, err = p.unary(); err == nil {
+			x = &binary{pos: t.pos, op: t.text, x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) unary() (node, error) {
+	if t := p.peek(); t.kind == tokOp && t.text == "-" {
+		p.advance()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{pos: t.pos, op: "-", x: x}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.advance()
+	switch t.kind {
+	case tokNumber:
+		return &numberLit{pos: t.pos, value: t.num}, nil
+	case tokString:
+		return &stringLit{pos: t.pos, value: t.text}, nil
+	case tokIdent:
+		for _, kw := range []string{"AND", "OR", "NOT", "IN"} {
+			if t.keyword(kw) {
+				return nil, errorf(t.pos, "unexpected %s", kw)
+			}
+		}
+		return &ident{pos: t.pos, name: t.text}, nil
+	case tokLParen:
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.advance(); c.kind != tokRParen {
+			return nil, errorf(c.pos, "expected ), found %s", c)
+		}
+		return x, nil
+	}
+	return nil, errorf(t.pos, "unexpected %s", t)
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case ">", ">=", "<", "<=", "=", "==", "!=", "<>":
+		return true
+	}
+	return false
+}
+
+// canonicalOp folds comparison spellings to one form
+func canonicalOp(op string) string {
+	switch op {
+	case "==":
+		return "="
+	case "<>":
+		return "!="
+	}
+	return op
+}
@@ -0,0 +1,119 @@
+package screener
+
+import (
+	"context"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// RequestFor returns the request a saved screen describes
+func RequestFor(screen models.SavedScreen) Request {
+	return Request{
+		Filter: screen.Filter,
+		Sort:   screen.Sort,
+		Order:  screen.Order,
+		Limit:  screen.Limit,
+	}
+}
+
+// Alert reports symbols entering or leaving a saved screen
+type Alert struct {
+	Source     string    `json:"source"` // always "screen"
+	ScreenID   string    `json:"screen_id"`
+	ScreenName string    `json:"screen_name"`
+	Date       time.Time `json:"date"`
+	Entered    []string  `json:"entered"`
+	Exited     []string  `json:"exited"`
+}
+
+// EvaluateSaved runs every saved screen against the latest session and
+// stores the symbols each matched, with those that entered and exited
+// since the screen's previous evaluation. Screens that fail are logged and
+// skipped. It returns an alert for each screen whose matches changed, or
+// an error if the saved screens or reference data cannot be read.
+func (s *Screener) EvaluateSaved(ctx context.Context, universe models.ScreenerFilter) ([]Alert, error) {
+	screens, err := s.store.ListScreens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := s.CompileOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []Alert
+	for _, saved := range screens {
+		screen, err := Compile(RequestFor(saved), opts)
+		if err != nil {
+			s.logger.Error("saved screen no longer compiles", "screen", saved.Name, "error", err)
+			continue
+		}
+
+		res, err := s.Run(ctx, screen, universe)
+		if err != nil {
+			s.logger.Error("failed to run saved screen", "screen", saved.Name, "error", err)
+			continue
+		}
+		if res.AsOf.IsZero() {
+			// Nothing stored yet
+			continue
+		}
+
+		// Membership is the full match set; the limit only trims what a
+		// run displays, so rank changes inside the set are not entries
+		result := models.ScreenResult{
+			ScreenID:    saved.ID,
+			Date:        res.AsOf,
+			Symbols:     res.Symbols,
+			EvaluatedAt: time.Now(),
+		}
+
+		prev, err := s.store.GetScreenResults(ctx, saved.ID, res.AsOf.AddDate(0, 0, -1), 1)
+		if err != nil {
+			s.logger.Error("failed to load previous screen result", "screen", saved.Name, "error", err)
+			continue
+		}
+		var previous []string
+		if len(prev) > 0 {
+			result.PreviousDate = prev[0].Date
+			previous = prev[0].Symbols
+		}
+		result.Entered = difference(result.Symbols, previous)
+		result.Exited = difference(previous, result.Symbols)
+
+		if err := s.store.SaveScreenResult(ctx, result); err != nil {
+			s.logger.Error("failed to save screen result", "screen", saved.Name, "error", err)
+			continue
+		}
+		if len(result.Entered) > 0 || len(result.Exited) > 0 {
+			alerts = append(alerts, Alert{
+				Source:     "screen",
+				ScreenID:   saved.ID,
+				ScreenName: saved.Name,
+				Date:       result.Date,
+				Entered:    result.Entered,
+				Exited:     result.Exited,
+			})
+		}
+		s.logger.Info("evaluated saved screen", "screen", saved.Name, "date", res.AsOf.Format("2006-01-02"),
+			"matches", len(result.Symbols), "entered", len(result.Entered), "exited", len(result.Exited))
+	}
+	return alerts, nil
+}
+
+// difference returns the symbols in a that are not in b, in a's order
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, symbol := range b {
+		in[symbol] = true
+	}
+	out := make([]string, 0)
+	for _, symbol := range a {
+		if !in[symbol] {
+			out = append(out, symbol)
+		}
+	}
+	return out
+}
@@ -0,0 +1,302 @@
+// Package screener evaluates custom screens: boolean filter expressions
+// over the latest bars, ticker reference data and technical indicators,
+// such as
+//
+//	close > sma200 AND rsi14 < 30 AND volume_ratio > 2 AND sector IN ['Energy']
+package screener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/indicators"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+
+	// maxExpressionLength bounds filter and sort expressions
+	maxExpressionLength = 2000
+
+	// indicatorWorkers bounds concurrent indicator seeding during a run
+	indicatorWorkers = 8
+)
+
+// Request describes a screen to run
+type Request struct {
+	// Filter is the boolean expression rows must satisfy; empty matches all
+	Filter string `json:"filter"`
+	// Sort is a number or string expression to order matches by, volume
+	// when empty
+	Sort string `json:"sort,omitempty"`
+	// Order is "asc" or "desc" (the default)
+	Order string `json:"order,omitempty"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// CompileOptions describe the reference data available to screens
+type CompileOptions struct {
+	// Sectors is whether tickers carry a sector; without one, screens
+	// referencing sector are rejected rather than never matching
+	Sectors bool
+}
+
+// Row is a matching symbol with the values of every metric the screen
+// referenced
+type Row struct {
+	models.ScreenerResult
+	Metrics map[string]any `json:"metrics"`
+}
+
+// Response is the outcome of running a screen
+type Response struct {
+	AsOf    time.Time `json:"as_of"`
+	Count   int       `json:"count"` // matches before the limit was applied
+	Results []Row     `json:"results"`
+	// Symbols is every match in sort order, ignoring the limit
+	Symbols []string `json:"-"`
+}
+
+// Screen is a compiled Request
+type Screen struct {
+	filter  *expr
+	sort    expr
+	desc    bool
+	limit   int
+	metrics []metric
+	specs   []string
+}
+
+// Compile validates and type-checks req. Errors describe the problem for
+// the user and are *Error when they concern an expression.
+func Compile(req Request, opts CompileOptions) (*Screen, error) {
+	c := newCompiler(opts)
+	s := &Screen{desc: true, limit: req.Limit}
+
+	if len(req.Filter) > maxExpressionLength || len(req.Sort) > maxExpressionLength {
+		return nil, fmt.Errorf("expressions are limited to %d characters", maxExpressionLength)
+	}
+
+	if strings.TrimSpace(req.Filter) != "" {
+		n, err := parse(req.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("filter: %w", err)
+		}
+		e, err := c.compile(n)
+		if err != nil {
+			return nil, fmt.Errorf("filter: %w", err)
+		}
+		if e.kind != kindBool {
+			return nil, fmt.Errorf("filter: must be a condition, found a %s", e.kind)
+		}
+		s.filter = &e
+	}
+
+	sortExpr := req.Sort
+	if strings.TrimSpace(sortExpr) == "" {
+		sortExpr = "volume"
+	}
+	n, err := parse(sortExpr)
+	if err != nil {
+		return nil, fmt.Errorf("sort: %w", err)
+	}
+	if s.sort, err = c.compile(n); err != nil {
+		return nil, fmt.Errorf("sort: %w", err)
+	}
+	if s.sort.kind == kindBool {
+		return nil, fmt.Errorf("sort: must be a number or string, found a condition")
+	}
+
+	switch strings.ToLower(req.Order) {
+	case "", "desc":
+	case "asc":
+		s.desc = false
+	default:
+		return nil, fmt.Errorf("order must be asc or desc")
+	}
+
+	switch {
+	case s.limit == 0:
+		s.limit = DefaultLimit
+	case s.limit < 0 || s.limit > MaxLimit:
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+	}
+
+	s.metrics = c.metrics
+	s.specs = c.specs()
+	return s, nil
+}
+
+// Screener runs compiled screens against a store
+type Screener struct {
+	store      store.Store
+	indicators *indicators.Engine
+	logger     *slog.Logger
+}
+
+func New(store store.Store, engine *indicators.Engine, logger *slog.Logger) *Screener {
+	return &Screener{store: store, indicators: engine, logger: logger}
+}
+
+// CompileOptions returns the options matching the stored reference data
+func (s *Screener) CompileOptions(ctx context.Context) (CompileOptions, error) {
+	sectors, err := s.store.HasSectors(ctx)
+	if err != nil {
+		return CompileOptions{}, err
+	}
+	return CompileOptions{Sectors: sectors}, nil
+}
+
+type candidate struct {
+	env  env
+	sort value
+}
+
+// Run evaluates screen against the latest session's bars that pass the
+// universe filter
+func (s *Screener) Run(ctx context.Context, screen *Screen, universe models.ScreenerFilter) (*Response, error) {
+	bars, err := s.store.GetScreenerBars(ctx, universe)
+	if err != nil {
+		return nil, err
+	}
+
+	envs := make([]env, len(bars))
+	var asOf time.Time
+	for i, bar := range bars {
+		envs[i].bar = bar
+		envs[i].ticker, err = s.store.GetTicker(ctx, bar.Symbol)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return nil, err
+		}
+		if bar.Date.After(asOf) {
+			asOf = bar.Date
+		}
+	}
+	if len(screen.specs) > 0 {
+		if err := s.loadIndicators(ctx, envs, screen.specs); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []candidate
+	for i := range envs {
+		e := &envs[i]
+		if screen.filter != nil {
+			if v := screen.filter.eval(e); v.null || !v.b {
+				continue
+			}
+		}
+		matches = append(matches, candidate{env: *e, sort: screen.sort.eval(e)})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i].sort, matches[j].sort
+		if a.null != b.null {
+			return b.null // unknown values sort last either way
+		}
+		if !a.null && !equal(screen.sort.kind, a, b) {
+			return less(screen.sort.kind, a, b) != screen.desc
+		}
+		return matches[i].env.bar.Symbol < matches[j].env.bar.Symbol
+	})
+
+	res := &Response{
+		AsOf:    asOf,
+		Count:   len(matches),
+		Results: make([]Row, 0, min(len(matches), screen.limit)),
+		Symbols: make([]string, len(matches)),
+	}
+	for i := range matches {
+		res.Symbols[i] = matches[i].env.bar.Symbol
+		if i < screen.limit {
+			res.Results = append(res.Results, screen.row(&matches[i].env))
+		}
+	}
+	return res, nil
+}
+
+// loadIndicators fills in indicator values, seeding uncached symbols from
+// history in parallel. It stops at the first failure, which it returns.
+func (s *Screener) loadIndicators(ctx context.Context, envs []env, specs []string) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < indicatorWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res, err := s.indicators.Latest(ctx, envs[i].bar, specs)
+				if err != nil {
+					cancel(err)
+					continue
+				}
+				envs[i].indicators = res
+			}
+		}()
+	}
+	for i := range envs {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return context.Cause(ctx)
+}
+
+func (screen *Screen) row(e *env) Row {
+	bar := e.bar
+	row := Row{
+		ScreenerResult: models.ScreenerResult{
+			Symbol:      bar.Symbol,
+			Name:        e.ticker.Name,
+			Price:       bar.Close,
+			Change:      bar.Change,
+			ChangePct:   bar.ChangePct,
+			Volume:      bar.Volume,
+			AvgVolume:   bar.AvgVolume20,
+			AvgVolume50: bar.AvgVolume50,
+			VolumeRatio: bar.VolumeRatio,
+		},
+		Metrics: make(map[string]any, len(screen.metrics)),
+	}
+	for _, m := range screen.metrics {
+		v := m.expr.eval(e)
+		switch {
+		case v.null:
+			row.Metrics[m.name] = nil
+		case m.expr.kind == kindString:
+			row.Metrics[m.name] = v.str
+		default:
+			row.Metrics[m.name] = v.num
+		}
+	}
+	return row
+}
+
+func equal(k kind, a, b value) bool {
+	if k == kindString {
+		return a.str == b.str
+	}
+	return a.num == b.num
+}
+
+func less(k kind, a, b value) bool {
+	if k == kindString {
+		return a.str < b.str
+	}
+	return a.num < b.num
+}
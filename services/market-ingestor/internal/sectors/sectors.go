@@ -0,0 +1,147 @@
+// Package sectors derives relative strength and rotation signals from the
+// SPDR sector ETFs in each day's bars.
+package sectors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// Benchmark is the ETF sector relative strength is measured against
+const Benchmark = "SPY"
+
+// ETF is a sector fund tracked for rotation
+type ETF struct {
+	Symbol string
+	Name   string
+	// Defensive sectors hold up when investors shed risk; the rest are
+	// cyclical
+	Defensive bool
+}
+
+// ETFs are the Select Sector SPDR funds
+var ETFs = []ETF{
+	{Symbol: "XLE", Name: "Energy Select Sector"},
+	{Symbol: "XLK", Name: "Technology Select Sector"},
+	{Symbol: "XLF", Name: "Financial Select Sector"},
+	{Symbol: "XLV", Name: "Health Care Select Sector", Defensive: true},
+	{Symbol: "XLI", Name: "Industrial Select Sector"},
+	{Symbol: "XLB", Name: "Materials Select Sector"},
+	{Symbol: "XLP", Name: "Consumer Staples Select Sector", Defensive: true},
+	{Symbol: "XLY", Name: "Consumer Discretionary Select Sector"},
+	{Symbol: "XLU", Name: "Utilities Select Sector", Defensive: true},
+	{Symbol: "XLRE", Name: "Real Estate Select Sector", Defensive: true},
+	{Symbol: "XLC", Name: "Communication Services Select Sector"},
+}
+
+const (
+	// groupSize is how many sectors count as leading and as lagging
+	groupSize = 3
+	// minSectors is how many sector bars a day needs for a rotation signal
+	minSectors = 2 * groupSize
+	// riskThreshold is the score at or beyond which rotation is RISK_ON or
+	// RISK_OFF; scores run from -2*groupSize to 2*groupSize
+	riskThreshold = groupSize
+)
+
+var bySymbol = func() map[string]ETF {
+	m := make(map[string]ETF, len(ETFs))
+	for _, etf := range ETFs {
+		m[etf.Symbol] = etf
+	}
+	return m
+}()
+
+// Symbols returns the benchmark and every sector ETF
+func Symbols() []string {
+	symbols := []string{Benchmark}
+	for _, etf := range ETFs {
+		symbols = append(symbols, etf.Symbol)
+	}
+	return symbols
+}
+
+// Analyze derives sector rows from one day's bars keyed by symbol, ordered
+// by relative strength, strongest first. It returns nil without a
+// benchmark bar.
+func Analyze(bars map[string]models.DailyBar) []models.SectorData {
+	spy, ok := bars[Benchmark]
+	if !ok {
+		return nil
+	}
+
+	var data []models.SectorData
+	for _, etf := range ETFs {
+		bar, ok := bars[etf.Symbol]
+		if !ok {
+			continue
+		}
+		data = append(data, models.SectorData{
+			Symbol:           etf.Symbol,
+			Name:             etf.Name,
+			Date:             bar.Date,
+			Open:             bar.Open,
+			High:             bar.High,
+			Low:              bar.Low,
+			Close:            bar.Close,
+			Volume:           bar.Volume,
+			ChangePct:        bar.ChangePct,
+			RelativeStrength: bar.ChangePct - spy.ChangePct,
+			VolumeRatio:      bar.VolumeRatio,
+		})
+	}
+	SortByStrength(data)
+	return data
+}
+
+// SortByStrength orders sector rows by relative strength, strongest first
+func SortByStrength(data []models.SectorData) {
+	sort.SliceStable(data, func(i, j int) bool {
+		if data[i].RelativeStrength != data[j].RelativeStrength {
+			return data[i].RelativeStrength > data[j].RelativeStrength
+		}
+		return data[i].Symbol < data[j].Symbol
+	})
+}
+
+// Rotation classifies a day from its sector rows ordered by strength. The
+// top and bottom three sectors lead and lag; each cyclical leader and
+// defensive laggard adds a point and each defensive leader and cyclical
+// laggard subtracts one. It returns false with too few sectors to judge.
+func Rotation(date time.Time, data []models.SectorData) (models.SectorRotation, bool) {
+	if len(data) < minSectors {
+		return models.SectorRotation{}, false
+	}
+
+	rotation := models.SectorRotation{Date: date, Signal: models.RiskNeutral}
+	score := 0
+	for i := 0; i < groupSize; i++ {
+		leader, laggard := data[i].Symbol, data[len(data)-1-i].Symbol
+		rotation.Leading = append(rotation.Leading, leader)
+		rotation.Lagging = append(rotation.Lagging, laggard)
+		if bySymbol[leader].Defensive {
+			score--
+		} else {
+			score++
+		}
+		if bySymbol[laggard].Defensive {
+			score++
+		} else {
+			score--
+		}
+	}
+
+	switch {
+	case score >= riskThreshold:
+		rotation.Signal = models.RiskOn
+	case score <= -riskThreshold:
+		rotation.Signal = models.RiskOff
+	}
+	rotation.Notes = fmt.Sprintf("score %+d; leading %s; lagging %s",
+		score, strings.Join(rotation.Leading, ", "), strings.Join(rotation.Lagging, ", "))
+	return rotation, true
+}
@@ -0,0 +1,306 @@
+package signals
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+const (
+	// Sessions is the history the detectors look at: the 52-week range
+	Sessions = 252
+	// Lookback is a calendar span covering Sessions trading days
+	Lookback = 380 * 24 * time.Hour
+
+	// GapMinPct is the smallest open-vs-prior-close move reported as a gap
+	GapMinPct = 2.0
+	// BreakoutSessions is the range a close must clear to break out
+	BreakoutSessions = 20
+	// UnusualVolumeRatio is the smallest volume relative to the 20-session
+	// average reported as unusual
+	UnusualVolumeRatio = 2.0
+
+	// volumeConfirmRatio is the volume ratio that confirms a price signal
+	volumeConfirmRatio = 1.5
+	// flatChangePct is the daily change below which unusual volume is
+	// neutral
+	flatChangePct = 0.5
+	// staleHighSessions is how long a 52-week high must have stood for a
+	// new one to count as a fresh leg higher
+	staleHighSessions = 60
+	// volumeWindow is the sessions the average behind a volume ratio spans;
+	// half of it is needed before the ratio is restated
+	volumeWindow = 20
+)
+
+// Detector finds signals in one session's bars a ticker at a time, so
+// callers can stream history rather than hold it all
+type Detector struct {
+	date    time.Time
+	at      time.Time
+	signals []models.Signal
+}
+
+// NewDetector detects signals on date. Signals are timestamped at the
+// session's close.
+func NewDetector(date time.Time) *Detector {
+	return &Detector{date: date, at: calendar.CloseTime(date)}
+}
+
+// Add checks a ticker's split-adjusted bars, ordered by date and ending on
+// the detector's date, against every detector. Tickers without a bar on
+// the date are skipped. Add returns how many signals the ticker raised.
+func (d *Detector) Add(ticker string, bars []models.DailyBar) int {
+	if len(bars) == 0 || !calendar.Date(bars[len(bars)-1].Date).Equal(calendar.Date(d.date)) {
+		return 0
+	}
+	if len(bars) > Sessions+1 {
+		bars = bars[len(bars)-Sessions-1:]
+	}
+	bars = restateVolume(bars)
+
+	n := len(d.signals)
+	for _, detect := range []func([]models.DailyBar) (models.Signal, bool){gap, breakout, unusualVolume, newHigh} {
+		if sig, ok := detect(bars); ok {
+			sig.Ticker = ticker
+			sig.Timestamp = d.at
+			d.signals = append(d.signals, sig)
+		}
+	}
+	return len(d.signals) - n
+}
+
+// Signals returns every signal raised so far
+func (d *Detector) Signals() []models.Signal {
+	return d.signals
+}
+
+// restateVolume recomputes the latest bar's 20-session average volume and
+// volume ratio from bars. Stored figures only account for the splits known
+// when they were computed, so a split saved late would inflate the ratio
+// until it rolls out; the split-adjusted bars Add receives give comparable
+// figures.
+func restateVolume(bars []models.DailyBar) []models.DailyBar {
+	prior := bars[max(0, len(bars)-1-volumeWindow) : len(bars)-1]
+	if len(prior) < volumeWindow/2 {
+		return bars
+	}
+
+	var sum int64
+	for _, bar := range prior {
+		sum += bar.Volume
+	}
+	bar := bars[len(bars)-1]
+	bar.AvgVolume20 = sum / int64(len(prior))
+	bar.VolumeRatio = 0
+	if bar.AvgVolume20 > 0 {
+		bar.VolumeRatio = float64(bar.Volume) / float64(bar.AvgVolume20)
+	}
+	// Copy rather than write through to the caller's slice
+	return append(bars[:len(bars)-1:len(bars)-1], bar)
+}
+
+// gap reports a session opening at least GapMinPct away from the prior
+// close, graded by size. A gap the session closed back through is less
+// convincing than one that held.
+func gap(bars []models.DailyBar) (models.Signal, bool) {
+	bar := bars[len(bars)-1]
+	prev := bar.PrevClose
+	if len(bars) > 1 {
+		prev = bars[len(bars)-2].Close
+	}
+	if prev <= 0 || bar.Open <= 0 {
+		return models.Signal{}, false
+	}
+	pct := (bar.Open/prev - 1) * 100
+	if math.Abs(pct) < GapMinPct {
+		return models.Signal{}, false
+	}
+
+	sig := models.Signal{
+		Source:     models.SourceGap,
+		Direction:  models.Bullish,
+		Strength:   grade(math.Abs(pct), 4, 7, 10),
+		Confidence: 0.6,
+		Tags:       []string{"gap_up"},
+	}
+	filled := bar.Low <= prev
+	if pct < 0 {
+		sig.Direction, sig.Tags[0] = models.Bearish, "gap_down"
+		filled = bar.High >= prev
+	}
+	if filled {
+		sig.Confidence = 0.3
+		sig.Tags = append(sig.Tags, "filled")
+	}
+	sig.RawData = raw(map[string]any{
+		"open": bar.Open, "prev_close": prev, "gap_pct": round(pct), "close": bar.Close,
+	})
+	return sig, true
+}
+
+// breakout reports a close above the highest high, or below the lowest
+// low, of the prior BreakoutSessions sessions, graded by volume.
+// Confidence rises with how near the close is to the day's extreme in the
+// breakout's direction.
+func breakout(bars []models.DailyBar) (models.Signal, bool) {
+	if len(bars) <= BreakoutSessions {
+		return models.Signal{}, false
+	}
+	bar := bars[len(bars)-1]
+	high, low := rangeOf(bars[len(bars)-1-BreakoutSessions : len(bars)-1])
+
+	sig := models.Signal{Source: models.SourceBreakout}
+	loc := closeLocation(bar)
+	switch {
+	case bar.Close > high:
+		sig.Direction = models.Bullish
+		sig.Tags = []string{"breakout_20d"}
+	case bar.Close < low:
+		sig.Direction = models.Bearish
+		sig.Tags = []string{"breakdown_20d"}
+		loc = 1 - loc
+	default:
+		return models.Signal{}, false
+	}
+
+	sig.Strength = grade(bar.VolumeRatio, volumeConfirmRatio, 2, 3)
+	sig.Confidence = 0.3 + 0.6*loc
+	if bar.VolumeRatio >= volumeConfirmRatio {
+		sig.Tags = append(sig.Tags, "volume_confirmed")
+	}
+	sig.RawData = raw(map[string]any{
+		"close": bar.Close, "range_high": high, "range_low": low,
+		"sessions": BreakoutSessions, "volume_ratio": round(bar.VolumeRatio),
+	})
+	return sig, true
+}
+
+// unusualVolume reports volume at least UnusualVolumeRatio times the
+// 20-session average, graded by the ratio. Its direction is the day's
+// move, with conviction growing as the move does.
+func unusualVolume(bars []models.DailyBar) (models.Signal, bool) {
+	bar := bars[len(bars)-1]
+	if bar.VolumeRatio < UnusualVolumeRatio {
+		return models.Signal{}, false
+	}
+
+	sig := models.Signal{
+		Source:     models.SourceUnusualVolume,
+		Direction:  models.Neutral,
+		Strength:   grade(bar.VolumeRatio, 3, 5, 10),
+		Confidence: 0.3 + 0.5*min(math.Abs(bar.ChangePct), 5)/5,
+		Tags:       []string{"unusual_volume"},
+	}
+	switch {
+	case bar.ChangePct >= flatChangePct:
+		sig.Direction = models.Bullish
+	case bar.ChangePct <= -flatChangePct:
+		sig.Direction = models.Bearish
+	}
+	sig.RawData = raw(map[string]any{
+		"volume": bar.Volume, "avg_volume_20": bar.AvgVolume20,
+		"volume_ratio": round(bar.VolumeRatio), "change_pct": round(bar.ChangePct),
+	})
+	return sig, true
+}
+
+// newHigh reports a high above every high of the prior year, for tickers
+// with a full year of history. It grows stronger for closing above the
+// old high, on heavy volume, and for clearing a high that had stood for
+// staleHighSessions or more.
+func newHigh(bars []models.DailyBar) (models.Signal, bool) {
+	if len(bars) <= Sessions {
+		return models.Signal{}, false
+	}
+	bar := bars[len(bars)-1]
+	prior := bars[len(bars)-1-Sessions : len(bars)-1]
+	high, _ := rangeOf(prior)
+	if bar.High <= high {
+		return models.Signal{}, false
+	}
+
+	sig := models.Signal{
+		Source:     models.SourceNewHigh,
+		Direction:  models.Bullish,
+		Strength:   models.StrengthWeak,
+		Confidence: 0.3 + 0.6*closeLocation(bar),
+		Tags:       []string{"new_52w_high"},
+	}
+	if bar.Close > high {
+		sig.Strength++
+		sig.Tags = append(sig.Tags, "closed_above")
+	}
+	if bar.VolumeRatio >= volumeConfirmRatio {
+		sig.Strength++
+		sig.Tags = append(sig.Tags, "volume_confirmed")
+	}
+	since := sessionsSinceHigh(prior, high)
+	if since >= staleHighSessions {
+		sig.Strength++
+		sig.Tags = append(sig.Tags, "fresh_high")
+	}
+	sig.RawData = raw(map[string]any{
+		"high": bar.High, "close": bar.Close, "prior_high": high,
+		"sessions_since_prior_high": since, "volume_ratio": round(bar.VolumeRatio),
+	})
+	return sig, true
+}
+
+// grade maps v to a strength: weak below moderate, moderate below strong,
+// strong below veryStrong, very strong from there
+func grade(v, moderate, strong, veryStrong float64) models.SignalStrength {
+	switch {
+	case v >= veryStrong:
+		return models.StrengthVeryStrong
+	case v >= strong:
+		return models.StrengthStrong
+	case v >= moderate:
+		return models.StrengthModerate
+	default:
+		return models.StrengthWeak
+	}
+}
+
+// rangeOf returns the highest high and lowest low of bars
+func rangeOf(bars []models.DailyBar) (high, low float64) {
+	high, low = bars[0].High, bars[0].Low
+	for _, bar := range bars[1:] {
+		high = max(high, bar.High)
+		low = min(low, bar.Low)
+	}
+	return high, low
+}
+
+// closeLocation is where the close sits in the day's range, from 0 at the
+// low to 1 at the high
+func closeLocation(bar models.DailyBar) float64 {
+	if bar.High <= bar.Low {
+		return 0.5
+	}
+	return max(0, min(1, (bar.Close-bar.Low)/(bar.High-bar.Low)))
+}
+
+// sessionsSinceHigh is how many sessions before the one following prior
+// its high was last reached
+func sessionsSinceHigh(prior []models.DailyBar, high float64) int {
+	for i := len(prior) - 1; i >= 0; i-- {
+		if prior[i].High >= high {
+			return len(prior) - i
+		}
+	}
+	return len(prior)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// raw encodes a detector's inputs for Signal.RawData
+func raw(v map[string]any) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
@@ -0,0 +1,108 @@
+package signals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/adjust"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// splitDay returns 30 quiet sessions around 200 followed by the ex date of
+// a 2-for-1 split, stored as traded: the ex-date bar opens near 100 on
+// twice the share volume, with change and volume stats against the raw
+// prior session as the ingest computes them.
+func splitDay(exDate time.Time) ([]models.DailyBar, models.CorporateAction) {
+	var dates []time.Time
+	for d := exDate; len(dates) < 30; {
+		d = calendar.PreviousTradingDay(d)
+		dates = append([]time.Time{d}, dates...)
+	}
+
+	bars := make([]models.DailyBar, 0, len(dates)+1)
+	for _, d := range dates {
+		bars = append(bars, models.DailyBar{
+			Symbol: "SPLT", Date: d,
+			Open: 200, High: 202, Low: 198, Close: 200, Volume: 1_000_000,
+			PrevClose: 200, ChangeBasis: models.ChangeBasisPrevClose,
+			AvgVolume20: 1_000_000, VolumeRatio: 1,
+		})
+	}
+	bars = append(bars, models.DailyBar{
+		Symbol: "SPLT", Date: exDate,
+		Open: 100.5, High: 101.5, Low: 99, Close: 100, Volume: 2_000_000,
+		PrevClose: 200, Change: -100, ChangePct: -50, ChangeBasis: models.ChangeBasisPrevClose,
+		AvgVolume20: 1_000_000, VolumeRatio: 2,
+	})
+
+	split := models.CorporateAction{
+		Symbol: "SPLT", Type: models.ActionSplit, ExDate: exDate,
+		SplitFrom: 1, SplitTo: 2, PriceFactor: 0.5,
+	}
+	return bars, split
+}
+
+func tags(sigs []models.Signal) []string {
+	var out []string
+	for _, sig := range sigs {
+		out = append(out, sig.Tags[0])
+	}
+	return out
+}
+
+func TestDetectorSplitDay(t *testing.T) {
+	exDate := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	bars, split := splitDay(exDate)
+
+	// Unadjusted, the split reads as a crash on heavy volume
+	raw := NewDetector(exDate)
+	raw.Add("SPLT", bars)
+	want := map[string]bool{"gap_down": true, "breakdown_20d": true, "unusual_volume": true}
+	got := tags(raw.Signals())
+	if len(got) != len(want) {
+		t.Fatalf("raw bars raised %v, want %d signals", got, len(want))
+	}
+	for _, tag := range got {
+		if !want[tag] {
+			t.Errorf("raw bars raised unexpected %s", tag)
+		}
+	}
+
+	adjusted := NewDetector(exDate)
+	if n := adjusted.Add("SPLT", adjust.Bars(bars, []models.CorporateAction{split}, adjust.Split)); n != 0 {
+		t.Errorf("split-adjusted bars raised %v, want none", tags(adjusted.Signals()))
+	}
+	if bars[len(bars)-1].VolumeRatio != 2 {
+		t.Error("Add modified the caller's bars")
+	}
+}
+
+func TestDetectorSkipsTickerWithoutBarOnDate(t *testing.T) {
+	exDate := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	bars, _ := splitDay(exDate)
+
+	d := NewDetector(calendar.NextTradingDay(exDate))
+	if n := d.Add("SPLT", bars); n != 0 {
+		t.Errorf("Add raised %d signals for a ticker without a bar on the date", n)
+	}
+}
+
+func TestRestateVolume(t *testing.T) {
+	bars := make([]models.DailyBar, 21)
+	for i := range bars {
+		bars[i] = models.DailyBar{Volume: 1_000, AvgVolume20: 500, VolumeRatio: 2}
+	}
+	bars[20].Volume = 3_000
+
+	got := restateVolume(bars)[20]
+	if got.AvgVolume20 != 1_000 || got.VolumeRatio != 3 {
+		t.Errorf("restated avg %d ratio %v, want 1000 and 3", got.AvgVolume20, got.VolumeRatio)
+	}
+
+	// Too little history keeps the stored figures
+	short := restateVolume(bars[11:])[9]
+	if short.AvgVolume20 != 500 || short.VolumeRatio != 2 {
+		t.Errorf("short history restated avg %d ratio %v, want stored 500 and 2", short.AvgVolume20, short.VolumeRatio)
+	}
+}
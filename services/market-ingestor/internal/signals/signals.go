@@ -0,0 +1,98 @@
+// Package signals detects trading signals in each session's bars and
+// records them, along with signals from other tools, in the shared signals
+// table.
+package signals
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/calendar"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/events"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// Store is the storage a Writer saves to; store.Store implements it
+type Store interface {
+	// SaveSignals stores signals, keeping one per source, ticker and
+	// session, and returns those stored
+	SaveSignals(ctx context.Context, signals []models.Signal) ([]models.Signal, error)
+}
+
+// Writer validates signals, saves them and announces the ones stored
+type Writer struct {
+	store  Store
+	events events.Publisher
+	logger *slog.Logger
+}
+
+// NewWriter returns a writer saving to store. publisher may be nil.
+func NewWriter(store Store, publisher events.Publisher, logger *slog.Logger) *Writer {
+	return &Writer{store: store, events: publisher, logger: logger}
+}
+
+// Write normalizes and saves signals. A signal for a source, ticker and
+// session that already has one replaces it only if it is stronger; Write
+// returns the signals stored, with their IDs, and publishes each as an
+// events.TypeSignal event.
+func (w *Writer) Write(ctx context.Context, signals []models.Signal) ([]models.Signal, error) {
+	if len(signals) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]models.Signal, len(signals))
+	for i, sig := range signals {
+		var err error
+		if normalized[i], err = Normalize(sig); err != nil {
+			return nil, err
+		}
+	}
+
+	saved, err := w.store.SaveSignals(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("saving signals: %w", err)
+	}
+	if w.events != nil {
+		for _, sig := range saved {
+			w.events.Publish(events.TypeSignal, sig)
+		}
+	}
+	w.logger.Debug("saved signals", "count", len(saved), "submitted", len(signals))
+	return saved, nil
+}
+
+// Normalize validates a signal and fills in what can be derived: the
+// session date from the timestamp, an upper-case ticker, confidence
+// clamped to 0-1 and non-nil tags
+func Normalize(sig models.Signal) (models.Signal, error) {
+	if sig.Source == "" {
+		return sig, fmt.Errorf("signal has no source")
+	}
+	if sig.Timestamp.IsZero() {
+		return sig, fmt.Errorf("%s signal has no timestamp", sig.Source)
+	}
+	switch sig.Direction {
+	case models.Bullish, models.Bearish, models.Neutral:
+	default:
+		return sig, fmt.Errorf("%s signal has invalid direction %q", sig.Source, sig.Direction)
+	}
+	if sig.Strength < models.StrengthWeak || sig.Strength > models.StrengthVeryStrong {
+		return sig, fmt.Errorf("%s signal strength %d is not between 1 and 4", sig.Source, sig.Strength)
+	}
+
+	sig.Ticker = strings.ToUpper(strings.TrimSpace(sig.Ticker))
+	y, m, d := sig.Timestamp.In(calendar.Location).Date()
+	sig.Date = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if math.IsNaN(sig.Confidence) {
+		sig.Confidence = 0
+	}
+	sig.Confidence = math.Round(max(0, min(1, sig.Confidence))*10000) / 10000
+	if sig.Tags == nil {
+		sig.Tags = []string{}
+	}
+	return sig, nil
+}
@@ -0,0 +1,247 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// priceFactor returns what prices before a's ex date are multiplied by, or
+// zero when a dividend's prior close is not known
+func priceFactor(a models.CorporateAction, priorClose float64) float64 {
+	switch a.Type {
+	case models.ActionSplit:
+		if a.SplitFrom > 0 && a.SplitTo > 0 {
+			return a.SplitFrom / a.SplitTo
+		}
+	case models.ActionDividend:
+		if priorClose > a.CashAmount && a.CashAmount > 0 {
+			return 1 - a.CashAmount/priorClose
+		}
+	}
+	return 0
+}
+
+// SaveCorporateActions upserts actions by ID and recomputes the factors of
+// every stored action for the symbols involved
+func (s *MemoryStore) SaveCorporateActions(ctx context.Context, actions []models.CorporateAction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.actions == nil {
+		s.actions = make(map[string]map[string]models.CorporateAction)
+	}
+	touched := make(map[string]bool)
+	for _, a := range actions {
+		if s.actions[a.Symbol] == nil {
+			s.actions[a.Symbol] = make(map[string]models.CorporateAction)
+		}
+		s.actions[a.Symbol][a.ID] = a
+		touched[a.Symbol] = true
+	}
+
+	for symbol := range touched {
+		bars := s.dailyBars[symbol]
+		for id, a := range s.actions[symbol] {
+			// Close of the last bar before the ex date
+			ex := dateOnly(a.ExDate)
+			i := sort.Search(len(bars), func(i int) bool {
+				return !dateOnly(bars[i].Date).Before(ex)
+			})
+			var prior float64
+			if i > 0 {
+				prior = bars[i-1].Close
+			}
+			a.PriceFactor = priceFactor(a, prior)
+			s.actions[symbol][id] = a
+		}
+	}
+	return nil
+}
+
+// GetCorporateActions returns a symbol's actions ordered by ex date
+func (s *MemoryStore) GetCorporateActions(ctx context.Context, symbol string) ([]models.CorporateAction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	actions := make([]models.CorporateAction, 0, len(s.actions[symbol]))
+	for _, a := range s.actions[symbol] {
+		actions = append(actions, a)
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		if !actions[i].ExDate.Equal(actions[j].ExDate) {
+			return actions[i].ExDate.Before(actions[j].ExDate)
+		}
+		return actions[i].ID < actions[j].ID
+	})
+	return actions, nil
+}
+
+// SaveCorporateActions upserts actions by ID, then recomputes price factors
+// for every action of the symbols involved against the stored closes
+func (s *PostgresStore) SaveCorporateActions(ctx context.Context, actions []models.CorporateAction) error {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	symbols := make(map[string]bool)
+	for _, a := range actions {
+		symbols[a.Symbol] = true
+		batch.Queue(`
+			INSERT INTO corporate_actions (id, symbol, action_type, ex_date, split_from, split_to,
+				cash_amount, currency, pay_date, dividend_type)
+			VALUES ($1, $2, $3, $4, NULLIF($5::numeric, 0), NULLIF($6::numeric, 0),
+				NULLIF($7::numeric, 0), NULLIF($8, ''), $9, NULLIF($10, ''))
+			ON CONFLICT (id) DO UPDATE SET
+				symbol = EXCLUDED.symbol,
+				action_type = EXCLUDED.action_type,
+				ex_date = EXCLUDED.ex_date,
+				split_from = EXCLUDED.split_from,
+				split_to = EXCLUDED.split_to,
+				cash_amount = EXCLUDED.cash_amount,
+				currency = EXCLUDED.currency,
+				pay_date = EXCLUDED.pay_date,
+				dividend_type = EXCLUDED.dividend_type,
+				updated_at = NOW()
+		`, a.ID, a.Symbol, a.Type, a.ExDate, a.SplitFrom, a.SplitTo,
+			a.CashAmount, a.Currency, a.PayDate, a.DividendType)
+	}
+	results := tx.SendBatch(ctx, batch)
+	for range actions {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("upserting corporate actions: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("upserting corporate actions: %w", err)
+	}
+
+	symbolList := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		symbolList = append(symbolList, symbol)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE corporate_actions ca SET
+			price_factor = CASE ca.action_type
+				WHEN 'split' THEN ca.split_from / NULLIF(ca.split_to, 0)
+				ELSE (
+					SELECT 1 - ca.cash_amount / d.close
+					FROM daily_bars d
+					WHERE d.symbol = ca.symbol AND d.date < ca.ex_date AND d.close > ca.cash_amount
+					ORDER BY d.date DESC
+					LIMIT 1
+				)
+			END,
+			updated_at = NOW()
+		WHERE ca.symbol = ANY($1)
+	`, symbolList); err != nil {
+		return fmt.Errorf("recomputing adjustment factors: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing corporate actions: %w", err)
+	}
+	return nil
+}
+
+// GetCorporateActions returns a symbol's actions ordered by ex date
+func (s *PostgresStore) GetCorporateActions(ctx context.Context, symbol string) ([]models.CorporateAction, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, symbol, action_type, ex_date, COALESCE(split_from, 0), COALESCE(split_to, 0),
+			COALESCE(cash_amount, 0), COALESCE(currency, ''), pay_date, COALESCE(dividend_type, ''),
+			COALESCE(price_factor, 0)
+		FROM corporate_actions
+		WHERE symbol = $1
+		ORDER BY ex_date, id
+	`, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("querying corporate actions for %s: %w", symbol, err)
+	}
+	return collectCorporateActions(rows)
+}
+
+// collectCorporateActions reads and closes rows of corporate_actions
+func collectCorporateActions(rows pgx.Rows) ([]models.CorporateAction, error) {
+	defer rows.Close()
+
+	actions := make([]models.CorporateAction, 0)
+	for rows.Next() {
+		var a models.CorporateAction
+		if err := rows.Scan(&a.ID, &a.Symbol, &a.Type, &a.ExDate, &a.SplitFrom, &a.SplitTo,
+			&a.CashAmount, &a.Currency, &a.PayDate, &a.DividendType, &a.PriceFactor); err != nil {
+			return nil, fmt.Errorf("scanning corporate action: %w", err)
+		}
+		actions = append(actions, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading corporate actions: %w", err)
+	}
+
+	return actions, nil
+}
+
+// GetCorporateActionsBetween returns every symbol's actions with ex dates
+// from..to (inclusive), ordered by symbol and ex date
+func (s *MemoryStore) GetCorporateActionsBetween(ctx context.Context, from, to time.Time) ([]models.CorporateAction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	actions := make([]models.CorporateAction, 0)
+	for _, byID := range s.actions {
+		for _, a := range byID {
+			ex := dateOnly(a.ExDate)
+			if ex.Before(dateOnly(from)) || ex.After(dateOnly(to)) {
+				continue
+			}
+			actions = append(actions, a)
+		}
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		if actions[i].Symbol != actions[j].Symbol {
+			return actions[i].Symbol < actions[j].Symbol
+		}
+		if !actions[i].ExDate.Equal(actions[j].ExDate) {
+			return actions[i].ExDate.Before(actions[j].ExDate)
+		}
+		return actions[i].ID < actions[j].ID
+	})
+	return actions, nil
+}
+
+// GetCorporateActionsBetween returns every symbol's actions with ex dates
+// from..to (inclusive), ordered by symbol and ex date
+func (s *PostgresStore) GetCorporateActionsBetween(ctx context.Context, from, to time.Time) ([]models.CorporateAction, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, symbol, action_type, ex_date, COALESCE(split_from, 0), COALESCE(split_to, 0),
+			COALESCE(cash_amount, 0), COALESCE(currency, ''), pay_date, COALESCE(dividend_type, ''),
+			COALESCE(price_factor, 0)
+		FROM corporate_actions
+		WHERE ex_date BETWEEN $1 AND $2
+		ORDER BY symbol, ex_date, id
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("querying corporate actions: %w", err)
+	}
+	return collectCorporateActions(rows)
+}
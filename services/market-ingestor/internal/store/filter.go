@@ -0,0 +1,50 @@
+package store
+
+import (
+	"regexp"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// testTickerPattern matches exchange test symbols (ZVZZT, ZXZZT, NTEST.A...)
+// that appear in grouped daily data. It is valid as both a Go and a
+// PostgreSQL regular expression.
+const testTickerPattern = `^(Z[A-Z]ZZT|[A-Z]TEST)(\.[A-Z])?$`
+
+var testTickerRe = regexp.MustCompile(testTickerPattern)
+
+// isTestTicker reports whether symbol is an exchange test symbol
+func isTestTicker(symbol string) bool {
+	return testTickerRe.MatchString(symbol)
+}
+
+// matches reports whether bar passes filter. Symbols without reference data
+// pass the type filter, so lists are not emptied when the ticker refresh
+// has failed or has not yet covered a new listing.
+func (c *tickerCache) matches(bar models.DailyBar, filter models.ScreenerFilter) bool {
+	if isTestTicker(bar.Symbol) {
+		return false
+	}
+	if filter.MinPrice > 0 && bar.Close < filter.MinPrice {
+		return false
+	}
+	if filter.MinVolume > 0 && bar.Volume < filter.MinVolume {
+		return false
+	}
+	if filter.MinDollarVolume > 0 && bar.Close*float64(bar.Volume) < filter.MinDollarVolume {
+		return false
+	}
+	if len(filter.Types) > 0 {
+		t, ok := c.get(bar.Symbol)
+		if !ok {
+			return true
+		}
+		for _, typ := range filter.Types {
+			if t.Type == typ {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,245 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// bucketOrigin aligns rolled-up bars to the 9:30 ET open. Eastern time only
+// ever shifts by whole hours, so buckets that divide an hour stay aligned
+// across daylight saving changes.
+var bucketOrigin = time.Date(2000, 1, 3, 14, 30, 0, 0, time.UTC)
+
+// memoryMinuteRetention is how far back from a symbol's newest minute bar
+// the memory store keeps its intraday history
+const memoryMinuteRetention = 30 * 24 * time.Hour
+
+// bucketStart returns the start of the interval-wide bucket containing t
+func bucketStart(t time.Time, interval time.Duration) time.Time {
+	offset := t.Sub(bucketOrigin) % interval
+	if offset < 0 {
+		offset += interval
+	}
+	return t.Add(-offset)
+}
+
+// rollup combines time-ordered minute bars into interval-wide bars
+func rollup(bars []models.Aggregate, interval time.Duration) []models.Aggregate {
+	if interval <= time.Minute {
+		return bars
+	}
+
+	var out []models.Aggregate
+	var notional float64
+	var weighted int64
+	for _, bar := range bars {
+		start := bucketStart(bar.Timestamp, interval)
+		if len(out) == 0 || !out[len(out)-1].Timestamp.Equal(start) {
+			notional, weighted = 0, 0
+			out = append(out, models.Aggregate{
+				Symbol:    bar.Symbol,
+				Timestamp: start,
+				Open:      bar.Open,
+				High:      bar.High,
+				Low:       bar.Low,
+			})
+		}
+		agg := &out[len(out)-1]
+		agg.High = max(agg.High, bar.High)
+		agg.Low = min(agg.Low, bar.Low)
+		agg.Close = bar.Close
+		agg.Volume += bar.Volume
+		agg.Transactions += bar.Transactions
+		if bar.VWAP > 0 {
+			notional += bar.VWAP * float64(bar.Volume)
+			weighted += bar.Volume
+		}
+		if weighted > 0 {
+			agg.VWAP = notional / float64(weighted)
+		}
+	}
+	return out
+}
+
+// memoryIntraday is the memory store's minute bar history
+type memoryIntraday struct {
+	mu       sync.RWMutex
+	bySymbol map[string][]models.Aggregate // symbol -> bars ordered by time
+}
+
+// save inserts bars in time order, replacing any bar with the same symbol
+// and start time, and drops bars older than memoryMinuteRetention
+func (m *memoryIntraday) save(bars []models.Aggregate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.bySymbol == nil {
+		m.bySymbol = make(map[string][]models.Aggregate)
+	}
+	touched := make(map[string]bool)
+	for _, bar := range bars {
+		symbolBars := m.bySymbol[bar.Symbol]
+		i := sort.Search(len(symbolBars), func(i int) bool {
+			return !symbolBars[i].Timestamp.Before(bar.Timestamp)
+		})
+		if i < len(symbolBars) && symbolBars[i].Timestamp.Equal(bar.Timestamp) {
+			symbolBars[i] = bar
+			continue
+		}
+		symbolBars = append(symbolBars, models.Aggregate{})
+		copy(symbolBars[i+1:], symbolBars[i:])
+		symbolBars[i] = bar
+		m.bySymbol[bar.Symbol] = symbolBars
+		touched[bar.Symbol] = true
+	}
+
+	for symbol := range touched {
+		symbolBars := m.bySymbol[symbol]
+		cutoff := symbolBars[len(symbolBars)-1].Timestamp.Add(-memoryMinuteRetention)
+		i := sort.Search(len(symbolBars), func(i int) bool {
+			return !symbolBars[i].Timestamp.Before(cutoff)
+		})
+		if i > 0 {
+			m.bySymbol[symbol] = append([]models.Aggregate(nil), symbolBars[i:]...)
+		}
+	}
+}
+
+func (m *memoryIntraday) get(symbol string, interval time.Duration, from, to time.Time, limit int) []models.Aggregate {
+	m.mu.RLock()
+	var bars []models.Aggregate
+	for _, bar := range m.bySymbol[symbol] {
+		if !from.IsZero() && bar.Timestamp.Before(from) {
+			continue
+		}
+		if !to.IsZero() && bar.Timestamp.After(to) {
+			break
+		}
+		bars = append(bars, bar)
+	}
+	m.mu.RUnlock()
+
+	bars = rollup(bars, interval)
+	if limit > 0 && len(bars) > limit {
+		bars = bars[len(bars)-limit:]
+	}
+	return bars
+}
+
+// SaveMinuteBars stores intraday minute bars
+func (s *MemoryStore) SaveMinuteBars(ctx context.Context, bars []models.Aggregate) error {
+	s.intraday.save(bars)
+	return nil
+}
+
+// GetIntradayBars returns a symbol's minute bars rolled up to interval
+func (s *MemoryStore) GetIntradayBars(ctx context.Context, symbol string, interval time.Duration, from, to time.Time, limit int) ([]models.Aggregate, error) {
+	return s.intraday.get(symbol, interval, from, to, limit), nil
+}
+
+// SaveMinuteBars upserts intraday minute bars
+func (s *PostgresStore) SaveMinuteBars(ctx context.Context, bars []models.Aggregate) error {
+	if len(bars) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	batch := &pgx.Batch{}
+	for _, bar := range bars {
+		batch.Queue(`
+			INSERT INTO intraday_bars (symbol, ts, open, high, low, close, volume, vwap, transactions)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8::numeric, 0), $9)
+			ON CONFLICT (symbol, ts) DO UPDATE SET
+				open = EXCLUDED.open,
+				high = EXCLUDED.high,
+				low = EXCLUDED.low,
+				close = EXCLUDED.close,
+				volume = EXCLUDED.volume,
+				vwap = EXCLUDED.vwap,
+				transactions = EXCLUDED.transactions,
+				updated_at = NOW()
+		`, bar.Symbol, bar.Timestamp, bar.Open, bar.High, bar.Low, bar.Close, bar.Volume, bar.VWAP, bar.Transactions)
+	}
+
+	results := s.pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for range bars {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("executing intraday batch insert: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetIntradayBars rolls minute bars up with date_bin. The inner query walks
+// the (symbol, ts) primary key newest first so the limit picks the most
+// recent buckets before re-sorting ascending.
+func (s *PostgresStore) GetIntradayBars(ctx context.Context, symbol string, interval time.Duration, from, to time.Time, limit int) ([]models.Aggregate, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	var fromArg, toArg, limitArg any
+	if !from.IsZero() {
+		fromArg = from
+	}
+	if !to.IsZero() {
+		toArg = to
+	}
+	if limit > 0 {
+		limitArg = limit
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT bucket, open, high, low, close, volume, vwap, transactions
+		FROM (
+			SELECT date_bin(make_interval(secs => $2), ts, $5::timestamptz) AS bucket,
+				(array_agg(open ORDER BY ts))[1] AS open,
+				MAX(high) AS high,
+				MIN(low) AS low,
+				(array_agg(close ORDER BY ts DESC))[1] AS close,
+				SUM(volume)::bigint AS volume,
+				COALESCE(SUM(vwap * volume) FILTER (WHERE vwap IS NOT NULL)
+					/ NULLIF(SUM(volume) FILTER (WHERE vwap IS NOT NULL), 0), 0) AS vwap,
+				COALESCE(SUM(transactions), 0)::int AS transactions
+			FROM intraday_bars
+			WHERE symbol = $1
+			  AND ($3::timestamptz IS NULL OR ts >= $3::timestamptz)
+			  AND ($4::timestamptz IS NULL OR ts <= $4::timestamptz)
+			GROUP BY bucket
+			ORDER BY bucket DESC
+			LIMIT $6
+		) recent
+		ORDER BY bucket ASC
+	`, symbol, interval.Seconds(), fromArg, toArg, bucketOrigin, limitArg)
+	if err != nil {
+		return nil, fmt.Errorf("querying intraday bars for %s: %w", symbol, err)
+	}
+	defer rows.Close()
+
+	bars := make([]models.Aggregate, 0)
+	for rows.Next() {
+		bar := models.Aggregate{Symbol: symbol}
+		if err := rows.Scan(&bar.Timestamp, &bar.Open, &bar.High, &bar.Low, &bar.Close,
+			&bar.Volume, &bar.VWAP, &bar.Transactions); err != nil {
+			return nil, fmt.Errorf("scanning intraday bar: %w", err)
+		}
+		bars = append(bars, bar)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading intraday bars: %w", err)
+	}
+
+	return bars, nil
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// liveCache holds each streamed symbol's snapshot of the current session.
+// Both stores keep it in memory; the stream rebuilds it after a restart.
+type liveCache struct {
+	mu        sync.RWMutex
+	snapshots map[string]models.Snapshot
+}
+
+func (c *liveCache) saveSnapshot(snap models.Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.snapshots == nil {
+		c.snapshots = make(map[string]models.Snapshot)
+	}
+	c.snapshots[snap.Symbol] = snap
+}
+
+// getSnapshots returns the snapshots of symbols, or of every streamed
+// symbol when symbols is empty, ordered by symbol
+func (c *liveCache) getSnapshots(symbols []string) []models.Snapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	snaps := make([]models.Snapshot, 0, len(c.snapshots))
+	if len(symbols) == 0 {
+		for _, snap := range c.snapshots {
+			snaps = append(snaps, snap)
+		}
+	} else {
+		seen := make(map[string]bool, len(symbols))
+		for _, symbol := range symbols {
+			if snap, ok := c.snapshots[symbol]; ok && !seen[symbol] {
+				seen[symbol] = true
+				snaps = append(snaps, snap)
+			}
+		}
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Symbol < snaps[j].Symbol })
+	return snaps
+}
@@ -1,10 +1,15 @@
 package store
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
 	"sort"
 	"sync"
 	"time"
 
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/adjust"
 	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
 )
 
@@ -12,23 +17,122 @@ import (
 // For production, replace with PostgreSQL/TimescaleDB
 type MemoryStore struct {
 	mu          sync.RWMutex
-	dailyBars   map[string][]models.DailyBar // symbol -> bars
+	dailyBars   map[string][]models.DailyBar                 // symbol -> bars ordered by date
+	actions     map[string]map[string]models.CorporateAction // symbol -> ID -> action
+	sectorData  map[time.Time]map[string]models.SectorData   // date -> symbol -> row
+	rotations   map[time.Time]models.SectorRotation          // date -> signal
+	strength    map[time.Time][]models.StrengthScore         // date -> scores by rank
+	signals     map[signalKey]models.Signal
+	optionsFlow memoryOptionsFlow
+	tickers     tickerCache
+	live        liveCache
+	intraday    memoryIntraday
+	screens     memoryScreens
+	watchlists  memoryWatchlists
 	lastUpdated time.Time
+
+	opts      MemoryOptions
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// MemoryOptions configures a MemoryStore
+type MemoryOptions struct {
+	// SnapshotPath is the file the store is restored from on creation and
+	// saved to on Close; empty keeps everything in memory
+	SnapshotPath string
+	// SnapshotInterval also saves every interval while running; zero only
+	// saves on Close
+	SnapshotInterval time.Duration
+	Logger           *slog.Logger
 }
 
-func NewMemoryStore() *MemoryStore {
-	return &MemoryStore{
+// NewMemoryStore creates a memory store, restoring the snapshot at
+// opts.SnapshotPath when one exists. An unreadable snapshot is renamed to
+// SnapshotPath.bad and the store starts empty.
+func NewMemoryStore(opts MemoryOptions) *MemoryStore {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+
+	s := &MemoryStore{
 		dailyBars: make(map[string][]models.DailyBar),
+		screens: memoryScreens{
+			byID:    make(map[string]models.SavedScreen),
+			results: make(map[string][]models.ScreenResult),
+		},
+		watchlists: newMemoryWatchlists(),
+		opts:       opts,
+	}
+	if opts.SnapshotPath == "" {
+		return s
+	}
+
+	snap, err := s.loadSnapshot(opts.SnapshotPath)
+	switch {
+	case err != nil:
+		// Keep the unreadable file for inspection rather than overwriting
+		// it with an empty store on Close
+		aside := opts.SnapshotPath + ".bad"
+		if renameErr := os.Rename(opts.SnapshotPath, aside); renameErr != nil {
+			aside = ""
+		}
+		opts.Logger.Warn("ignoring memory store snapshot", "path", opts.SnapshotPath,
+			"moved_to", aside, "error", err)
+	case snap != nil:
+		opts.Logger.Info("restored memory store snapshot", "path", opts.SnapshotPath,
+			"saved_at", snap.SavedAt, "symbols", len(snap.DailyBars))
+	}
+
+	if opts.SnapshotInterval > 0 {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.snapshotLoop()
+	}
+	return s
+}
+
+// snapshotLoop saves a snapshot every SnapshotInterval until Close
+func (s *MemoryStore) snapshotLoop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.SnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.writeSnapshot(s.opts.SnapshotPath); err != nil {
+				s.opts.Logger.Error("failed to save memory store snapshot", "error", err)
+			}
+		}
 	}
 }
 
 // SaveDailyBars stores daily bar data
-func (s *MemoryStore) SaveDailyBars(bars []models.DailyBar) error {
+func (s *MemoryStore) SaveDailyBars(ctx context.Context, bars []models.DailyBar) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// Keep each symbol's bars ordered by date with one bar per day, so
+	// out-of-order backfills and re-ingests behave like the Postgres upsert
 	for _, bar := range bars {
-		s.dailyBars[bar.Symbol] = append(s.dailyBars[bar.Symbol], bar)
+		symbolBars := s.dailyBars[bar.Symbol]
+		day := dateOnly(bar.Date)
+		i := sort.Search(len(symbolBars), func(i int) bool {
+			return !dateOnly(symbolBars[i].Date).Before(day)
+		})
+		if i < len(symbolBars) && dateOnly(symbolBars[i].Date).Equal(day) {
+			symbolBars[i] = bar
+			continue
+		}
+		symbolBars = append(symbolBars, models.DailyBar{})
+		copy(symbolBars[i+1:], symbolBars[i:])
+		symbolBars[i] = bar
+		s.dailyBars[bar.Symbol] = symbolBars
 	}
 	s.lastUpdated = time.Now()
 
@@ -36,7 +140,11 @@ func (s *MemoryStore) SaveDailyBars(bars []models.DailyBar) error {
 }
 
 // GetLatestBars returns the most recent bar for each symbol
-func (s *MemoryStore) GetLatestBars() []models.DailyBar {
+func (s *MemoryStore) GetLatestBars(ctx context.Context) ([]models.DailyBar, error) {
+	return s.latestBars(), nil
+}
+
+func (s *MemoryStore) latestBars() []models.DailyBar {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -50,9 +158,101 @@ func (s *MemoryStore) GetLatestBars() []models.DailyBar {
 	return bars
 }
 
+// filteredLatestBars returns bars on the most recent stored date that pass
+// filter, mirroring the Postgres screener queries
+func (s *MemoryStore) filteredLatestBars(filter models.ScreenerFilter) []models.DailyBar {
+	bars := s.latestBars()
+
+	var latest time.Time
+	for _, bar := range bars {
+		if day := dateOnly(bar.Date); day.After(latest) {
+			latest = day
+		}
+	}
+
+	filtered := bars[:0]
+	for _, bar := range bars {
+		if dateOnly(bar.Date).Equal(latest) && s.tickers.matches(bar, filter) {
+			filtered = append(filtered, bar)
+		}
+	}
+	return filtered
+}
+
+// GetBars returns bars for a symbol within a date range, ordered by date
+func (s *MemoryStore) GetBars(ctx context.Context, symbol string, from, to time.Time, limit int) ([]models.DailyBar, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bars := make([]models.DailyBar, 0)
+	for _, bar := range s.dailyBars[symbol] {
+		day := dateOnly(bar.Date)
+		if !from.IsZero() && day.Before(dateOnly(from)) {
+			continue
+		}
+		if !to.IsZero() && day.After(dateOnly(to)) {
+			break
+		}
+		bars = append(bars, bar)
+	}
+
+	if limit > 0 && len(bars) > limit {
+		bars = bars[len(bars)-limit:]
+	}
+
+	return bars, nil
+}
+
+// GetClosesOn returns the closing price of every symbol with a bar on date
+func (s *MemoryStore) GetClosesOn(ctx context.Context, date time.Time) (map[string]float64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	day := dateOnly(date)
+	closes := make(map[string]float64)
+	for symbol, bars := range s.dailyBars {
+		if bar, ok := barOn(bars, day); ok {
+			closes[symbol] = bar.Close
+		}
+	}
+
+	return closes, nil
+}
+
+// GetStoredDates returns the distinct dates with stored bars between from and to
+func (s *MemoryStore) GetStoredDates(ctx context.Context, from, to time.Time) ([]time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	start, end := dateOnly(from), dateOnly(to)
+	seen := make(map[time.Time]bool)
+	for _, bars := range s.dailyBars {
+		for _, bar := range bars {
+			day := dateOnly(bar.Date)
+			if day.Before(start) {
+				continue
+			}
+			if day.After(end) {
+				break
+			}
+			seen[day] = true
+		}
+	}
+
+	dates := make([]time.Time, 0, len(seen))
+	for day := range seen {
+		dates = append(dates, day)
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+
+	return dates, nil
+}
+
 // GetTopGainers returns top N stocks by percent change
-func (s *MemoryStore) GetTopGainers(n int) []models.ScreenerResult {
-	bars := s.GetLatestBars()
+func (s *MemoryStore) GetTopGainers(ctx context.Context, n int, filter models.ScreenerFilter) ([]models.ScreenerResult, error) {
+	bars := s.filteredLatestBars(filter)
 
 	sort.Slice(bars, func(i, j int) bool {
 		return bars[i].ChangePct > bars[j].ChangePct
@@ -61,22 +261,16 @@ func (s *MemoryStore) GetTopGainers(n int) []models.ScreenerResult {
 	results := make([]models.ScreenerResult, 0, n)
 	for i := 0; i < n && i < len(bars); i++ {
 		if bars[i].ChangePct > 0 {
-			results = append(results, models.ScreenerResult{
-				Symbol:    bars[i].Symbol,
-				Price:     bars[i].Close,
-				Change:    bars[i].Change,
-				ChangePct: bars[i].ChangePct,
-				Volume:    bars[i].Volume,
-			})
+			results = append(results, screenerResult(bars[i]))
 		}
 	}
 
-	return results
+	return s.tickers.nameResults(results), nil
 }
 
 // GetTopLosers returns bottom N stocks by percent change
-func (s *MemoryStore) GetTopLosers(n int) []models.ScreenerResult {
-	bars := s.GetLatestBars()
+func (s *MemoryStore) GetTopLosers(ctx context.Context, n int, filter models.ScreenerFilter) ([]models.ScreenerResult, error) {
+	bars := s.filteredLatestBars(filter)
 
 	sort.Slice(bars, func(i, j int) bool {
 		return bars[i].ChangePct < bars[j].ChangePct
@@ -85,22 +279,16 @@ func (s *MemoryStore) GetTopLosers(n int) []models.ScreenerResult {
 	results := make([]models.ScreenerResult, 0, n)
 	for i := 0; i < n && i < len(bars); i++ {
 		if bars[i].ChangePct < 0 {
-			results = append(results, models.ScreenerResult{
-				Symbol:    bars[i].Symbol,
-				Price:     bars[i].Close,
-				Change:    bars[i].Change,
-				ChangePct: bars[i].ChangePct,
-				Volume:    bars[i].Volume,
-			})
+			results = append(results, screenerResult(bars[i]))
 		}
 	}
 
-	return results
+	return s.tickers.nameResults(results), nil
 }
 
 // GetMostActive returns top N stocks by volume
-func (s *MemoryStore) GetMostActive(n int) []models.ScreenerResult {
-	bars := s.GetLatestBars()
+func (s *MemoryStore) GetMostActive(ctx context.Context, n int, filter models.ScreenerFilter) ([]models.ScreenerResult, error) {
+	bars := s.filteredLatestBars(filter)
 
 	sort.Slice(bars, func(i, j int) bool {
 		return bars[i].Volume > bars[j].Volume
@@ -108,20 +296,73 @@ func (s *MemoryStore) GetMostActive(n int) []models.ScreenerResult {
 
 	results := make([]models.ScreenerResult, 0, n)
 	for i := 0; i < n && i < len(bars); i++ {
-		results = append(results, models.ScreenerResult{
-			Symbol:    bars[i].Symbol,
-			Price:     bars[i].Close,
-			Change:    bars[i].Change,
-			ChangePct: bars[i].ChangePct,
-			Volume:    bars[i].Volume,
+		results = append(results, screenerResult(bars[i]))
+	}
+
+	return s.tickers.nameResults(results), nil
+}
+
+// GetUnusualVolume returns top N stocks by volume ratio at or above minRatio
+func (s *MemoryStore) GetUnusualVolume(ctx context.Context, n int, minRatio float64, filter models.ScreenerFilter) ([]models.ScreenerResult, error) {
+	bars := s.filteredLatestBars(filter)
+
+	sort.Slice(bars, func(i, j int) bool {
+		return bars[i].VolumeRatio > bars[j].VolumeRatio
+	})
+
+	results := make([]models.ScreenerResult, 0, n)
+	for i := 0; i < n && i < len(bars); i++ {
+		if bars[i].VolumeRatio > 0 && bars[i].VolumeRatio >= minRatio {
+			results = append(results, screenerResult(bars[i]))
+		}
+	}
+
+	return s.tickers.nameResults(results), nil
+}
+
+// GetScreenerBars returns the latest date's bars that pass filter
+func (s *MemoryStore) GetScreenerBars(ctx context.Context, filter models.ScreenerFilter) ([]models.DailyBar, error) {
+	return s.filteredLatestBars(filter), nil
+}
+
+// UpdateVolumeStats computes average volume and volume ratio for bars on date,
+// from prior volume restated for splits up to date
+func (s *MemoryStore) UpdateVolumeStats(ctx context.Context, date time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	day := dateOnly(date)
+	for symbol, bars := range s.dailyBars {
+		i := sort.Search(len(bars), func(i int) bool {
+			return !dateOnly(bars[i].Date).Before(day)
 		})
+		if i == len(bars) || !dateOnly(bars[i].Date).Equal(day) {
+			continue
+		}
+
+		// Restate prior volume in the terms of date, ignoring later splits
+		var actions []models.CorporateAction
+		for _, a := range s.actions[symbol] {
+			if !dateOnly(a.ExDate).After(day) {
+				actions = append(actions, a)
+			}
+		}
+		prior := adjust.Bars(bars[max(0, i-volumeWindowLong):i], actions, adjust.Split)
+
+		stats := computeVolumeStats(prior)
+		bars[i].AvgVolume20 = stats.avg20
+		bars[i].AvgVolume50 = stats.avg50
+		bars[i].VolumeRatio = 0
+		if stats.avg20 > 0 {
+			bars[i].VolumeRatio = float64(bars[i].Volume) / float64(stats.avg20)
+		}
 	}
 
-	return results
+	return nil
 }
 
 // GetIndices returns data for major index ETFs
-func (s *MemoryStore) GetIndices() []models.IndexData {
+func (s *MemoryStore) GetIndices(ctx context.Context) ([]models.IndexData, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -146,19 +387,85 @@ func (s *MemoryStore) GetIndices() []models.IndexData {
 		}
 	}
 
-	return indices
+	return indices, nil
+}
+
+// SaveTickers replaces ticker reference data
+func (s *MemoryStore) SaveTickers(ctx context.Context, tickers []models.Ticker) error {
+	s.tickers.replace(tickers, time.Now())
+	return nil
+}
+
+// GetTicker returns reference data for a symbol
+func (s *MemoryStore) GetTicker(ctx context.Context, symbol string) (models.Ticker, error) {
+	t, ok := s.tickers.get(symbol)
+	if !ok {
+		return models.Ticker{}, fmt.Errorf("ticker %s: %w", symbol, ErrNotFound)
+	}
+	return t, nil
+}
+
+// GetTickersUpdated returns when ticker reference data was last refreshed
+func (s *MemoryStore) GetTickersUpdated(ctx context.Context) (time.Time, error) {
+	return s.tickers.lastUpdated(), nil
+}
+
+// HasSectors reports whether any ticker has a sector
+func (s *MemoryStore) HasSectors(ctx context.Context) (bool, error) {
+	return s.tickers.hasSectors(), nil
+}
+
+// SaveSnapshot replaces a symbol's live session snapshot
+func (s *MemoryStore) SaveSnapshot(ctx context.Context, snap models.Snapshot) error {
+	s.live.saveSnapshot(snap)
+	return nil
+}
+
+// GetSnapshots returns live snapshots for symbols, or all when empty
+func (s *MemoryStore) GetSnapshots(ctx context.Context, symbols []string) ([]models.Snapshot, error) {
+	return s.live.getSnapshots(symbols), nil
 }
 
 // GetLastUpdated returns the last update time
-func (s *MemoryStore) GetLastUpdated() time.Time {
+func (s *MemoryStore) GetLastUpdated(ctx context.Context) (time.Time, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.lastUpdated
+	return s.lastUpdated, nil
 }
 
-// Close is a no-op for memory store
+// Close stops periodic snapshots and saves a final one
 func (s *MemoryStore) Close() error {
-	return nil
+	var err error
+	s.closeOnce.Do(func() {
+		if s.stop != nil {
+			close(s.stop)
+			<-s.done
+		}
+		if s.opts.SnapshotPath != "" {
+			if err = s.writeSnapshot(s.opts.SnapshotPath); err == nil {
+				s.opts.Logger.Info("saved memory store snapshot", "path", s.opts.SnapshotPath)
+			}
+		}
+	})
+	return err
+}
+
+// barOn finds the bar on day in a date-ordered slice
+func barOn(bars []models.DailyBar, day time.Time) (models.DailyBar, bool) {
+	i := sort.Search(len(bars), func(i int) bool {
+		return !dateOnly(bars[i].Date).Before(day)
+	})
+	if i < len(bars) && dateOnly(bars[i].Date).Equal(day) {
+		return bars[i], true
+	}
+	return models.DailyBar{}, false
+}
+
+// dateOnly strips the time of day so bars compare by trading date,
+// matching the DATE column semantics of the Postgres store
+func dateOnly(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
 
 // Ensure MemoryStore implements Store interface
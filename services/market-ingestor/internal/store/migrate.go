@@ -0,0 +1,341 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/migrations"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockKey is the pg_advisory_lock key held while migrating, so
+// concurrent runners apply each version once
+const migrationLockKey int64 = 0x6d6b7464_6d696772 // "mktdmigr"
+
+// Migration is one embedded schema version
+type Migration struct {
+	Version int64
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus reports whether a version has been applied
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Unknown marks versions recorded in the database that this binary
+	// does not embed, typically applied by a newer release
+	Unknown bool
+}
+
+// Migrator applies the embedded migrations, recording each applied version
+// in schema_migrations
+type Migrator struct {
+	pool       *pgxpool.Pool
+	ownsPool   bool
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// NewMigrator connects to databaseURL for running migrations; Close
+// releases the connection
+func NewMigrator(ctx context.Context, databaseURL string, logger *slog.Logger) (*Migrator, error) {
+	config, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing database URL: %w", err)
+	}
+	config.MaxConns = 2
+	config.MinConns = 0
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("creating connection pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("pinging database: %w", err)
+	}
+
+	m, err := newMigrator(pool, logger)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	m.ownsPool = true
+	return m, nil
+}
+
+// newMigrator runs the embedded migrations over an existing pool
+func newMigrator(pool *pgxpool.Pool, logger *slog.Logger) (*Migrator, error) {
+	list, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: list, logger: logger}, nil
+}
+
+// Close releases the connection pool opened by NewMigrator
+func (m *Migrator) Close() {
+	if m.ownsPool {
+		m.pool.Close()
+	}
+}
+
+// LoadMigrations reads NNN_name.sql and NNN_name.down.sql files from fsys,
+// ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("listing migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, file := range files {
+		base, isDown := strings.CutSuffix(strings.TrimSuffix(path.Base(file), ".sql"), ".down")
+		prefix, name, ok := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must be NNN_description.sql", file)
+		}
+
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", file, err)
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %d has files named both %q and %q", version, m.Name, name)
+		}
+		if isDown {
+			m.down = string(body)
+		} else {
+			m.up = string(body)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %03d_%s has no up file", m.Version, m.Name)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Up applies every pending migration in version order and returns how many
+// ran. Each migration commits with its schema_migrations row, so a failure
+// leaves earlier versions applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig, mig.up, `
+				INSERT INTO schema_migrations (version, name) VALUES ($1, $2)
+			`, mig.Version, mig.Name); err != nil {
+				return err
+			}
+			m.logger.Info("applied migration", "version", mig.Version, "name", mig.Name)
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the most recently applied steps migrations, newest first,
+// and returns how many ran
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		byVersion := make(map[int64]Migration, len(m.migrations))
+		for _, mig := range m.migrations {
+			byVersion[mig.Version] = mig
+		}
+
+		versions := make([]int64, 0, len(done))
+		for v := range done {
+			versions = append(versions, v)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, v := range versions {
+			if reverted >= steps {
+				break
+			}
+			mig, ok := byVersion[v]
+			if !ok {
+				return fmt.Errorf("migration %d is applied but not embedded in this binary", v)
+			}
+			if mig.down == "" {
+				return fmt.Errorf("migration %03d_%s has no down file", mig.Version, mig.Name)
+			}
+			if err := m.apply(ctx, conn, mig, mig.down, `
+				DELETE FROM schema_migrations WHERE version = $1
+			`, mig.Version); err != nil {
+				return err
+			}
+			m.logger.Info("reverted migration", "version", mig.Version, "name", mig.Name)
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists every embedded migration, plus any applied version this
+// binary does not know, in version order
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquiring connection: %w", err)
+	}
+	defer conn.Release()
+
+	done, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if row, ok := done[mig.Version]; ok {
+			st.Applied, st.AppliedAt = true, row.appliedAt
+			delete(done, mig.Version)
+		}
+		statuses = append(statuses, st)
+	}
+	for v, row := range done {
+		statuses = append(statuses, MigrationStatus{
+			Version: v, Name: row.name, Applied: true, AppliedAt: row.appliedAt, Unknown: true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// locked runs fn on one connection holding the migration advisory lock,
+// after making sure schema_migrations exists
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("acquiring migration lock: %w", err)
+	}
+	defer func() {
+		// Unlock even when ctx is done; the lock is session-scoped and the
+		// connection goes back to the pool
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			m.logger.Warn("releasing migration lock", "error", err)
+			// A connection still holding the lock must not be reused
+			conn.Conn().Close(unlockCtx)
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`); err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// apply runs one migration's SQL and its bookkeeping statement in a
+// transaction
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, mig Migration, body, record string, args ...any) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Without arguments pgx uses the simple protocol, which runs every
+	// statement in the file
+	if _, err := tx.Exec(ctx, body); err != nil {
+		return fmt.Errorf("migration %03d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if _, err := tx.Exec(ctx, record, args...); err != nil {
+		return fmt.Errorf("recording migration %03d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing migration %03d_%s: %w", mig.Version, mig.Name, err)
+	}
+	return nil
+}
+
+// appliedMigration is a schema_migrations row
+type appliedMigration struct {
+	name      string
+	appliedAt time.Time
+}
+
+// appliedVersions reads schema_migrations, which is empty until the first
+// migration runs
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]appliedMigration, error) {
+	var exists bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("checking schema_migrations: %w", err)
+	}
+	done := make(map[int64]appliedMigration)
+	if !exists {
+		return done, nil
+	}
+
+	rows, err := conn.Query(ctx, `SELECT version, name, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("querying schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v int64
+		var row appliedMigration
+		if err := rows.Scan(&v, &row.name, &row.appliedAt); err != nil {
+			return nil, fmt.Errorf("scanning schema_migrations: %w", err)
+		}
+		done[v] = row
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading schema_migrations: %w", err)
+	}
+	return done, nil
+}
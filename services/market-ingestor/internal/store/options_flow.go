@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// memoryOptionsFlow holds stored flow and the alert IDs already seen. It
+// is guarded by MemoryStore.mu.
+type memoryOptionsFlow struct {
+	flows  []models.OptionsFlow
+	alerts map[string]bool
+}
+
+// SaveOptionsFlow stores flow whose alert ID is not already stored
+func (s *MemoryStore) SaveOptionsFlow(ctx context.Context, flows []models.OptionsFlow) ([]models.OptionsFlow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.optionsFlow.alerts == nil {
+		s.optionsFlow.alerts = make(map[string]bool)
+	}
+	saved := make([]models.OptionsFlow, 0, len(flows))
+	for _, flow := range flows {
+		if flow.AlertID != "" {
+			if s.optionsFlow.alerts[flow.AlertID] {
+				continue
+			}
+			s.optionsFlow.alerts[flow.AlertID] = true
+		}
+		flow.ID, flow.CreatedAt = newID(), time.Now()
+		s.optionsFlow.flows = append(s.optionsFlow.flows, flow)
+		saved = append(saved, flow)
+	}
+	return saved, nil
+}
+
+// GetOptionsFlow returns flow matching filter, newest first
+func (s *MemoryStore) GetOptionsFlow(ctx context.Context, filter models.OptionsFlowFilter, limit int) ([]models.OptionsFlow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	flows := make([]models.OptionsFlow, 0)
+	for _, flow := range s.optionsFlow.flows {
+		switch {
+		case filter.Ticker != "" && flow.Ticker != filter.Ticker,
+			filter.Direction != "" && flow.Direction != filter.Direction,
+			!filter.Since.IsZero() && flow.Timestamp.Before(filter.Since):
+			continue
+		}
+		flows = append(flows, flow)
+	}
+	sort.SliceStable(flows, func(i, j int) bool {
+		return flows[i].Timestamp.After(flows[j].Timestamp)
+	})
+	if limit > 0 && len(flows) > limit {
+		flows = flows[:limit]
+	}
+	return flows, nil
+}
+
+// SaveOptionsFlow inserts flow, skipping alert IDs already stored
+func (s *PostgresStore) SaveOptionsFlow(ctx context.Context, flows []models.OptionsFlow) ([]models.OptionsFlow, error) {
+	if len(flows) == 0 {
+		return []models.OptionsFlow{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	batch := &pgx.Batch{}
+	for _, flow := range flows {
+		var rawData any
+		if len(flow.RawData) > 0 {
+			rawData = flow.RawData
+		}
+		batch.Queue(`
+			INSERT INTO options_flow (alert_id, "timestamp", ticker, expiration, strike, option_type, side,
+				trade_type, premium, volume, open_interest, dte, direction, raw_data)
+			VALUES (NULLIF($1, ''), $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11, $12,
+				NULLIF($13, ''), $14::jsonb)
+			ON CONFLICT (alert_id) DO NOTHING
+			RETURNING id::text, created_at
+		`, flow.AlertID, flow.Timestamp, flow.Ticker, flow.Expiration, flow.Strike, string(flow.OptionType),
+			string(flow.Side), string(flow.TradeType), flow.Premium, flow.Volume, flow.OpenInterest, flow.DTE,
+			string(flow.Direction), rawData)
+	}
+
+	results := s.pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	saved := make([]models.OptionsFlow, 0, len(flows))
+	for _, flow := range flows {
+		err := results.QueryRow().Scan(&flow.ID, &flow.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("executing options flow batch insert: %w", err)
+		}
+		saved = append(saved, flow)
+	}
+	return saved, nil
+}
+
+// GetOptionsFlow returns flow matching filter, newest first
+func (s *PostgresStore) GetOptionsFlow(ctx context.Context, filter models.OptionsFlowFilter, limit int) ([]models.OptionsFlow, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var tickerArg, directionArg, sinceArg, limitArg any
+	if filter.Ticker != "" {
+		tickerArg = filter.Ticker
+	}
+	if filter.Direction != "" {
+		directionArg = string(filter.Direction)
+	}
+	if !filter.Since.IsZero() {
+		sinceArg = filter.Since
+	}
+	if limit > 0 {
+		limitArg = limit
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id::text, COALESCE(alert_id, ''), "timestamp", ticker, expiration, strike::float8, option_type,
+			COALESCE(side, ''), COALESCE(trade_type, ''), premium::float8, volume, COALESCE(open_interest, 0),
+			COALESCE(dte, 0), COALESCE(direction, ''), raw_data, created_at
+		FROM options_flow
+		WHERE ($1::text IS NULL OR ticker = $1::text)
+		  AND ($2::text IS NULL OR direction = $2::text)
+		  AND ($3::timestamptz IS NULL OR "timestamp" >= $3::timestamptz)
+		ORDER BY "timestamp" DESC
+		LIMIT $4
+	`, tickerArg, directionArg, sinceArg, limitArg)
+	if err != nil {
+		return nil, fmt.Errorf("querying options flow: %w", err)
+	}
+	defer rows.Close()
+
+	flows := make([]models.OptionsFlow, 0)
+	for rows.Next() {
+		var flow models.OptionsFlow
+		var optionType, side, tradeType, direction string
+		var rawData []byte
+		if err := rows.Scan(&flow.ID, &flow.AlertID, &flow.Timestamp, &flow.Ticker, &flow.Expiration,
+			&flow.Strike, &optionType, &side, &tradeType, &flow.Premium, &flow.Volume, &flow.OpenInterest,
+			&flow.DTE, &direction, &rawData, &flow.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning options flow: %w", err)
+		}
+		flow.OptionType = models.OptionType(optionType)
+		flow.Side = models.OptionSide(side)
+		flow.TradeType = models.TradeType(tradeType)
+		flow.Direction = models.FlowDirection(direction)
+		flow.RawData = rawData
+		flows = append(flows, flow)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading options flow: %w", err)
+	}
+	return flows, nil
+}
@@ -15,11 +15,20 @@ import (
 type PostgresStore struct {
 	pool        *pgxpool.Pool
 	logger      *slog.Logger
+	tickers     tickerCache
+	live        liveCache
 	lastUpdated time.Time
 }
 
+// PostgresOptions configures a PostgresStore
+type PostgresOptions struct {
+	// AutoMigrate applies pending embedded migrations before the store is
+	// used
+	AutoMigrate bool
+}
+
 // NewPostgresStore creates a new PostgreSQL store
-func NewPostgresStore(ctx context.Context, databaseURL string, logger *slog.Logger) (*PostgresStore, error) {
+func NewPostgresStore(ctx context.Context, databaseURL string, logger *slog.Logger, opts PostgresOptions) (*PostgresStore, error) {
 	config, err := pgxpool.ParseConfig(databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("parsing database URL: %w", err)
@@ -44,23 +53,41 @@ func NewPostgresStore(ctx context.Context, databaseURL string, logger *slog.Logg
 
 	logger.Info("connected to PostgreSQL")
 
-	return &PostgresStore{
+	if opts.AutoMigrate {
+		m, err := newMigrator(pool, logger)
+		if err == nil {
+			_, err = m.Up(ctx)
+		}
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("migrating database: %w", err)
+		}
+	}
+
+	s := &PostgresStore{
 		pool:   pool,
 		logger: logger,
-	}, nil
+	}
+
+	// Warm the ticker name cache from the last refresh
+	if err := s.loadTickers(ctx); err != nil {
+		logger.Warn("loading tickers", "error", err)
+	}
+
+	return s, nil
 }
 
 // SaveDailyBars stores daily bar data using upsert
-func (s *PostgresStore) SaveDailyBars(bars []models.DailyBar) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+func (s *PostgresStore) SaveDailyBars(ctx context.Context, bars []models.DailyBar) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
 	batch := &pgx.Batch{}
 
 	for _, bar := range bars {
 		batch.Queue(`
-			INSERT INTO daily_bars (symbol, date, open, high, low, close, volume, vwap, change, change_percent)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			INSERT INTO daily_bars (symbol, date, open, high, low, close, volume, vwap, prev_close, change, change_percent, change_basis)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9::numeric, 0), $10, $11, NULLIF($12, ''))
 			ON CONFLICT (symbol, date) DO UPDATE SET
 				open = EXCLUDED.open,
 				high = EXCLUDED.high,
@@ -68,10 +95,12 @@ func (s *PostgresStore) SaveDailyBars(bars []models.DailyBar) error {
 				close = EXCLUDED.close,
 				volume = EXCLUDED.volume,
 				vwap = EXCLUDED.vwap,
+				prev_close = EXCLUDED.prev_close,
 				change = EXCLUDED.change,
 				change_percent = EXCLUDED.change_percent,
+				change_basis = EXCLUDED.change_basis,
 				updated_at = NOW()
-		`, bar.Symbol, bar.Date, bar.Open, bar.High, bar.Low, bar.Close, bar.Volume, bar.VWAP, bar.Change, bar.ChangePct)
+		`, bar.Symbol, bar.Date, bar.Open, bar.High, bar.Low, bar.Close, bar.Volume, bar.VWAP, bar.PrevClose, bar.Change, bar.ChangePct, string(bar.ChangeBasis))
 	}
 
 	results := s.pool.SendBatch(ctx, batch)
@@ -90,117 +119,319 @@ func (s *PostgresStore) SaveDailyBars(bars []models.DailyBar) error {
 }
 
 // GetLatestBars returns the most recent bar for each symbol
-func (s *PostgresStore) GetLatestBars() []models.DailyBar {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (s *PostgresStore) GetLatestBars(ctx context.Context) ([]models.DailyBar, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	rows, err := s.pool.Query(ctx, `
 		SELECT DISTINCT ON (symbol)
 			symbol, date, open, high, low, close, volume,
-			COALESCE(vwap, 0), COALESCE(change, 0), COALESCE(change_percent, 0)
+			COALESCE(vwap, 0), COALESCE(prev_close, 0), COALESCE(change, 0), COALESCE(change_percent, 0), COALESCE(change_basis, ''),
+			COALESCE(avg_volume_20, 0), COALESCE(avg_volume_50, 0), COALESCE(volume_ratio, 0)
 		FROM daily_bars
 		ORDER BY symbol, date DESC
 	`)
 	if err != nil {
-		s.logger.Error("querying latest bars", "error", err)
-		return nil
+		return nil, fmt.Errorf("querying latest bars: %w", err)
 	}
-	defer rows.Close()
+	return collectDailyBars(rows)
+}
 
-	var bars []models.DailyBar
-	for rows.Next() {
-		var bar models.DailyBar
-		if err := rows.Scan(&bar.Symbol, &bar.Date, &bar.Open, &bar.High, &bar.Low, &bar.Close, &bar.Volume, &bar.VWAP, &bar.Change, &bar.ChangePct); err != nil {
-			s.logger.Error("scanning row", "error", err)
-			continue
-		}
-		bars = append(bars, bar)
+// GetBars returns bars for a symbol within a date range, ordered by date.
+// The inner query walks idx_daily_bars_symbol (symbol, date DESC) so the
+// limit picks the most recent bars before re-sorting ascending.
+func (s *PostgresStore) GetBars(ctx context.Context, symbol string, from, to time.Time, limit int) ([]models.DailyBar, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var fromArg, toArg, limitArg any
+	if !from.IsZero() {
+		fromArg = from
+	}
+	if !to.IsZero() {
+		toArg = to
+	}
+	if limit > 0 {
+		limitArg = limit
 	}
 
-	return bars
+	rows, err := s.pool.Query(ctx, `
+		SELECT symbol, date, open, high, low, close, volume, vwap, prev_close, change, change_percent, change_basis,
+			avg_volume_20, avg_volume_50, volume_ratio
+		FROM (
+			SELECT symbol, date, open, high, low, close, volume,
+				COALESCE(vwap, 0) AS vwap, COALESCE(prev_close, 0) AS prev_close,
+				COALESCE(change, 0) AS change, COALESCE(change_percent, 0) AS change_percent,
+				COALESCE(change_basis, '') AS change_basis,
+				COALESCE(avg_volume_20, 0) AS avg_volume_20, COALESCE(avg_volume_50, 0) AS avg_volume_50,
+				COALESCE(volume_ratio, 0) AS volume_ratio
+			FROM daily_bars
+			WHERE symbol = $1
+			  AND ($2::date IS NULL OR date >= $2::date)
+			  AND ($3::date IS NULL OR date <= $3::date)
+			ORDER BY date DESC
+			LIMIT $4
+		) recent
+		ORDER BY date ASC
+	`, symbol, fromArg, toArg, limitArg)
+	if err != nil {
+		return nil, fmt.Errorf("querying bars for %s: %w", symbol, err)
+	}
+	return collectDailyBars(rows)
 }
 
-// GetTopGainers returns top N stocks by percent change
-func (s *PostgresStore) GetTopGainers(n int) []models.ScreenerResult {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// GetClosesOn returns the closing price of every symbol with a bar on date
+func (s *PostgresStore) GetClosesOn(ctx context.Context, date time.Time) (map[string]float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	rows, err := s.pool.Query(ctx, `
-		SELECT symbol, close, COALESCE(change, 0), COALESCE(change_percent, 0), volume
+		SELECT symbol, close
 		FROM daily_bars
-		WHERE date = (SELECT MAX(date) FROM daily_bars)
-		  AND change_percent > 0
-		ORDER BY change_percent DESC
-		LIMIT $1
-	`, n)
+		WHERE date = $1::date
+	`, date)
 	if err != nil {
-		s.logger.Error("querying top gainers", "error", err)
-		return nil
+		return nil, fmt.Errorf("querying closes on %s: %w", date.Format("2006-01-02"), err)
 	}
 	defer rows.Close()
 
-	return s.scanScreenerResults(rows)
+	closes := make(map[string]float64)
+	for rows.Next() {
+		var symbol string
+		var close float64
+		if err := rows.Scan(&symbol, &close); err != nil {
+			return nil, fmt.Errorf("scanning close: %w", err)
+		}
+		closes[symbol] = close
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading closes: %w", err)
+	}
+
+	return closes, nil
 }
 
-// GetTopLosers returns bottom N stocks by percent change
-func (s *PostgresStore) GetTopLosers(n int) []models.ScreenerResult {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// GetStoredDates returns the distinct dates with stored bars between from and to
+func (s *PostgresStore) GetStoredDates(ctx context.Context, from, to time.Time) ([]time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	rows, err := s.pool.Query(ctx, `
-		SELECT symbol, close, COALESCE(change, 0), COALESCE(change_percent, 0), volume
+		SELECT DISTINCT date
 		FROM daily_bars
-		WHERE date = (SELECT MAX(date) FROM daily_bars)
-		  AND change_percent < 0
-		ORDER BY change_percent ASC
-		LIMIT $1
-	`, n)
+		WHERE date BETWEEN $1::date AND $2::date
+		ORDER BY date
+	`, from, to)
 	if err != nil {
-		s.logger.Error("querying top losers", "error", err)
-		return nil
+		return nil, fmt.Errorf("querying stored dates: %w", err)
 	}
 	defer rows.Close()
 
-	return s.scanScreenerResults(rows)
+	dates := make([]time.Time, 0)
+	for rows.Next() {
+		var date time.Time
+		if err := rows.Scan(&date); err != nil {
+			return nil, fmt.Errorf("scanning stored date: %w", err)
+		}
+		dates = append(dates, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading stored dates: %w", err)
+	}
+
+	return dates, nil
+}
+
+// GetTopGainers returns top N stocks by percent change
+func (s *PostgresStore) GetTopGainers(ctx context.Context, n int, filter models.ScreenerFilter) ([]models.ScreenerResult, error) {
+	return s.queryScreener(ctx, "top gainers", "d.change_percent > 0", "d.change_percent DESC", n, filter)
+}
+
+// GetTopLosers returns bottom N stocks by percent change
+func (s *PostgresStore) GetTopLosers(ctx context.Context, n int, filter models.ScreenerFilter) ([]models.ScreenerResult, error) {
+	return s.queryScreener(ctx, "top losers", "d.change_percent < 0", "d.change_percent ASC", n, filter)
 }
 
 // GetMostActive returns top N stocks by volume
-func (s *PostgresStore) GetMostActive(n int) []models.ScreenerResult {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *PostgresStore) GetMostActive(ctx context.Context, n int, filter models.ScreenerFilter) ([]models.ScreenerResult, error) {
+	return s.queryScreener(ctx, "most active", "TRUE", "d.volume DESC", n, filter)
+}
+
+// GetUnusualVolume returns top N stocks by volume ratio at or above minRatio
+func (s *PostgresStore) GetUnusualVolume(ctx context.Context, n int, minRatio float64, filter models.ScreenerFilter) ([]models.ScreenerResult, error) {
+	return s.queryScreener(ctx, "unusual volume", "d.volume_ratio > 0 AND d.volume_ratio >= $7", "d.volume_ratio DESC", n, filter, minRatio)
+}
+
+// UpdateVolumeStats computes 20 and 50 session average volume from the bars
+// before date, and the volume ratio vs the 20 session average, for every
+// bar on date. Prior volume is divided by the price factors of the splits
+// after it with ex dates up to date. History is read through
+// idx_daily_bars_symbol and splits through idx_corporate_actions_symbol.
+func (s *PostgresStore) UpdateVolumeStats(ctx context.Context, date time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		WITH prior AS (
+			SELECT b.symbol,
+				b.volume / COALESCE((
+					SELECT EXP(SUM(LN(ca.price_factor)))
+					FROM corporate_actions ca
+					WHERE ca.symbol = b.symbol
+					  AND ca.action_type = 'split'
+					  AND ca.price_factor > 0
+					  AND ca.ex_date > b.date
+					  AND ca.ex_date <= $1::date
+				), 1) AS volume,
+				ROW_NUMBER() OVER (PARTITION BY b.symbol ORDER BY b.date DESC) AS rn
+			FROM daily_bars b
+			WHERE b.date < $1::date
+			  AND b.date >= $1::date - 120
+			  AND b.symbol IN (SELECT symbol FROM daily_bars WHERE date = $1::date)
+		), stats AS (
+			SELECT symbol,
+				AVG(volume) FILTER (WHERE rn <= $2) AS avg_short,
+				COUNT(*) FILTER (WHERE rn <= $2) AS n_short,
+				AVG(volume) AS avg_long,
+				COUNT(*) AS n_long
+			FROM prior
+			WHERE rn <= $3
+			GROUP BY symbol
+		)
+		UPDATE daily_bars d SET
+			avg_volume_20 = CASE WHEN st.n_short >= $4 THEN ROUND(st.avg_short) END,
+			avg_volume_50 = CASE WHEN st.n_long >= $5 THEN ROUND(st.avg_long) END,
+			volume_ratio = CASE WHEN st.n_short >= $4 AND st.avg_short > 0
+				THEN ROUND(d.volume / st.avg_short, 4) END
+		FROM stats st
+		WHERE d.symbol = st.symbol AND d.date = $1::date
+	`, date, volumeWindowShort, volumeWindowLong, minHistoryShort, minHistoryLong)
+	if err != nil {
+		return fmt.Errorf("updating volume stats: %w", err)
+	}
+
+	s.logger.Info("updated volume stats", "date", date.Format("2006-01-02"), "bars", tag.RowsAffected())
+	return nil
+}
+
+// screenerConditions applies a models.ScreenerFilter bound by screenerArgs
+// as $1-$5. Like tickerCache.matches, symbols without a tickers row pass the
+// type filter.
+const screenerConditions = `d.symbol !~ $1
+		  AND d.close >= $2
+		  AND d.volume >= $3
+		  AND d.close * d.volume >= $4
+		  AND (cardinality($5::text[]) = 0
+			OR NOT EXISTS (SELECT 1 FROM tickers t WHERE t.symbol = d.symbol)
+			OR EXISTS (
+				SELECT 1 FROM tickers t WHERE t.symbol = d.symbol AND t.type = ANY($5::text[])
+			))`
+
+// screenerArgs returns the bind arguments for screenerConditions
+func screenerArgs(filter models.ScreenerFilter) []any {
+	types := filter.Types
+	if types == nil {
+		// ANY($n) needs a non-nil slice
+		types = []string{}
+	}
+	return []any{testTickerPattern, filter.MinPrice, filter.MinVolume, filter.MinDollarVolume, types}
+}
+
+// queryScreener returns the latest day's bars matching where and filter,
+// sorted by orderBy. where and orderBy are fixed SQL fragments, never user
+// input; n is bound as $6 and extra args from $7.
+func (s *PostgresStore) queryScreener(ctx context.Context, name, where, orderBy string, n int, filter models.ScreenerFilter, extra ...any) ([]models.ScreenerResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
+	args := append(append(screenerArgs(filter), n), extra...)
 	rows, err := s.pool.Query(ctx, `
-		SELECT symbol, close, COALESCE(change, 0), COALESCE(change_percent, 0), volume
-		FROM daily_bars
-		WHERE date = (SELECT MAX(date) FROM daily_bars)
-		ORDER BY volume DESC
-		LIMIT $1
-	`, n)
+		SELECT d.symbol, d.close, COALESCE(d.change, 0), COALESCE(d.change_percent, 0), d.volume,
+			COALESCE(d.avg_volume_20, 0), COALESCE(d.avg_volume_50, 0), COALESCE(d.volume_ratio, 0)
+		FROM daily_bars d
+		WHERE d.date = (SELECT MAX(date) FROM daily_bars)
+		  AND `+where+`
+		  AND `+screenerConditions+`
+		ORDER BY `+orderBy+`
+		LIMIT $6
+	`, args...)
 	if err != nil {
-		s.logger.Error("querying most active", "error", err)
-		return nil
+		return nil, fmt.Errorf("querying %s: %w", name, err)
 	}
+	return s.collectScreenerResults(rows)
+}
+
+// GetScreenerBars returns the latest date's bars that pass filter
+func (s *PostgresStore) GetScreenerBars(ctx context.Context, filter models.ScreenerFilter) ([]models.DailyBar, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT d.symbol, d.date, d.open, d.high, d.low, d.close, d.volume,
+			COALESCE(d.vwap, 0), COALESCE(d.prev_close, 0), COALESCE(d.change, 0), COALESCE(d.change_percent, 0), COALESCE(d.change_basis, ''),
+			COALESCE(d.avg_volume_20, 0), COALESCE(d.avg_volume_50, 0), COALESCE(d.volume_ratio, 0)
+		FROM daily_bars d
+		WHERE d.date = (SELECT MAX(date) FROM daily_bars)
+		  AND `+screenerConditions+`
+	`, screenerArgs(filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("querying screener bars: %w", err)
+	}
+	return collectDailyBars(rows)
+}
+
+// scanDailyBar scans a full daily_bars row selected with COALESCEd nullable columns
+func scanDailyBar(row pgx.Row, bar *models.DailyBar) error {
+	var basis string
+	if err := row.Scan(&bar.Symbol, &bar.Date, &bar.Open, &bar.High, &bar.Low, &bar.Close, &bar.Volume,
+		&bar.VWAP, &bar.PrevClose, &bar.Change, &bar.ChangePct, &basis,
+		&bar.AvgVolume20, &bar.AvgVolume50, &bar.VolumeRatio); err != nil {
+		return err
+	}
+	bar.ChangeBasis = models.ChangeBasis(basis)
+	return nil
+}
+
+// collectDailyBars reads and closes rows of full daily_bars rows
+func collectDailyBars(rows pgx.Rows) ([]models.DailyBar, error) {
 	defer rows.Close()
 
-	return s.scanScreenerResults(rows)
+	bars := make([]models.DailyBar, 0)
+	for rows.Next() {
+		var bar models.DailyBar
+		if err := scanDailyBar(rows, &bar); err != nil {
+			return nil, fmt.Errorf("scanning daily bar: %w", err)
+		}
+		bars = append(bars, bar)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading daily bars: %w", err)
+	}
+	return bars, nil
 }
 
-func (s *PostgresStore) scanScreenerResults(rows pgx.Rows) []models.ScreenerResult {
-	var results []models.ScreenerResult
+// collectScreenerResults reads and closes screener rows, naming them from
+// the ticker cache
+func (s *PostgresStore) collectScreenerResults(rows pgx.Rows) ([]models.ScreenerResult, error) {
+	defer rows.Close()
+
+	results := make([]models.ScreenerResult, 0)
 	for rows.Next() {
 		var r models.ScreenerResult
-		if err := rows.Scan(&r.Symbol, &r.Price, &r.Change, &r.ChangePct, &r.Volume); err != nil {
-			s.logger.Error("scanning screener result", "error", err)
-			continue
+		if err := rows.Scan(&r.Symbol, &r.Price, &r.Change, &r.ChangePct, &r.Volume, &r.AvgVolume, &r.AvgVolume50, &r.VolumeRatio); err != nil {
+			return nil, fmt.Errorf("scanning screener result: %w", err)
 		}
 		results = append(results, r)
 	}
-	return results
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading screener results: %w", err)
+	}
+	return s.tickers.nameResults(results), nil
 }
 
 // GetIndices returns data for major index ETFs
-func (s *PostgresStore) GetIndices() []models.IndexData {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *PostgresStore) GetIndices(ctx context.Context) ([]models.IndexData, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	indexSymbols := map[string]string{
@@ -222,28 +453,136 @@ func (s *PostgresStore) GetIndices() []models.IndexData {
 		  AND symbol = ANY($1)
 	`, symbols)
 	if err != nil {
-		s.logger.Error("querying indices", "error", err)
-		return nil
+		return nil, fmt.Errorf("querying indices: %w", err)
 	}
 	defer rows.Close()
 
-	var indices []models.IndexData
+	indices := make([]models.IndexData, 0, len(symbols))
 	for rows.Next() {
 		var idx models.IndexData
 		if err := rows.Scan(&idx.Symbol, &idx.Price, &idx.Change, &idx.ChangePct); err != nil {
-			s.logger.Error("scanning index", "error", err)
-			continue
+			return nil, fmt.Errorf("scanning index: %w", err)
 		}
 		idx.Name = indexSymbols[idx.Symbol]
 		indices = append(indices, idx)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading indices: %w", err)
+	}
+
+	return indices, nil
+}
+
+// SaveTickers upserts ticker reference data and marks tickers absent from
+// this refresh inactive
+func (s *PostgresStore) SaveTickers(ctx context.Context, tickers []models.Ticker) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	refreshedAt := time.Now()
+	batch := &pgx.Batch{}
+
+	for _, t := range tickers {
+		batch.Queue(`
+			INSERT INTO tickers (symbol, name, type, primary_exchange, cik, active, currency, sector, updated_at)
+			VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6, NULLIF($7, ''), NULLIF($8, ''), $9)
+			ON CONFLICT (symbol) DO UPDATE SET
+				name = EXCLUDED.name,
+				type = EXCLUDED.type,
+				primary_exchange = EXCLUDED.primary_exchange,
+				cik = EXCLUDED.cik,
+				active = EXCLUDED.active,
+				currency = EXCLUDED.currency,
+				sector = COALESCE(EXCLUDED.sector, tickers.sector),
+				updated_at = EXCLUDED.updated_at
+		`, t.Symbol, t.Name, t.Type, t.PrimaryExchange, t.CIK, t.Active, t.Currency, t.Sector, refreshedAt)
+	}
+	batch.Queue(`UPDATE tickers SET active = FALSE WHERE updated_at < $1 AND active`, refreshedAt)
+
+	results := s.pool.SendBatch(ctx, batch)
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("executing ticker upsert: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("closing ticker batch: %w", err)
+	}
+
+	s.tickers.replace(tickers, refreshedAt)
+	s.logger.Info("saved tickers", "count", len(tickers))
+
+	return nil
+}
+
+// loadTickers fills the ticker cache from the tickers table
+func (s *PostgresStore) loadTickers(ctx context.Context) error {
+	rows, err := s.pool.Query(ctx, `
+		SELECT symbol, name, COALESCE(type, ''), COALESCE(primary_exchange, ''),
+			COALESCE(cik, ''), active, COALESCE(currency, ''), COALESCE(sector, ''), updated_at
+		FROM tickers
+	`)
+	if err != nil {
+		return fmt.Errorf("querying tickers: %w", err)
+	}
+	defer rows.Close()
+
+	var tickers []models.Ticker
+	var updatedAt time.Time
+	for rows.Next() {
+		var t models.Ticker
+		var rowUpdated time.Time
+		if err := rows.Scan(&t.Symbol, &t.Name, &t.Type, &t.PrimaryExchange, &t.CIK, &t.Active, &t.Currency, &t.Sector, &rowUpdated); err != nil {
+			return fmt.Errorf("scanning ticker: %w", err)
+		}
+		if rowUpdated.After(updatedAt) {
+			updatedAt = rowUpdated
+		}
+		tickers = append(tickers, t)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading tickers: %w", err)
+	}
+
+	s.tickers.replace(tickers, updatedAt)
+	return nil
+}
+
+// GetTicker returns reference data for a symbol from the cache
+func (s *PostgresStore) GetTicker(ctx context.Context, symbol string) (models.Ticker, error) {
+	t, ok := s.tickers.get(symbol)
+	if !ok {
+		return models.Ticker{}, fmt.Errorf("ticker %s: %w", symbol, ErrNotFound)
+	}
+	return t, nil
+}
+
+// GetTickersUpdated returns when ticker reference data was last refreshed
+func (s *PostgresStore) GetTickersUpdated(ctx context.Context) (time.Time, error) {
+	return s.tickers.lastUpdated(), nil
+}
+
+// HasSectors reports whether any cached ticker has a sector
+func (s *PostgresStore) HasSectors(ctx context.Context) (bool, error) {
+	return s.tickers.hasSectors(), nil
+}
+
+// SaveSnapshot replaces a symbol's live session snapshot. Live state is
+// held in memory; the stream rebuilds it after a restart.
+func (s *PostgresStore) SaveSnapshot(ctx context.Context, snap models.Snapshot) error {
+	s.live.saveSnapshot(snap)
+	return nil
+}
 
-	return indices
+// GetSnapshots returns live snapshots for symbols, or all when empty
+func (s *PostgresStore) GetSnapshots(ctx context.Context, symbols []string) ([]models.Snapshot, error) {
+	return s.live.getSnapshots(symbols), nil
 }
 
 // GetLastUpdated returns the last update time
-func (s *PostgresStore) GetLastUpdated() time.Time {
-	return s.lastUpdated
+func (s *PostgresStore) GetLastUpdated(ctx context.Context) (time.Time, error) {
+	return s.lastUpdated, nil
 }
 
 // Close closes the connection pool
@@ -0,0 +1,366 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// newID returns a random (version 4) UUID, matching gen_random_uuid()
+func newID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// memoryScreens holds saved screens for MemoryStore
+type memoryScreens struct {
+	byID    map[string]models.SavedScreen
+	results map[string][]models.ScreenResult // screen ID -> results ordered by date
+}
+
+// ListScreens returns saved screens ordered by name
+func (s *MemoryStore) ListScreens(ctx context.Context) ([]models.SavedScreen, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	screens := make([]models.SavedScreen, 0, len(s.screens.byID))
+	for _, screen := range s.screens.byID {
+		screens = append(screens, screen)
+	}
+	sort.Slice(screens, func(i, j int) bool {
+		return screens[i].Name < screens[j].Name
+	})
+	return screens, nil
+}
+
+// GetScreen returns a saved screen by ID
+func (s *MemoryStore) GetScreen(ctx context.Context, id string) (models.SavedScreen, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	screen, ok := s.screens.byID[id]
+	if !ok {
+		return models.SavedScreen{}, fmt.Errorf("screen %s: %w", id, ErrNotFound)
+	}
+	return screen, nil
+}
+
+// CreateScreen stores a new screen with a generated ID
+func (s *MemoryStore) CreateScreen(ctx context.Context, screen models.SavedScreen) (models.SavedScreen, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.screenNameTaken(screen.Name, "") {
+		return models.SavedScreen{}, fmt.Errorf("screen %q: %w", screen.Name, ErrDuplicate)
+	}
+
+	now := time.Now()
+	screen.ID = newID()
+	screen.CreatedAt, screen.UpdatedAt = now, now
+	s.screens.byID[screen.ID] = screen
+	return screen, nil
+}
+
+// UpdateScreen replaces a saved screen's definition
+func (s *MemoryStore) UpdateScreen(ctx context.Context, screen models.SavedScreen) (models.SavedScreen, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.screens.byID[screen.ID]
+	if !ok {
+		return models.SavedScreen{}, fmt.Errorf("screen %s: %w", screen.ID, ErrNotFound)
+	}
+	if s.screenNameTaken(screen.Name, screen.ID) {
+		return models.SavedScreen{}, fmt.Errorf("screen %q: %w", screen.Name, ErrDuplicate)
+	}
+
+	screen.CreatedAt = existing.CreatedAt
+	screen.UpdatedAt = time.Now()
+	s.screens.byID[screen.ID] = screen
+	return screen, nil
+}
+
+// DeleteScreen removes a saved screen and its results
+func (s *MemoryStore) DeleteScreen(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.screens.byID[id]; !ok {
+		return fmt.Errorf("screen %s: %w", id, ErrNotFound)
+	}
+	delete(s.screens.byID, id)
+	delete(s.screens.results, id)
+	return nil
+}
+
+func (s *MemoryStore) screenNameTaken(name, exceptID string) bool {
+	for id, screen := range s.screens.byID {
+		if screen.Name == name && id != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveScreenResult stores a screen's result, replacing any on the same date
+func (s *MemoryStore) SaveScreenResult(ctx context.Context, result models.ScreenResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.screens.byID[result.ScreenID]; !ok {
+		return fmt.Errorf("screen %s: %w", result.ScreenID, ErrNotFound)
+	}
+
+	results := s.screens.results[result.ScreenID]
+	day := dateOnly(result.Date)
+	i := sort.Search(len(results), func(i int) bool {
+		return !dateOnly(results[i].Date).Before(day)
+	})
+	if i < len(results) && dateOnly(results[i].Date).Equal(day) {
+		results[i] = result
+		return nil
+	}
+	results = append(results, models.ScreenResult{})
+	copy(results[i+1:], results[i:])
+	results[i] = result
+	s.screens.results[result.ScreenID] = results
+	return nil
+}
+
+// GetScreenResults returns a screen's results on or before to, newest first
+func (s *MemoryStore) GetScreenResults(ctx context.Context, screenID string, to time.Time, limit int) ([]models.ScreenResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored := s.screens.results[screenID]
+	results := make([]models.ScreenResult, 0)
+	for i := len(stored) - 1; i >= 0; i-- {
+		if !to.IsZero() && dateOnly(stored[i].Date).After(dateOnly(to)) {
+			continue
+		}
+		results = append(results, stored[i])
+		if limit > 0 && len(results) == limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique constraint error
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+const screenColumns = `id::text, name, description, filter, sort, sort_order, result_limit, created_at, updated_at`
+
+func scanScreen(row pgx.Row, screen *models.SavedScreen) error {
+	return row.Scan(&screen.ID, &screen.Name, &screen.Description, &screen.Filter, &screen.Sort,
+		&screen.Order, &screen.Limit, &screen.CreatedAt, &screen.UpdatedAt)
+}
+
+// ListScreens returns saved screens ordered by name
+func (s *PostgresStore) ListScreens(ctx context.Context) ([]models.SavedScreen, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `SELECT `+screenColumns+` FROM saved_screens ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("querying saved screens: %w", err)
+	}
+	defer rows.Close()
+
+	screens := make([]models.SavedScreen, 0)
+	for rows.Next() {
+		var screen models.SavedScreen
+		if err := scanScreen(rows, &screen); err != nil {
+			return nil, fmt.Errorf("scanning saved screen: %w", err)
+		}
+		screens = append(screens, screen)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading saved screens: %w", err)
+	}
+	return screens, nil
+}
+
+// GetScreen returns a saved screen by ID
+func (s *PostgresStore) GetScreen(ctx context.Context, id string) (models.SavedScreen, error) {
+	if !uuidRe.MatchString(id) {
+		return models.SavedScreen{}, fmt.Errorf("screen %s: %w", id, ErrNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var screen models.SavedScreen
+	err := scanScreen(s.pool.QueryRow(ctx, `SELECT `+screenColumns+` FROM saved_screens WHERE id = $1::text::uuid`, id), &screen)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return models.SavedScreen{}, fmt.Errorf("screen %s: %w", id, ErrNotFound)
+	case err != nil:
+		return models.SavedScreen{}, fmt.Errorf("querying saved screen: %w", err)
+	}
+	return screen, nil
+}
+
+// CreateScreen stores a new screen with a generated ID
+func (s *PostgresStore) CreateScreen(ctx context.Context, screen models.SavedScreen) (models.SavedScreen, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var created models.SavedScreen
+	err := scanScreen(s.pool.QueryRow(ctx, `
+		INSERT INTO saved_screens (name, description, filter, sort, sort_order, result_limit)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+screenColumns,
+		screen.Name, screen.Description, screen.Filter, screen.Sort, screen.Order, screen.Limit), &created)
+	if isUniqueViolation(err) {
+		return models.SavedScreen{}, fmt.Errorf("screen %q: %w", screen.Name, ErrDuplicate)
+	}
+	if err != nil {
+		return models.SavedScreen{}, fmt.Errorf("inserting saved screen: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateScreen replaces a saved screen's definition
+func (s *PostgresStore) UpdateScreen(ctx context.Context, screen models.SavedScreen) (models.SavedScreen, error) {
+	if !uuidRe.MatchString(screen.ID) {
+		return models.SavedScreen{}, fmt.Errorf("screen %s: %w", screen.ID, ErrNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var updated models.SavedScreen
+	err := scanScreen(s.pool.QueryRow(ctx, `
+		UPDATE saved_screens SET
+			name = $2, description = $3, filter = $4, sort = $5, sort_order = $6, result_limit = $7,
+			updated_at = NOW()
+		WHERE id = $1::text::uuid
+		RETURNING `+screenColumns,
+		screen.ID, screen.Name, screen.Description, screen.Filter, screen.Sort, screen.Order, screen.Limit), &updated)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return models.SavedScreen{}, fmt.Errorf("screen %s: %w", screen.ID, ErrNotFound)
+	case isUniqueViolation(err):
+		return models.SavedScreen{}, fmt.Errorf("screen %q: %w", screen.Name, ErrDuplicate)
+	case err != nil:
+		return models.SavedScreen{}, fmt.Errorf("updating saved screen: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteScreen removes a saved screen; its results cascade
+func (s *PostgresStore) DeleteScreen(ctx context.Context, id string) error {
+	if !uuidRe.MatchString(id) {
+		return fmt.Errorf("screen %s: %w", id, ErrNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `DELETE FROM saved_screens WHERE id = $1::text::uuid`, id)
+	if err != nil {
+		return fmt.Errorf("deleting saved screen: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("screen %s: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// SaveScreenResult upserts a screen's result for its date
+func (s *PostgresStore) SaveScreenResult(ctx context.Context, result models.ScreenResult) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var previous any
+	if !result.PreviousDate.IsZero() {
+		previous = result.PreviousDate
+	}
+
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO screen_results (screen_id, date, previous_date, symbols, entered, exited, evaluated_at)
+		VALUES ($1::text::uuid, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (screen_id, date) DO UPDATE SET
+			previous_date = EXCLUDED.previous_date,
+			symbols = EXCLUDED.symbols,
+			entered = EXCLUDED.entered,
+			exited = EXCLUDED.exited,
+			evaluated_at = EXCLUDED.evaluated_at
+	`, result.ScreenID, result.Date, previous, nonNil(result.Symbols), nonNil(result.Entered), nonNil(result.Exited), result.EvaluatedAt)
+	if err != nil {
+		return fmt.Errorf("saving screen result: %w", err)
+	}
+	return nil
+}
+
+// GetScreenResults returns a screen's results on or before to, newest first
+func (s *PostgresStore) GetScreenResults(ctx context.Context, screenID string, to time.Time, limit int) ([]models.ScreenResult, error) {
+	if !uuidRe.MatchString(screenID) {
+		return []models.ScreenResult{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var toArg, limitArg any
+	if !to.IsZero() {
+		toArg = to
+	}
+	if limit > 0 {
+		limitArg = limit
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT screen_id::text, date, previous_date, symbols, entered, exited, evaluated_at
+		FROM screen_results
+		WHERE screen_id = $1::text::uuid
+		  AND ($2::date IS NULL OR date <= $2::date)
+		ORDER BY date DESC
+		LIMIT $3
+	`, screenID, toArg, limitArg)
+	if err != nil {
+		return nil, fmt.Errorf("querying screen results: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]models.ScreenResult, 0)
+	for rows.Next() {
+		var r models.ScreenResult
+		var previous *time.Time
+		if err := rows.Scan(&r.ScreenID, &r.Date, &previous, &r.Symbols, &r.Entered, &r.Exited, &r.EvaluatedAt); err != nil {
+			return nil, fmt.Errorf("scanning screen result: %w", err)
+		}
+		if previous != nil {
+			r.PreviousDate = *previous
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading screen results: %w", err)
+	}
+	return results, nil
+}
+
+// nonNil returns s, or an empty slice for nil so it stores as '{}' not NULL
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,217 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/sectors"
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveSectorData stores sector rows, replacing any for the same symbol and
+// date
+func (s *MemoryStore) SaveSectorData(ctx context.Context, data []models.SectorData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sectorData == nil {
+		s.sectorData = make(map[time.Time]map[string]models.SectorData)
+	}
+	for _, row := range data {
+		day := dateOnly(row.Date)
+		if s.sectorData[day] == nil {
+			s.sectorData[day] = make(map[string]models.SectorData)
+		}
+		s.sectorData[day][row.Symbol] = row
+	}
+	return nil
+}
+
+// GetSectorData returns the sector rows on date, or on the latest date
+// stored when date is zero, strongest first
+func (s *MemoryStore) GetSectorData(ctx context.Context, date time.Time) ([]models.SectorData, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	day := dateOnly(date)
+	if date.IsZero() {
+		for d := range s.sectorData {
+			if d.After(day) {
+				day = d
+			}
+		}
+	}
+
+	data := make([]models.SectorData, 0, len(s.sectorData[day]))
+	for _, row := range s.sectorData[day] {
+		data = append(data, row)
+	}
+	sectors.SortByStrength(data)
+	return data, nil
+}
+
+// SaveSectorRotation stores a day's rotation signal, replacing any earlier one
+func (s *MemoryStore) SaveSectorRotation(ctx context.Context, rotation models.SectorRotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rotations == nil {
+		s.rotations = make(map[time.Time]models.SectorRotation)
+	}
+	s.rotations[dateOnly(rotation.Date)] = rotation
+	return nil
+}
+
+// GetSectorRotation returns the rotation signal on date, or the latest when
+// date is zero
+func (s *MemoryStore) GetSectorRotation(ctx context.Context, date time.Time) (models.SectorRotation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	day := dateOnly(date)
+	if date.IsZero() {
+		for d := range s.rotations {
+			if d.After(day) {
+				day = d
+			}
+		}
+	}
+
+	rotation, ok := s.rotations[day]
+	if !ok {
+		return models.SectorRotation{}, fmt.Errorf("sector rotation: %w", ErrNotFound)
+	}
+	return rotation, nil
+}
+
+// SaveSectorData upserts sector rows by symbol and date
+func (s *PostgresStore) SaveSectorData(ctx context.Context, data []models.SectorData) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	batch := &pgx.Batch{}
+	for _, row := range data {
+		batch.Queue(`
+			INSERT INTO sector_data (symbol, name, date, open, high, low, close, volume,
+				change_percent, relative_strength, volume_ratio)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11::numeric, 0))
+			ON CONFLICT (symbol, date) DO UPDATE SET
+				name = EXCLUDED.name,
+				open = EXCLUDED.open,
+				high = EXCLUDED.high,
+				low = EXCLUDED.low,
+				close = EXCLUDED.close,
+				volume = EXCLUDED.volume,
+				change_percent = EXCLUDED.change_percent,
+				relative_strength = EXCLUDED.relative_strength,
+				volume_ratio = EXCLUDED.volume_ratio
+		`, row.Symbol, row.Name, row.Date, row.Open, row.High, row.Low, row.Close, row.Volume,
+			row.ChangePct, row.RelativeStrength, row.VolumeRatio)
+	}
+
+	results := s.pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for range data {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("upserting sector data: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetSectorData returns the sector rows on date, or on the latest date with
+// prices when date is zero (the schema seeds placeholder rows with a zero
+// close), strongest first
+func (s *PostgresStore) GetSectorData(ctx context.Context, date time.Time) ([]models.SectorData, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var dateArg any
+	if !date.IsZero() {
+		dateArg = date
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT symbol, name, date, COALESCE(open, 0), COALESCE(high, 0), COALESCE(low, 0), close,
+			COALESCE(volume, 0), COALESCE(change_percent, 0), COALESCE(relative_strength, 0),
+			COALESCE(volume_ratio, 0)
+		FROM sector_data
+		WHERE close > 0
+		  AND date = COALESCE($1::date, (SELECT MAX(date) FROM sector_data WHERE close > 0))
+		ORDER BY relative_strength DESC NULLS LAST, symbol
+	`, dateArg)
+	if err != nil {
+		return nil, fmt.Errorf("querying sector data: %w", err)
+	}
+	defer rows.Close()
+
+	data := make([]models.SectorData, 0)
+	for rows.Next() {
+		var row models.SectorData
+		if err := rows.Scan(&row.Symbol, &row.Name, &row.Date, &row.Open, &row.High, &row.Low, &row.Close,
+			&row.Volume, &row.ChangePct, &row.RelativeStrength, &row.VolumeRatio); err != nil {
+			return nil, fmt.Errorf("scanning sector data: %w", err)
+		}
+		data = append(data, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading sector data: %w", err)
+	}
+
+	return data, nil
+}
+
+// SaveSectorRotation upserts a day's rotation signal
+func (s *PostgresStore) SaveSectorRotation(ctx context.Context, rotation models.SectorRotation) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO sector_rotation (date, signal, leading_sectors, lagging_sectors, notes)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		ON CONFLICT (date) DO UPDATE SET
+			signal = EXCLUDED.signal,
+			leading_sectors = EXCLUDED.leading_sectors,
+			lagging_sectors = EXCLUDED.lagging_sectors,
+			notes = EXCLUDED.notes
+	`, rotation.Date, rotation.Signal, nonNil(rotation.Leading), nonNil(rotation.Lagging), rotation.Notes); err != nil {
+		return fmt.Errorf("upserting sector rotation: %w", err)
+	}
+	return nil
+}
+
+// GetSectorRotation returns the rotation signal on date, or the latest when
+// date is zero
+func (s *PostgresStore) GetSectorRotation(ctx context.Context, date time.Time) (models.SectorRotation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var dateArg any
+	if !date.IsZero() {
+		dateArg = date
+	}
+
+	var rotation models.SectorRotation
+	err := s.pool.QueryRow(ctx, `
+		SELECT date, signal, COALESCE(leading_sectors, '[]'), COALESCE(lagging_sectors, '[]'), COALESCE(notes, '')
+		FROM sector_rotation
+		WHERE $1::date IS NULL OR date = $1::date
+		ORDER BY date DESC
+		LIMIT 1
+	`, dateArg).Scan(&rotation.Date, &rotation.Signal, &rotation.Leading, &rotation.Lagging, &rotation.Notes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.SectorRotation{}, fmt.Errorf("sector rotation: %w", ErrNotFound)
+	}
+	if err != nil {
+		return models.SectorRotation{}, fmt.Errorf("querying sector rotation: %w", err)
+	}
+	return rotation, nil
+}
@@ -0,0 +1,203 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// signalKey is what a stored signal is unique by
+type signalKey struct {
+	source models.SignalSource
+	ticker string
+	date   time.Time
+}
+
+func signalKeyOf(sig models.Signal) signalKey {
+	return signalKey{source: sig.Source, ticker: sig.Ticker, date: dateOnly(sig.Date)}
+}
+
+// signalBefore reports whether a sorts before b newest first
+func signalBefore(a, b models.Signal) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.After(b.Timestamp)
+	}
+	return a.ID > b.ID
+}
+
+// SaveSignals stores signals, replacing a stored signal for the same
+// source, ticker and session only with a stronger one
+func (s *MemoryStore) SaveSignals(ctx context.Context, signals []models.Signal) ([]models.Signal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.signals == nil {
+		s.signals = make(map[signalKey]models.Signal)
+	}
+	saved := make([]models.Signal, 0, len(signals))
+	for _, sig := range signals {
+		key := signalKeyOf(sig)
+		if existing, ok := s.signals[key]; ok {
+			if sig.Strength <= existing.Strength {
+				continue
+			}
+			sig.ID, sig.CreatedAt = existing.ID, existing.CreatedAt
+		} else {
+			sig.ID, sig.CreatedAt = newID(), time.Now()
+		}
+		s.signals[key] = sig
+		saved = append(saved, sig)
+	}
+	return saved, nil
+}
+
+// GetSignals returns signals matching filter, newest first
+func (s *MemoryStore) GetSignals(ctx context.Context, filter models.SignalFilter, limit int) ([]models.Signal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cursor := models.Signal{Timestamp: filter.Before, ID: filter.BeforeID}
+	signals := make([]models.Signal, 0)
+	for _, sig := range s.signals {
+		switch {
+		case filter.Source != "" && sig.Source != filter.Source,
+			filter.Ticker != "" && sig.Ticker != filter.Ticker,
+			filter.Direction != "" && sig.Direction != filter.Direction,
+			!filter.Since.IsZero() && sig.Timestamp.Before(filter.Since),
+			!filter.Before.IsZero() && !signalBefore(cursor, sig):
+			continue
+		}
+		signals = append(signals, sig)
+	}
+	sort.Slice(signals, func(i, j int) bool {
+		return signalBefore(signals[i], signals[j])
+	})
+	if limit > 0 && len(signals) > limit {
+		signals = signals[:limit]
+	}
+	return signals, nil
+}
+
+// SaveSignals upserts signals on (source, ticker, session_date), only
+// replacing a stored signal with a stronger one. Skipped signals return
+// no row.
+func (s *PostgresStore) SaveSignals(ctx context.Context, signals []models.Signal) ([]models.Signal, error) {
+	if len(signals) == 0 {
+		return []models.Signal{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	batch := &pgx.Batch{}
+	for _, sig := range signals {
+		var rawData any
+		if len(sig.RawData) > 0 {
+			rawData = sig.RawData
+		}
+		batch.Queue(`
+			INSERT INTO signals (source, "timestamp", session_date, ticker, direction, strength, confidence, tags, raw_data)
+			VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8::jsonb, $9::jsonb)
+			ON CONFLICT (source, COALESCE(ticker, ''), session_date) DO UPDATE SET
+				"timestamp" = EXCLUDED."timestamp",
+				direction = EXCLUDED.direction,
+				strength = EXCLUDED.strength,
+				confidence = EXCLUDED.confidence,
+				tags = EXCLUDED.tags,
+				raw_data = EXCLUDED.raw_data
+			WHERE signals.strength IS NULL OR signals.strength < EXCLUDED.strength
+			RETURNING id::text, created_at
+		`, string(sig.Source), sig.Timestamp, sig.Date, sig.Ticker, string(sig.Direction),
+			int(sig.Strength), sig.Confidence, nonNil(sig.Tags), rawData)
+	}
+
+	results := s.pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	saved := make([]models.Signal, 0, len(signals))
+	for _, sig := range signals {
+		err := results.QueryRow().Scan(&sig.ID, &sig.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("executing signals batch insert: %w", err)
+		}
+		saved = append(saved, sig)
+	}
+	return saved, nil
+}
+
+// GetSignals returns signals matching filter, newest first, walking
+// idx_signals_timestamp from the cursor
+func (s *PostgresStore) GetSignals(ctx context.Context, filter models.SignalFilter, limit int) ([]models.Signal, error) {
+	if filter.BeforeID != "" && !uuidRe.MatchString(filter.BeforeID) {
+		return []models.Signal{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var sourceArg, tickerArg, directionArg, sinceArg, beforeArg, beforeIDArg, limitArg any
+	if filter.Source != "" {
+		sourceArg = string(filter.Source)
+	}
+	if filter.Ticker != "" {
+		tickerArg = filter.Ticker
+	}
+	if filter.Direction != "" {
+		directionArg = string(filter.Direction)
+	}
+	if !filter.Since.IsZero() {
+		sinceArg = filter.Since
+	}
+	if !filter.Before.IsZero() {
+		beforeArg, beforeIDArg = filter.Before, filter.BeforeID
+	}
+	if limit > 0 {
+		limitArg = limit
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id::text, source, "timestamp", session_date, COALESCE(ticker, ''), COALESCE(direction, ''),
+			COALESCE(strength, 0), COALESCE(confidence, 0), COALESCE(tags, '[]'::jsonb), raw_data, created_at
+		FROM signals
+		WHERE ($1::text IS NULL OR source = $1::text)
+		  AND ($2::text IS NULL OR ticker = $2::text)
+		  AND ($3::text IS NULL OR direction = $3::text)
+		  AND ($4::timestamptz IS NULL OR "timestamp" >= $4::timestamptz)
+		  AND ($5::timestamptz IS NULL OR ("timestamp", id) < ($5::timestamptz, COALESCE($6::text, '00000000-0000-0000-0000-000000000000')::uuid))
+		ORDER BY "timestamp" DESC, id DESC
+		LIMIT $7
+	`, sourceArg, tickerArg, directionArg, sinceArg, beforeArg, beforeIDArg, limitArg)
+	if err != nil {
+		return nil, fmt.Errorf("querying signals: %w", err)
+	}
+	defer rows.Close()
+
+	signals := make([]models.Signal, 0)
+	for rows.Next() {
+		var sig models.Signal
+		var source, direction string
+		var strength int
+		var rawData []byte
+		if err := rows.Scan(&sig.ID, &source, &sig.Timestamp, &sig.Date, &sig.Ticker, &direction,
+			&strength, &sig.Confidence, &sig.Tags, &rawData, &sig.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning signal: %w", err)
+		}
+		sig.Source = models.SignalSource(source)
+		sig.Direction = models.SignalDirection(direction)
+		sig.Strength = models.SignalStrength(strength)
+		sig.RawData = rawData
+		signals = append(signals, sig)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading signals: %w", err)
+	}
+	return signals, nil
+}
@@ -0,0 +1,289 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// Snapshot files start with a fixed header followed by a gzip-compressed
+// gob payload:
+//
+//	magic   [8]byte  "MDSNAP\x00\x00"
+//	version uint16   snapshotVersion
+//	length  uint64   payload bytes
+//	crc32   uint32   Castagnoli checksum of the payload
+//
+// Integers are big-endian. A version bump is needed whenever memorySnapshot
+// changes in a way gob cannot decode from older files.
+const snapshotVersion uint16 = 1
+
+var (
+	snapshotMagic = [8]byte{'M', 'D', 'S', 'N', 'A', 'P'}
+	snapshotCRC   = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// snapshotHeaderSize is magic + version + length + crc32
+const snapshotHeaderSize = 8 + 2 + 8 + 4
+
+// errSnapshotCorrupt reports a snapshot file that fails validation
+var errSnapshotCorrupt = errors.New("snapshot corrupt")
+
+// memorySnapshot is the persisted state of a MemoryStore. Live snapshots
+// describe the current session only and are not saved.
+type memorySnapshot struct {
+	SavedAt        time.Time
+	LastUpdated    time.Time
+	DailyBars      map[string][]models.DailyBar
+	Actions        map[string]map[string]models.CorporateAction
+	Tickers        map[string]models.Ticker
+	TickersUpdated time.Time
+	MinuteBars     map[string][]models.Aggregate
+	Screens        map[string]models.SavedScreen
+	ScreenResults  map[string][]models.ScreenResult
+	SectorData     map[time.Time]map[string]models.SectorData
+	Rotations      map[time.Time]models.SectorRotation
+	Strength       map[time.Time][]models.StrengthScore
+	Watchlists     map[string]models.Watchlist
+	Signals        []models.Signal
+	OptionsFlow    []models.OptionsFlow
+}
+
+// capture copies the store's state. Slices are copied because bars are
+// updated in place.
+func (s *MemoryStore) capture() *memorySnapshot {
+	snap := &memorySnapshot{SavedAt: time.Now()}
+
+	s.mu.RLock()
+	snap.LastUpdated = s.lastUpdated
+	snap.DailyBars = make(map[string][]models.DailyBar, len(s.dailyBars))
+	for symbol, bars := range s.dailyBars {
+		snap.DailyBars[symbol] = append([]models.DailyBar(nil), bars...)
+	}
+	snap.Actions = make(map[string]map[string]models.CorporateAction, len(s.actions))
+	for symbol, byID := range s.actions {
+		snap.Actions[symbol] = make(map[string]models.CorporateAction, len(byID))
+		for id, a := range byID {
+			snap.Actions[symbol][id] = a
+		}
+	}
+	snap.Screens = make(map[string]models.SavedScreen, len(s.screens.byID))
+	for id, screen := range s.screens.byID {
+		snap.Screens[id] = screen
+	}
+	snap.ScreenResults = make(map[string][]models.ScreenResult, len(s.screens.results))
+	for id, results := range s.screens.results {
+		snap.ScreenResults[id] = append([]models.ScreenResult(nil), results...)
+	}
+	snap.SectorData = make(map[time.Time]map[string]models.SectorData, len(s.sectorData))
+	for day, bySymbol := range s.sectorData {
+		snap.SectorData[day] = make(map[string]models.SectorData, len(bySymbol))
+		for symbol, row := range bySymbol {
+			snap.SectorData[day][symbol] = row
+		}
+	}
+	snap.Rotations = make(map[time.Time]models.SectorRotation, len(s.rotations))
+	for day, rotation := range s.rotations {
+		snap.Rotations[day] = rotation
+	}
+	snap.Strength = make(map[time.Time][]models.StrengthScore, len(s.strength))
+	for day, scores := range s.strength {
+		snap.Strength[day] = scores
+	}
+	snap.Signals = make([]models.Signal, 0, len(s.signals))
+	for _, sig := range s.signals {
+		snap.Signals = append(snap.Signals, sig)
+	}
+	snap.OptionsFlow = append([]models.OptionsFlow(nil), s.optionsFlow.flows...)
+	snap.Watchlists = make(map[string]models.Watchlist, len(s.watchlists.byID))
+	for id, list := range s.watchlists.byID {
+		snap.Watchlists[id] = list
+	}
+	s.mu.RUnlock()
+
+	s.tickers.mu.RLock()
+	snap.Tickers = make(map[string]models.Ticker, len(s.tickers.bySymbol))
+	for symbol, t := range s.tickers.bySymbol {
+		snap.Tickers[symbol] = t
+	}
+	snap.TickersUpdated = s.tickers.updatedAt
+	s.tickers.mu.RUnlock()
+
+	s.intraday.mu.RLock()
+	snap.MinuteBars = make(map[string][]models.Aggregate, len(s.intraday.bySymbol))
+	for symbol, bars := range s.intraday.bySymbol {
+		snap.MinuteBars[symbol] = append([]models.Aggregate(nil), bars...)
+	}
+	s.intraday.mu.RUnlock()
+
+	return snap
+}
+
+// restore replaces the store's state with snap
+func (s *MemoryStore) restore(snap *memorySnapshot) {
+	s.mu.Lock()
+	s.lastUpdated = snap.LastUpdated
+	if snap.DailyBars != nil {
+		s.dailyBars = snap.DailyBars
+	}
+	s.actions = snap.Actions
+	s.sectorData = snap.SectorData
+	s.rotations = snap.Rotations
+	s.strength = snap.Strength
+	if snap.Screens != nil {
+		s.screens.byID = snap.Screens
+	}
+	if snap.ScreenResults != nil {
+		// gob does not distinguish empty slices from nil, and the API
+		// renders symbol lists as arrays
+		for _, results := range snap.ScreenResults {
+			for i := range results {
+				results[i].Symbols = nonNil(results[i].Symbols)
+				results[i].Entered = nonNil(results[i].Entered)
+				results[i].Exited = nonNil(results[i].Exited)
+			}
+		}
+		s.screens.results = snap.ScreenResults
+	}
+	s.signals = make(map[signalKey]models.Signal, len(snap.Signals))
+	for _, sig := range snap.Signals {
+		sig.Tags = nonNil(sig.Tags)
+		s.signals[signalKeyOf(sig)] = sig
+	}
+	s.optionsFlow = memoryOptionsFlow{flows: snap.OptionsFlow, alerts: make(map[string]bool, len(snap.OptionsFlow))}
+	for _, flow := range snap.OptionsFlow {
+		if flow.AlertID != "" {
+			s.optionsFlow.alerts[flow.AlertID] = true
+		}
+	}
+	// Snapshots saved before watchlists existed keep the seeded list
+	if snap.Watchlists != nil {
+		for id, list := range snap.Watchlists {
+			if list.Items == nil {
+				list.Items = []models.WatchlistItem{}
+				snap.Watchlists[id] = list
+			}
+		}
+		s.watchlists.byID = snap.Watchlists
+	}
+	s.mu.Unlock()
+
+	s.tickers.mu.Lock()
+	s.tickers.bySymbol = snap.Tickers
+	s.tickers.updatedAt = snap.TickersUpdated
+	s.tickers.mu.Unlock()
+
+	s.intraday.mu.Lock()
+	s.intraday.bySymbol = snap.MinuteBars
+	s.intraday.mu.Unlock()
+}
+
+// encodeSnapshot returns snap in the snapshot file format
+func encodeSnapshot(snap *memorySnapshot) ([]byte, error) {
+	var payload bytes.Buffer
+	zw := gzip.NewWriter(&payload)
+	if err := gob.NewEncoder(zw).Encode(snap); err != nil {
+		return nil, fmt.Errorf("encoding snapshot: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compressing snapshot: %w", err)
+	}
+
+	out := make([]byte, snapshotHeaderSize, snapshotHeaderSize+payload.Len())
+	copy(out, snapshotMagic[:])
+	binary.BigEndian.PutUint16(out[8:], snapshotVersion)
+	binary.BigEndian.PutUint64(out[10:], uint64(payload.Len()))
+	binary.BigEndian.PutUint32(out[18:], crc32.Checksum(payload.Bytes(), snapshotCRC))
+	return append(out, payload.Bytes()...), nil
+}
+
+// decodeSnapshot validates and decodes a snapshot file
+func decodeSnapshot(data []byte) (*memorySnapshot, error) {
+	if len(data) < snapshotHeaderSize || !bytes.Equal(data[:8], snapshotMagic[:]) {
+		return nil, fmt.Errorf("%w: not a snapshot file", errSnapshotCorrupt)
+	}
+	if v := binary.BigEndian.Uint16(data[8:]); v != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (expected %d)", v, snapshotVersion)
+	}
+	payload := data[snapshotHeaderSize:]
+	if n := binary.BigEndian.Uint64(data[10:]); n != uint64(len(payload)) {
+		return nil, fmt.Errorf("%w: payload is %d bytes, header says %d", errSnapshotCorrupt, len(payload), n)
+	}
+	if sum := crc32.Checksum(payload, snapshotCRC); sum != binary.BigEndian.Uint32(data[18:]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", errSnapshotCorrupt)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing snapshot: %w", err)
+	}
+	defer zr.Close()
+
+	var snap memorySnapshot
+	if err := gob.NewDecoder(zr).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("decoding snapshot: %w", err)
+	}
+	return &snap, nil
+}
+
+// writeSnapshot saves the store to path, atomically replacing any earlier
+// snapshot
+func (s *MemoryStore) writeSnapshot(path string) error {
+	data, err := encodeSnapshot(s.capture())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating snapshot directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("syncing snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing snapshot: %w", err)
+	}
+	return nil
+}
+
+// loadSnapshot restores the store from path. A missing file is not an
+// error.
+func (s *MemoryStore) loadSnapshot(path string) (*memorySnapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	snap, err := decodeSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+	s.restore(snap)
+	return snap, nil
+}
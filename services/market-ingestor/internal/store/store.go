@@ -1,33 +1,239 @@
 package store
 
 import (
+	"context"
+	"errors"
+	"net"
 	"time"
 
 	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
-// Store defines the interface for market data storage
+var (
+	// ErrNotFound is returned when reading, updating or deleting a record
+	// that does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicate is returned when a record would violate a uniqueness rule
+	ErrDuplicate = errors.New("already exists")
+)
+
+// IsUnavailable reports whether err means the database could not be
+// reached, as opposed to a query that failed once connected or ran out of
+// time. (context.DeadlineExceeded is itself a net.Error.)
+func IsUnavailable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	return errors.As(err, &connectErr) || errors.As(err, &netErr)
+}
+
+// Store defines the interface for market data storage. Every method takes
+// the caller's context, so an API request's queries are abandoned when the
+// client goes away, and returns any storage failure rather than an empty
+// result.
 type Store interface {
 	// SaveDailyBars stores daily bar data
-	SaveDailyBars(bars []models.DailyBar) error
+	SaveDailyBars(ctx context.Context, bars []models.DailyBar) error
 
 	// GetLatestBars returns the most recent bar for each symbol
-	GetLatestBars() []models.DailyBar
+	GetLatestBars(ctx context.Context) ([]models.DailyBar, error)
+
+	// GetBars returns bars for a symbol between from and to (inclusive),
+	// ordered by date ascending. A zero from/to leaves that side unbounded.
+	// If limit > 0, only the most recent limit bars in the range are returned.
+	GetBars(ctx context.Context, symbol string, from, to time.Time, limit int) ([]models.DailyBar, error)
+
+	// GetClosesOn returns the closing price of every symbol with a bar on date
+	GetClosesOn(ctx context.Context, date time.Time) (map[string]float64, error)
+
+	// GetStoredDates returns the distinct dates between from and to
+	// (inclusive) that have at least one stored bar, ordered ascending
+	GetStoredDates(ctx context.Context, from, to time.Time) ([]time.Time, error)
 
 	// GetTopGainers returns top N stocks by percent change
-	GetTopGainers(n int) []models.ScreenerResult
+	GetTopGainers(ctx context.Context, n int, filter models.ScreenerFilter) ([]models.ScreenerResult, error)
 
 	// GetTopLosers returns bottom N stocks by percent change
-	GetTopLosers(n int) []models.ScreenerResult
+	GetTopLosers(ctx context.Context, n int, filter models.ScreenerFilter) ([]models.ScreenerResult, error)
 
 	// GetMostActive returns top N stocks by volume
-	GetMostActive(n int) []models.ScreenerResult
+	GetMostActive(ctx context.Context, n int, filter models.ScreenerFilter) ([]models.ScreenerResult, error)
+
+	// GetUnusualVolume returns top N stocks by volume ratio at or above minRatio
+	GetUnusualVolume(ctx context.Context, n int, minRatio float64, filter models.ScreenerFilter) ([]models.ScreenerResult, error)
+
+	// GetScreenerBars returns every bar on the latest stored date that passes
+	// filter, the universe custom screens are evaluated against
+	GetScreenerBars(ctx context.Context, filter models.ScreenerFilter) ([]models.DailyBar, error)
+
+	// UpdateVolumeStats computes average volume and volume ratio for every
+	// bar on date from the bars stored before it, their volume restated for
+	// splits with ex dates up to date so a split does not read as a surge
+	UpdateVolumeStats(ctx context.Context, date time.Time) error
 
 	// GetIndices returns data for major index ETFs
-	GetIndices() []models.IndexData
+	GetIndices(ctx context.Context) ([]models.IndexData, error)
+
+	// SaveTickers replaces ticker reference data with a full refresh; symbols
+	// missing from tickers are kept but marked inactive
+	SaveTickers(ctx context.Context, tickers []models.Ticker) error
+
+	// GetTicker returns reference data for a symbol, or ErrNotFound
+	GetTicker(ctx context.Context, symbol string) (models.Ticker, error)
+
+	// GetTickersUpdated returns when ticker reference data was last refreshed
+	GetTickersUpdated(ctx context.Context) (time.Time, error)
+
+	// HasSectors reports whether any ticker reference data carries a sector
+	HasSectors(ctx context.Context) (bool, error)
+
+	// ListScreens returns saved screens ordered by name
+	ListScreens(ctx context.Context) ([]models.SavedScreen, error)
+
+	// GetScreen returns a saved screen by ID, or ErrNotFound
+	GetScreen(ctx context.Context, id string) (models.SavedScreen, error)
+
+	// CreateScreen stores a new screen, assigning its ID and timestamps. It
+	// returns ErrDuplicate if the name is taken.
+	CreateScreen(ctx context.Context, screen models.SavedScreen) (models.SavedScreen, error)
+
+	// UpdateScreen replaces the definition of the screen with screen.ID. It
+	// returns ErrNotFound or ErrDuplicate.
+	UpdateScreen(ctx context.Context, screen models.SavedScreen) (models.SavedScreen, error)
+
+	// DeleteScreen removes a screen and its results, or returns ErrNotFound
+	DeleteScreen(ctx context.Context, id string) error
+
+	// SaveScreenResult stores a screen's result, replacing any for the same date
+	SaveScreenResult(ctx context.Context, result models.ScreenResult) error
+
+	// GetScreenResults returns a screen's results on or before to, most
+	// recent first. A zero to is unbounded; limit > 0 caps the count.
+	GetScreenResults(ctx context.Context, screenID string, to time.Time, limit int) ([]models.ScreenResult, error)
+
+	// ListWatchlists returns watchlists and their items, ordered by name
+	ListWatchlists(ctx context.Context) ([]models.Watchlist, error)
+
+	// GetWatchlist returns a watchlist and its items by ID, or ErrNotFound
+	GetWatchlist(ctx context.Context, id string) (models.Watchlist, error)
+
+	// CreateWatchlist stores a new, empty watchlist, assigning its ID and
+	// timestamps
+	CreateWatchlist(ctx context.Context, list models.Watchlist) (models.Watchlist, error)
+
+	// UpdateWatchlist replaces the name and description of the watchlist
+	// with list.ID, or returns ErrNotFound
+	UpdateWatchlist(ctx context.Context, list models.Watchlist) (models.Watchlist, error)
+
+	// DeleteWatchlist removes a watchlist and its items, or returns
+	// ErrNotFound
+	DeleteWatchlist(ctx context.Context, id string) error
+
+	// AddWatchlistItem adds a ticker to a watchlist, setting AddedAt. It
+	// returns ErrNotFound for a missing watchlist, or ErrDuplicate if the
+	// ticker is already on it.
+	AddWatchlistItem(ctx context.Context, watchlistID string, item models.WatchlistItem) (models.WatchlistItem, error)
+
+	// UpdateWatchlistItem replaces the notes on item.Ticker, or returns
+	// ErrNotFound
+	UpdateWatchlistItem(ctx context.Context, watchlistID string, item models.WatchlistItem) (models.WatchlistItem, error)
+
+	// RemoveWatchlistItem removes a ticker from a watchlist, or returns
+	// ErrNotFound
+	RemoveWatchlistItem(ctx context.Context, watchlistID, ticker string) error
+
+	// GetWatchlistQuotes returns a watchlist's items, ordered by ticker, each
+	// with its latest bar and latest strength score, or ErrNotFound
+	GetWatchlistQuotes(ctx context.Context, id string) ([]models.WatchlistQuote, error)
+
+	// SaveCorporateActions upserts splits and dividends by ID, then
+	// recomputes the price factors of every stored action for the symbols
+	// involved
+	SaveCorporateActions(ctx context.Context, actions []models.CorporateAction) error
+
+	// GetCorporateActions returns a symbol's splits and dividends ordered by
+	// ex date
+	GetCorporateActions(ctx context.Context, symbol string) ([]models.CorporateAction, error)
+
+	// SaveSectorData stores sector ETF rows, replacing any for the same
+	// symbol and date
+	SaveSectorData(ctx context.Context, data []models.SectorData) error
+
+	// GetSectorData returns the sector rows on date, or on the latest date
+	// stored when date is zero, by relative strength, strongest first
+	GetSectorData(ctx context.Context, date time.Time) ([]models.SectorData, error)
+
+	// SaveSectorRotation stores a day's rotation signal, replacing any
+	// earlier one for the date
+	SaveSectorRotation(ctx context.Context, rotation models.SectorRotation) error
+
+	// GetSectorRotation returns the rotation signal on date, or the latest
+	// when date is zero, or ErrNotFound
+	GetSectorRotation(ctx context.Context, date time.Time) (models.SectorRotation, error)
+
+	// GetCorporateActionsBetween returns every symbol's splits and dividends
+	// with ex dates between from and to (inclusive), ordered by symbol and
+	// ex date
+	GetCorporateActionsBetween(ctx context.Context, from, to time.Time) ([]models.CorporateAction, error)
+
+	// ScanBars calls fn once per symbol, in symbol order, with its bars
+	// between from and to (inclusive) ordered by date, so the whole
+	// universe's history never has to be held at once. A nil symbols scans
+	// every symbol; an error from fn stops the scan and is returned.
+	ScanBars(ctx context.Context, symbols []string, from, to time.Time, fn func(symbol string, bars []models.DailyBar) error) error
+
+	// SaveStrengthScores replaces the strength scores stored for date
+	SaveStrengthScores(ctx context.Context, date time.Time, scores []models.StrengthScore) error
+
+	// GetStrengthScores returns up to limit scores on date, or on the latest
+	// date scored when date is zero, strongest first, or weakest first with
+	// bottom. limit <= 0 returns every score.
+	GetStrengthScores(ctx context.Context, date time.Time, limit int, bottom bool) ([]models.StrengthScore, error)
+
+	// GetStrengthScore returns a ticker's score on date, or its latest when
+	// date is zero, or ErrNotFound
+	GetStrengthScore(ctx context.Context, ticker string, date time.Time) (models.StrengthScore, error)
+
+	// SaveSignals stores signals, keeping one per source, ticker and
+	// session date: a signal replaces the stored one only if it is
+	// stronger. It returns the signals stored, with their IDs.
+	SaveSignals(ctx context.Context, signals []models.Signal) ([]models.Signal, error)
+
+	// GetSignals returns up to limit signals matching filter, newest first
+	// by timestamp then ID. limit <= 0 returns every match.
+	GetSignals(ctx context.Context, filter models.SignalFilter, limit int) ([]models.Signal, error)
+
+	// SaveOptionsFlow stores flow, skipping any whose alert ID is already
+	// stored, and returns the flow stored, with IDs
+	SaveOptionsFlow(ctx context.Context, flows []models.OptionsFlow) ([]models.OptionsFlow, error)
+
+	// GetOptionsFlow returns up to limit flows matching filter, newest
+	// first. limit <= 0 returns every match.
+	GetOptionsFlow(ctx context.Context, filter models.OptionsFlowFilter, limit int) ([]models.OptionsFlow, error)
+
+	// SaveMinuteBars stores intraday minute bars, replacing any bar with the
+	// same symbol and start time
+	SaveMinuteBars(ctx context.Context, bars []models.Aggregate) error
+
+	// GetIntradayBars returns a symbol's minute bars starting between from
+	// and to (inclusive) rolled up to interval, a whole number of minutes,
+	// oldest first. Buckets are aligned to the 9:30 ET open. A zero from/to
+	// leaves that side unbounded; if limit > 0, only the most recent limit
+	// bars are returned.
+	GetIntradayBars(ctx context.Context, symbol string, interval time.Duration, from, to time.Time, limit int) ([]models.Aggregate, error)
+
+	// SaveSnapshot replaces a symbol's live session snapshot
+	SaveSnapshot(ctx context.Context, snap models.Snapshot) error
+
+	// GetSnapshots returns live snapshots for symbols, or for every streamed
+	// symbol when symbols is empty, ordered by symbol
+	GetSnapshots(ctx context.Context, symbols []string) ([]models.Snapshot, error)
 
 	// GetLastUpdated returns the last update time
-	GetLastUpdated() time.Time
+	GetLastUpdated(ctx context.Context) (time.Time, error)
 
 	// Close closes any connections (no-op for memory store)
 	Close() error
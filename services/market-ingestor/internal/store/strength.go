@@ -0,0 +1,283 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// ScanBars calls fn with each symbol's bars between from and to
+// (inclusive), ordered by date
+func (s *MemoryStore) ScanBars(ctx context.Context, symbols []string, from, to time.Time, fn func(symbol string, bars []models.DailyBar) error) error {
+	if symbols == nil {
+		s.mu.RLock()
+		for symbol := range s.dailyBars {
+			symbols = append(symbols, symbol)
+		}
+		s.mu.RUnlock()
+	}
+	symbols = append([]string(nil), symbols...)
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		bars, _ := s.GetBars(ctx, symbol, from, to, 0)
+		if len(bars) == 0 {
+			continue
+		}
+		if err := fn(symbol, bars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveStrengthScores replaces the scores stored for date
+func (s *MemoryStore) SaveStrengthScores(ctx context.Context, date time.Time, scores []models.StrengthScore) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.strength == nil {
+		s.strength = make(map[time.Time][]models.StrengthScore)
+	}
+	ranked := append([]models.StrengthScore(nil), scores...)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Rank < ranked[j].Rank })
+	s.strength[dateOnly(date)] = ranked
+	return nil
+}
+
+// GetStrengthScores returns the strongest, or with bottom the weakest,
+// scores on date, or on the latest date scored when date is zero
+func (s *MemoryStore) GetStrengthScores(ctx context.Context, date time.Time, limit int, bottom bool) ([]models.StrengthScore, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	day := dateOnly(date)
+	if date.IsZero() {
+		for d := range s.strength {
+			if d.After(day) {
+				day = d
+			}
+		}
+	}
+
+	ranked := s.strength[day]
+	if limit <= 0 || limit > len(ranked) {
+		limit = len(ranked)
+	}
+	scores := make([]models.StrengthScore, 0, limit)
+	for i := 0; i < limit; i++ {
+		if bottom {
+			scores = append(scores, ranked[len(ranked)-1-i])
+		} else {
+			scores = append(scores, ranked[i])
+		}
+	}
+	return scores, nil
+}
+
+// GetStrengthScore returns a ticker's score on date, or its latest when
+// date is zero
+func (s *MemoryStore) GetStrengthScore(ctx context.Context, ticker string, date time.Time) (models.StrengthScore, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found models.StrengthScore
+	for day, ranked := range s.strength {
+		if !date.IsZero() && !day.Equal(dateOnly(date)) {
+			continue
+		}
+		if !found.Date.IsZero() && !day.After(dateOnly(found.Date)) {
+			continue
+		}
+		for _, score := range ranked {
+			if score.Ticker == ticker {
+				found = score
+				break
+			}
+		}
+	}
+	if found.Date.IsZero() {
+		return models.StrengthScore{}, fmt.Errorf("strength score for %s: %w", ticker, ErrNotFound)
+	}
+	return found, nil
+}
+
+// ScanBars streams bars ordered by symbol and date, calling fn as each
+// symbol's run completes. A nil symbols scans every symbol.
+func (s *PostgresStore) ScanBars(ctx context.Context, symbols []string, from, to time.Time, fn func(symbol string, bars []models.DailyBar) error) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT symbol, date, open, high, low, close, volume,
+			COALESCE(vwap, 0), COALESCE(prev_close, 0), COALESCE(change, 0), COALESCE(change_percent, 0), COALESCE(change_basis, ''),
+			COALESCE(avg_volume_20, 0), COALESCE(avg_volume_50, 0), COALESCE(volume_ratio, 0)
+		FROM daily_bars
+		WHERE ($1::text[] IS NULL OR symbol = ANY($1))
+		  AND date BETWEEN $2 AND $3
+		ORDER BY symbol, date
+	`, symbols, from, to)
+	if err != nil {
+		return fmt.Errorf("querying bar history: %w", err)
+	}
+	defer rows.Close()
+
+	var bars []models.DailyBar
+	for rows.Next() {
+		var bar models.DailyBar
+		if err := scanDailyBar(rows, &bar); err != nil {
+			return fmt.Errorf("scanning daily bar: %w", err)
+		}
+		if len(bars) > 0 && bars[0].Symbol != bar.Symbol {
+			if err := fn(bars[0].Symbol, bars); err != nil {
+				return err
+			}
+			bars = nil
+		}
+		bars = append(bars, bar)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading bar history: %w", err)
+	}
+	if len(bars) > 0 {
+		return fn(bars[0].Symbol, bars)
+	}
+	return nil
+}
+
+// SaveStrengthScores replaces the scores stored for date in one transaction
+func (s *PostgresStore) SaveStrengthScores(ctx context.Context, date time.Time, scores []models.StrengthScore) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM strength_scores WHERE date = $1`, date); err != nil {
+		return fmt.Errorf("clearing strength scores: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, sc := range scores {
+		c := sc.Components
+		batch.Queue(`
+			INSERT INTO strength_scores (ticker, date, composite_score, momentum_1d, momentum_5d, momentum_20d,
+				rs_vs_spy, volume_trend, ma_alignment, high_low_position, rank, total_ranked)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		`, sc.Ticker, date, sc.Composite, c.Momentum1D, c.Momentum5D, c.Momentum20D,
+			c.RSVsSPY, c.VolumeTrend, c.MAAlignment, c.HighLowPosition, sc.Rank, sc.TotalRanked)
+	}
+	results := tx.SendBatch(ctx, batch)
+	for range scores {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("inserting strength scores: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("inserting strength scores: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing strength scores: %w", err)
+	}
+	return nil
+}
+
+// strengthColumns selects a full strength_scores row for scanStrengthScore
+const strengthColumns = `
+	ticker, date, composite_score, COALESCE(momentum_1d, 0), COALESCE(momentum_5d, 0),
+	COALESCE(momentum_20d, 0), COALESCE(rs_vs_spy, 0), COALESCE(volume_trend, 0),
+	COALESCE(ma_alignment, 0), COALESCE(high_low_position, 0), COALESCE(rank, 0), COALESCE(total_ranked, 0)
+`
+
+func scanStrengthScore(row pgx.Row, sc *models.StrengthScore) error {
+	c := &sc.Components
+	return row.Scan(&sc.Ticker, &sc.Date, &sc.Composite, &c.Momentum1D, &c.Momentum5D,
+		&c.Momentum20D, &c.RSVsSPY, &c.VolumeTrend,
+		&c.MAAlignment, &c.HighLowPosition, &sc.Rank, &sc.TotalRanked)
+}
+
+// GetStrengthScores returns the strongest, or with bottom the weakest,
+// scores on date, or on the latest date scored when date is zero
+func (s *PostgresStore) GetStrengthScores(ctx context.Context, date time.Time, limit int, bottom bool) ([]models.StrengthScore, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var dateArg, limitArg any
+	if !date.IsZero() {
+		dateArg = date
+	}
+	if limit > 0 {
+		limitArg = limit
+	}
+	order := "ASC"
+	if bottom {
+		order = "DESC"
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+strengthColumns+`
+		FROM strength_scores
+		WHERE date = COALESCE($1::date, (SELECT MAX(date) FROM strength_scores))
+		ORDER BY rank `+order+`
+		LIMIT $2
+	`, dateArg, limitArg)
+	if err != nil {
+		return nil, fmt.Errorf("querying strength scores: %w", err)
+	}
+	defer rows.Close()
+
+	scores := make([]models.StrengthScore, 0)
+	for rows.Next() {
+		var sc models.StrengthScore
+		if err := scanStrengthScore(rows, &sc); err != nil {
+			return nil, fmt.Errorf("scanning strength score: %w", err)
+		}
+		scores = append(scores, sc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading strength scores: %w", err)
+	}
+
+	return scores, nil
+}
+
+// GetStrengthScore returns a ticker's score on date, or its latest when
+// date is zero
+func (s *PostgresStore) GetStrengthScore(ctx context.Context, ticker string, date time.Time) (models.StrengthScore, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var dateArg any
+	if !date.IsZero() {
+		dateArg = date
+	}
+
+	var sc models.StrengthScore
+	err := scanStrengthScore(s.pool.QueryRow(ctx, `
+		SELECT `+strengthColumns+`
+		FROM strength_scores
+		WHERE ticker = $1 AND ($2::date IS NULL OR date = $2::date)
+		ORDER BY date DESC
+		LIMIT 1
+	`, ticker, dateArg), &sc)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.StrengthScore{}, fmt.Errorf("strength score for %s: %w", ticker, ErrNotFound)
+	}
+	if err != nil {
+		return models.StrengthScore{}, fmt.Errorf("querying strength score for %s: %w", ticker, err)
+	}
+	return sc, nil
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+// tickerCache is the in-memory symbol -> reference data map both stores use
+// to join company names into screener and index results
+type tickerCache struct {
+	mu        sync.RWMutex
+	bySymbol  map[string]models.Ticker
+	updatedAt time.Time
+}
+
+// replace swaps in a full ticker set. Symbols no longer listed are kept but
+// marked inactive, and a known sector survives a refresh that lacks one,
+// matching the Postgres refresh semantics.
+func (c *tickerCache) replace(tickers []models.Ticker, updatedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := make(map[string]models.Ticker, len(tickers))
+	for symbol, t := range c.bySymbol {
+		t.Active = false
+		next[symbol] = t
+	}
+	for _, t := range tickers {
+		if t.Sector == "" {
+			t.Sector = next[t.Symbol].Sector
+		}
+		next[t.Symbol] = t
+	}
+	c.bySymbol = next
+	c.updatedAt = updatedAt
+}
+
+func (c *tickerCache) get(symbol string) (models.Ticker, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	t, ok := c.bySymbol[symbol]
+	return t, ok
+}
+
+func (c *tickerCache) lastUpdated() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.updatedAt
+}
+
+func (c *tickerCache) hasSectors() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, t := range c.bySymbol {
+		if t.Sector != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// nameResults fills Name on each result from reference data
+func (c *tickerCache) nameResults(results []models.ScreenerResult) []models.ScreenerResult {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i := range results {
+		if t, ok := c.bySymbol[results[i].Symbol]; ok && t.Name != "" {
+			results[i].Name = t.Name
+		}
+	}
+	return results
+}
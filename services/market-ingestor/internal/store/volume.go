@@ -0,0 +1,54 @@
+package store
+
+import "github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+
+// Average volume windows and the history each needs before it is reported.
+// Requiring half the window keeps thinly-traded new listings from showing
+// extreme ratios off a handful of sessions.
+const (
+	volumeWindowShort = 20
+	volumeWindowLong  = 50
+	minHistoryShort   = volumeWindowShort / 2
+	minHistoryLong    = volumeWindowLong / 2
+)
+
+type volumeStats struct {
+	avg20 int64
+	avg50 int64
+}
+
+// computeVolumeStats averages volume over the trailing windows of prior,
+// which holds the sessions before the bar being scored, oldest first
+func computeVolumeStats(prior []models.DailyBar) volumeStats {
+	var stats volumeStats
+	var sum20, sum50 int64
+	n := len(prior)
+	for i := n - 1; i >= 0 && n-i <= volumeWindowLong; i-- {
+		sum50 += prior[i].Volume
+		if n-i <= volumeWindowShort {
+			sum20 += prior[i].Volume
+		}
+	}
+
+	if short := min(n, volumeWindowShort); short >= minHistoryShort {
+		stats.avg20 = sum20 / int64(short)
+	}
+	if long := min(n, volumeWindowLong); long >= minHistoryLong {
+		stats.avg50 = sum50 / int64(long)
+	}
+	return stats
+}
+
+// screenerResult converts a bar to a screener row
+func screenerResult(bar models.DailyBar) models.ScreenerResult {
+	return models.ScreenerResult{
+		Symbol:      bar.Symbol,
+		Price:       bar.Close,
+		Change:      bar.Change,
+		ChangePct:   bar.ChangePct,
+		Volume:      bar.Volume,
+		AvgVolume:   bar.AvgVolume20,
+		AvgVolume50: bar.AvgVolume50,
+		VolumeRatio: bar.VolumeRatio,
+	}
+}
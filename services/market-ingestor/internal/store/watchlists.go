@@ -0,0 +1,536 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// defaultWatchlist is created with an empty store, as the schema seeds it
+var defaultWatchlist = models.Watchlist{
+	Name:        "My Holdings",
+	Description: "Track your current stock holdings",
+}
+
+// memoryWatchlists holds watchlists for MemoryStore. Item slices are
+// replaced rather than modified, so callers may keep the ones returned.
+type memoryWatchlists struct {
+	byID map[string]models.Watchlist
+}
+
+func newMemoryWatchlists() memoryWatchlists {
+	now := time.Now()
+	seed := defaultWatchlist
+	seed.ID = newID()
+	seed.Items = []models.WatchlistItem{}
+	seed.CreatedAt, seed.UpdatedAt = now, now
+	return memoryWatchlists{byID: map[string]models.Watchlist{seed.ID: seed}}
+}
+
+// ListWatchlists returns watchlists and their items ordered by name
+func (s *MemoryStore) ListWatchlists(ctx context.Context) ([]models.Watchlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lists := make([]models.Watchlist, 0, len(s.watchlists.byID))
+	for _, list := range s.watchlists.byID {
+		lists = append(lists, list)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		if lists[i].Name != lists[j].Name {
+			return lists[i].Name < lists[j].Name
+		}
+		return lists[i].CreatedAt.Before(lists[j].CreatedAt)
+	})
+	return lists, nil
+}
+
+// GetWatchlist returns a watchlist and its items by ID
+func (s *MemoryStore) GetWatchlist(ctx context.Context, id string) (models.Watchlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list, ok := s.watchlists.byID[id]
+	if !ok {
+		return models.Watchlist{}, fmt.Errorf("watchlist %s: %w", id, ErrNotFound)
+	}
+	return list, nil
+}
+
+// CreateWatchlist stores a new, empty watchlist with a generated ID
+func (s *MemoryStore) CreateWatchlist(ctx context.Context, list models.Watchlist) (models.Watchlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	list.ID = newID()
+	list.Items = []models.WatchlistItem{}
+	list.CreatedAt, list.UpdatedAt = now, now
+	s.watchlists.byID[list.ID] = list
+	return list, nil
+}
+
+// UpdateWatchlist renames a watchlist and replaces its description
+func (s *MemoryStore) UpdateWatchlist(ctx context.Context, list models.Watchlist) (models.Watchlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.watchlists.byID[list.ID]
+	if !ok {
+		return models.Watchlist{}, fmt.Errorf("watchlist %s: %w", list.ID, ErrNotFound)
+	}
+	existing.Name = list.Name
+	existing.Description = list.Description
+	existing.UpdatedAt = time.Now()
+	s.watchlists.byID[list.ID] = existing
+	return existing, nil
+}
+
+// DeleteWatchlist removes a watchlist and its items
+func (s *MemoryStore) DeleteWatchlist(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.watchlists.byID[id]; !ok {
+		return fmt.Errorf("watchlist %s: %w", id, ErrNotFound)
+	}
+	delete(s.watchlists.byID, id)
+	return nil
+}
+
+// AddWatchlistItem adds a ticker to a watchlist
+func (s *MemoryStore) AddWatchlistItem(ctx context.Context, watchlistID string, item models.WatchlistItem) (models.WatchlistItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, ok := s.watchlists.byID[watchlistID]
+	if !ok {
+		return models.WatchlistItem{}, fmt.Errorf("watchlist %s: %w", watchlistID, ErrNotFound)
+	}
+	i := sort.Search(len(list.Items), func(i int) bool {
+		return list.Items[i].Ticker >= item.Ticker
+	})
+	if i < len(list.Items) && list.Items[i].Ticker == item.Ticker {
+		return models.WatchlistItem{}, fmt.Errorf("watchlist %s item %s: %w", watchlistID, item.Ticker, ErrDuplicate)
+	}
+
+	item.AddedAt = time.Now()
+	items := make([]models.WatchlistItem, 0, len(list.Items)+1)
+	items = append(items, list.Items[:i]...)
+	items = append(items, item)
+	list.Items = append(items, list.Items[i:]...)
+	s.watchlists.byID[watchlistID] = list
+	return item, nil
+}
+
+// UpdateWatchlistItem replaces the notes on a watchlist item
+func (s *MemoryStore) UpdateWatchlistItem(ctx context.Context, watchlistID string, item models.WatchlistItem) (models.WatchlistItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, i, err := s.findWatchlistItem(watchlistID, item.Ticker)
+	if err != nil {
+		return models.WatchlistItem{}, err
+	}
+	items := append([]models.WatchlistItem(nil), list.Items...)
+	items[i].Notes = item.Notes
+	list.Items = items
+	s.watchlists.byID[watchlistID] = list
+	return items[i], nil
+}
+
+// RemoveWatchlistItem removes a ticker from a watchlist
+func (s *MemoryStore) RemoveWatchlistItem(ctx context.Context, watchlistID, ticker string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, i, err := s.findWatchlistItem(watchlistID, ticker)
+	if err != nil {
+		return err
+	}
+	items := make([]models.WatchlistItem, 0, len(list.Items)-1)
+	items = append(items, list.Items[:i]...)
+	list.Items = append(items, list.Items[i+1:]...)
+	s.watchlists.byID[watchlistID] = list
+	return nil
+}
+
+func (s *MemoryStore) findWatchlistItem(watchlistID, ticker string) (models.Watchlist, int, error) {
+	list, ok := s.watchlists.byID[watchlistID]
+	if !ok {
+		return models.Watchlist{}, 0, fmt.Errorf("watchlist %s: %w", watchlistID, ErrNotFound)
+	}
+	for i, item := range list.Items {
+		if item.Ticker == ticker {
+			return list, i, nil
+		}
+	}
+	return models.Watchlist{}, 0, fmt.Errorf("watchlist %s item %s: %w", watchlistID, ticker, ErrNotFound)
+}
+
+// GetWatchlistQuotes returns a watchlist's items with their latest bar and
+// strength score
+func (s *MemoryStore) GetWatchlistQuotes(ctx context.Context, id string) ([]models.WatchlistQuote, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list, ok := s.watchlists.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("watchlist %s: %w", id, ErrNotFound)
+	}
+
+	quotes := make([]models.WatchlistQuote, len(list.Items))
+	index := make(map[string]int, len(list.Items))
+	for i, item := range list.Items {
+		quotes[i].WatchlistItem = item
+		index[item.Ticker] = i
+		if bars := s.dailyBars[item.Ticker]; len(bars) > 0 {
+			bar := bars[len(bars)-1]
+			quotes[i].Bar = &bar
+		}
+	}
+	for _, ranked := range s.strength {
+		for _, score := range ranked {
+			i, ok := index[score.Ticker]
+			if !ok {
+				continue
+			}
+			if latest := quotes[i].Strength; latest == nil || score.Date.After(latest.Date) {
+				quotes[i].Strength = &score
+			}
+		}
+	}
+	return quotes, nil
+}
+
+// isForeignKeyViolation reports whether err is a PostgreSQL foreign key
+// constraint error
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+const (
+	watchlistColumns     = `id::text, name, COALESCE(description, ''), created_at, updated_at`
+	watchlistItemColumns = `ticker, COALESCE(notes, ''), added_at`
+)
+
+func scanWatchlist(row pgx.Row, list *models.Watchlist) error {
+	return row.Scan(&list.ID, &list.Name, &list.Description, &list.CreatedAt, &list.UpdatedAt)
+}
+
+func scanWatchlistItem(row pgx.Row, item *models.WatchlistItem) error {
+	return row.Scan(&item.Ticker, &item.Notes, &item.AddedAt)
+}
+
+// ListWatchlists returns watchlists and their items ordered by name
+func (s *PostgresStore) ListWatchlists(ctx context.Context) ([]models.Watchlist, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `SELECT `+watchlistColumns+` FROM watchlists ORDER BY name, created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("querying watchlists: %w", err)
+	}
+	defer rows.Close()
+
+	lists := make([]models.Watchlist, 0)
+	byID := make(map[string]int)
+	for rows.Next() {
+		list := models.Watchlist{Items: []models.WatchlistItem{}}
+		if err := scanWatchlist(rows, &list); err != nil {
+			return nil, fmt.Errorf("scanning watchlist: %w", err)
+		}
+		byID[list.ID] = len(lists)
+		lists = append(lists, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading watchlists: %w", err)
+	}
+
+	rows, err = s.pool.Query(ctx, `
+		SELECT watchlist_id::text, `+watchlistItemColumns+`
+		FROM watchlist_items
+		ORDER BY watchlist_id, ticker
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying watchlist items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var item models.WatchlistItem
+		if err := rows.Scan(&id, &item.Ticker, &item.Notes, &item.AddedAt); err != nil {
+			return nil, fmt.Errorf("scanning watchlist item: %w", err)
+		}
+		// Items of a watchlist created since the first query are skipped
+		if i, ok := byID[id]; ok {
+			lists[i].Items = append(lists[i].Items, item)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading watchlist items: %w", err)
+	}
+	return lists, nil
+}
+
+// GetWatchlist returns a watchlist and its items by ID
+func (s *PostgresStore) GetWatchlist(ctx context.Context, id string) (models.Watchlist, error) {
+	if !uuidRe.MatchString(id) {
+		return models.Watchlist{}, fmt.Errorf("watchlist %s: %w", id, ErrNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var list models.Watchlist
+	err := scanWatchlist(s.pool.QueryRow(ctx, `SELECT `+watchlistColumns+` FROM watchlists WHERE id = $1::text::uuid`, id), &list)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return models.Watchlist{}, fmt.Errorf("watchlist %s: %w", id, ErrNotFound)
+	case err != nil:
+		return models.Watchlist{}, fmt.Errorf("querying watchlist: %w", err)
+	}
+
+	if list.Items, err = s.watchlistItems(ctx, id); err != nil {
+		return models.Watchlist{}, err
+	}
+	return list, nil
+}
+
+// watchlistItems returns a watchlist's items ordered by ticker
+func (s *PostgresStore) watchlistItems(ctx context.Context, id string) ([]models.WatchlistItem, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+watchlistItemColumns+`
+		FROM watchlist_items
+		WHERE watchlist_id = $1::text::uuid
+		ORDER BY ticker
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("querying watchlist items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]models.WatchlistItem, 0)
+	for rows.Next() {
+		var item models.WatchlistItem
+		if err := scanWatchlistItem(rows, &item); err != nil {
+			return nil, fmt.Errorf("scanning watchlist item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading watchlist items: %w", err)
+	}
+	return items, nil
+}
+
+// CreateWatchlist stores a new, empty watchlist with a generated ID
+func (s *PostgresStore) CreateWatchlist(ctx context.Context, list models.Watchlist) (models.Watchlist, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	created := models.Watchlist{Items: []models.WatchlistItem{}}
+	err := scanWatchlist(s.pool.QueryRow(ctx, `
+		INSERT INTO watchlists (name, description)
+		VALUES ($1, NULLIF($2, ''))
+		RETURNING `+watchlistColumns,
+		list.Name, list.Description), &created)
+	if err != nil {
+		return models.Watchlist{}, fmt.Errorf("inserting watchlist: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateWatchlist renames a watchlist and replaces its description
+func (s *PostgresStore) UpdateWatchlist(ctx context.Context, list models.Watchlist) (models.Watchlist, error) {
+	if !uuidRe.MatchString(list.ID) {
+		return models.Watchlist{}, fmt.Errorf("watchlist %s: %w", list.ID, ErrNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var updated models.Watchlist
+	err := scanWatchlist(s.pool.QueryRow(ctx, `
+		UPDATE watchlists SET name = $2, description = NULLIF($3, ''), updated_at = NOW()
+		WHERE id = $1::text::uuid
+		RETURNING `+watchlistColumns,
+		list.ID, list.Name, list.Description), &updated)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return models.Watchlist{}, fmt.Errorf("watchlist %s: %w", list.ID, ErrNotFound)
+	case err != nil:
+		return models.Watchlist{}, fmt.Errorf("updating watchlist: %w", err)
+	}
+
+	if updated.Items, err = s.watchlistItems(ctx, list.ID); err != nil {
+		return models.Watchlist{}, err
+	}
+	return updated, nil
+}
+
+// DeleteWatchlist removes a watchlist; its items cascade
+func (s *PostgresStore) DeleteWatchlist(ctx context.Context, id string) error {
+	if !uuidRe.MatchString(id) {
+		return fmt.Errorf("watchlist %s: %w", id, ErrNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `DELETE FROM watchlists WHERE id = $1::text::uuid`, id)
+	if err != nil {
+		return fmt.Errorf("deleting watchlist: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("watchlist %s: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// AddWatchlistItem adds a ticker to a watchlist
+func (s *PostgresStore) AddWatchlistItem(ctx context.Context, watchlistID string, item models.WatchlistItem) (models.WatchlistItem, error) {
+	if !uuidRe.MatchString(watchlistID) {
+		return models.WatchlistItem{}, fmt.Errorf("watchlist %s: %w", watchlistID, ErrNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var added models.WatchlistItem
+	err := scanWatchlistItem(s.pool.QueryRow(ctx, `
+		INSERT INTO watchlist_items (watchlist_id, ticker, notes)
+		VALUES ($1::text::uuid, $2, NULLIF($3, ''))
+		RETURNING `+watchlistItemColumns,
+		watchlistID, item.Ticker, item.Notes), &added)
+	switch {
+	case isForeignKeyViolation(err):
+		return models.WatchlistItem{}, fmt.Errorf("watchlist %s: %w", watchlistID, ErrNotFound)
+	case isUniqueViolation(err):
+		return models.WatchlistItem{}, fmt.Errorf("watchlist %s item %s: %w", watchlistID, item.Ticker, ErrDuplicate)
+	case err != nil:
+		return models.WatchlistItem{}, fmt.Errorf("inserting watchlist item: %w", err)
+	}
+	return added, nil
+}
+
+// UpdateWatchlistItem replaces the notes on a watchlist item
+func (s *PostgresStore) UpdateWatchlistItem(ctx context.Context, watchlistID string, item models.WatchlistItem) (models.WatchlistItem, error) {
+	if !uuidRe.MatchString(watchlistID) {
+		return models.WatchlistItem{}, fmt.Errorf("watchlist %s: %w", watchlistID, ErrNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var updated models.WatchlistItem
+	err := scanWatchlistItem(s.pool.QueryRow(ctx, `
+		UPDATE watchlist_items SET notes = NULLIF($3, '')
+		WHERE watchlist_id = $1::text::uuid AND ticker = $2
+		RETURNING `+watchlistItemColumns,
+		watchlistID, item.Ticker, item.Notes), &updated)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return models.WatchlistItem{}, fmt.Errorf("watchlist %s item %s: %w", watchlistID, item.Ticker, ErrNotFound)
+	case err != nil:
+		return models.WatchlistItem{}, fmt.Errorf("updating watchlist item: %w", err)
+	}
+	return updated, nil
+}
+
+// RemoveWatchlistItem removes a ticker from a watchlist
+func (s *PostgresStore) RemoveWatchlistItem(ctx context.Context, watchlistID, ticker string) error {
+	if !uuidRe.MatchString(watchlistID) {
+		return fmt.Errorf("watchlist %s: %w", watchlistID, ErrNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM watchlist_items WHERE watchlist_id = $1::text::uuid AND ticker = $2
+	`, watchlistID, ticker)
+	if err != nil {
+		return fmt.Errorf("deleting watchlist item: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("watchlist %s item %s: %w", watchlistID, ticker, ErrNotFound)
+	}
+	return nil
+}
+
+// GetWatchlistQuotes returns a watchlist's items with their latest bar and
+// strength score. Each item's latest rows are read through
+// idx_daily_bars_symbol and idx_strength_scores_ticker.
+func (s *PostgresStore) GetWatchlistQuotes(ctx context.Context, id string) ([]models.WatchlistQuote, error) {
+	list, err := s.GetWatchlist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	quotes := make([]models.WatchlistQuote, len(list.Items))
+	tickers := make([]string, len(list.Items))
+	index := make(map[string]int, len(list.Items))
+	for i, item := range list.Items {
+		quotes[i].WatchlistItem = item
+		tickers[i] = item.Ticker
+		index[item.Ticker] = i
+	}
+	if len(tickers) == 0 {
+		return quotes, nil
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT b.symbol, b.date, b.open, b.high, b.low, b.close, b.volume,
+			COALESCE(b.vwap, 0), COALESCE(b.prev_close, 0), COALESCE(b.change, 0), COALESCE(b.change_percent, 0), COALESCE(b.change_basis, ''),
+			COALESCE(b.avg_volume_20, 0), COALESCE(b.avg_volume_50, 0), COALESCE(b.volume_ratio, 0)
+		FROM unnest($1::text[]) AS t(ticker)
+		CROSS JOIN LATERAL (
+			SELECT * FROM daily_bars WHERE symbol = t.ticker ORDER BY date DESC LIMIT 1
+		) b
+	`, tickers)
+	if err != nil {
+		return nil, fmt.Errorf("querying watchlist bars: %w", err)
+	}
+	bars, err := collectDailyBars(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, bar := range bars {
+		quotes[index[bar.Symbol]].Bar = &bar
+	}
+
+	rows, err = s.pool.Query(ctx, `
+		SELECT `+strengthColumns+`
+		FROM unnest($1::text[]) AS t(symbol)
+		CROSS JOIN LATERAL (
+			SELECT * FROM strength_scores WHERE ticker = t.symbol ORDER BY date DESC LIMIT 1
+		) s
+	`, tickers)
+	if err != nil {
+		return nil, fmt.Errorf("querying watchlist strength scores: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sc models.StrengthScore
+		if err := scanStrengthScore(rows, &sc); err != nil {
+			return nil, fmt.Errorf("scanning strength score: %w", err)
+		}
+		quotes[index[sc.Ticker]].Strength = &sc
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading strength scores: %w", err)
+	}
+	return quotes, nil
+}
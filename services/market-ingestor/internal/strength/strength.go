@@ -0,0 +1,233 @@
+// Package strength scores and ranks tickers by a composite of momentum,
+// relative strength, volume and trend measures.
+package strength
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+)
+
+const (
+	// Benchmark is the ticker relative strength is measured against
+	Benchmark = "SPY"
+	// Sessions is how much history a score looks at: the 52-week range
+	Sessions = 252
+	// Lookback is a calendar span covering Sessions trading days
+	Lookback = 380 * 24 * time.Hour
+	// MinSessions is the history a ticker needs to be scored, enough for
+	// 20-session momentum
+	MinSessions = 21
+
+	// componentLimit keeps percentages within the store's NUMERIC(8, 4)
+	componentLimit = 9999
+)
+
+// weights are each component's share of the composite. Every component is
+// converted to its percentile across the day's universe first, so the
+// composite is itself 0-100.
+var weights = []struct {
+	weight float64
+	value  func(c models.StrengthComponents) float64
+}{
+	{0.10, func(c models.StrengthComponents) float64 { return c.Momentum1D }},
+	{0.15, func(c models.StrengthComponents) float64 { return c.Momentum5D }},
+	{0.20, func(c models.StrengthComponents) float64 { return c.Momentum20D }},
+	{0.20, func(c models.StrengthComponents) float64 { return c.RSVsSPY }},
+	{0.10, func(c models.StrengthComponents) float64 { return c.VolumeTrend }},
+	{0.15, func(c models.StrengthComponents) float64 { return c.MAAlignment }},
+	{0.10, func(c models.StrengthComponents) float64 { return c.HighLowPosition }},
+}
+
+// Ranker scores one day's universe a ticker at a time, so callers can
+// stream history rather than hold it all
+type Ranker struct {
+	date        time.Time
+	spyMomentum float64
+	scores      []models.StrengthScore
+}
+
+// NewRanker ranks tickers as of date. spy is the benchmark's
+// split-adjusted history ending on date; without it, relative strength is
+// zero for every ticker.
+func NewRanker(date time.Time, spy []models.DailyBar) *Ranker {
+	r := &Ranker{date: date}
+	if len(spy) >= MinSessions && sameDay(spy[len(spy)-1].Date, date) {
+		r.spyMomentum = change(spy, 20)
+	}
+	return r
+}
+
+// Add scores a ticker from its split-adjusted bars ordered by date. Tickers
+// without a bar on the ranker's date or with less than MinSessions of
+// history are skipped; Add reports whether the ticker was scored.
+func (r *Ranker) Add(ticker string, bars []models.DailyBar) bool {
+	if len(bars) < MinSessions || !sameDay(bars[len(bars)-1].Date, r.date) {
+		return false
+	}
+	if len(bars) > Sessions {
+		bars = bars[len(bars)-Sessions:]
+	}
+
+	c := models.StrengthComponents{
+		Momentum1D:      change(bars, 1),
+		Momentum5D:      change(bars, 5),
+		Momentum20D:     change(bars, 20),
+		VolumeTrend:     volumeTrend(bars),
+		MAAlignment:     maAlignment(bars),
+		HighLowPosition: highLowPosition(bars),
+	}
+	c.RSVsSPY = clamp(c.Momentum20D - r.spyMomentum)
+
+	r.scores = append(r.scores, models.StrengthScore{
+		Ticker:     ticker,
+		Date:       r.date,
+		Components: c,
+	})
+	return true
+}
+
+// Scores returns every added ticker with its composite and rank, strongest
+// first
+func (r *Ranker) Scores() []models.StrengthScore {
+	scores := r.scores
+	for _, w := range weights {
+		pct := percentiles(scores, w.value)
+		for i := range scores {
+			scores[i].Composite += w.weight * pct[i]
+		}
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Composite != scores[j].Composite {
+			return scores[i].Composite > scores[j].Composite
+		}
+		return scores[i].Ticker < scores[j].Ticker
+	})
+	for i := range scores {
+		scores[i].Composite = math.Round(scores[i].Composite*100) / 100
+		scores[i].Rank = i + 1
+		scores[i].TotalRanked = len(scores)
+	}
+	return scores
+}
+
+// percentiles returns each score's percentile (0-100) for value, ties
+// sharing their average position
+func percentiles(scores []models.StrengthScore, value func(models.StrengthComponents) float64) []float64 {
+	n := len(scores)
+	pct := make([]float64, n)
+	if n == 1 {
+		pct[0] = 50
+		return pct
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return value(scores[order[a]].Components) < value(scores[order[b]].Components)
+	})
+
+	for i := 0; i < n; {
+		j := i
+		v := value(scores[order[i]].Components)
+		for j+1 < n && value(scores[order[j+1]].Components) == v {
+			j++
+		}
+		p := float64(i+j) / 2 / float64(n-1) * 100
+		for k := i; k <= j; k++ {
+			pct[order[k]] = p
+		}
+		i = j + 1
+	}
+	return pct
+}
+
+// change is the percent change of the last close from the close n
+// sessions earlier
+func change(bars []models.DailyBar, n int) float64 {
+	last := len(bars) - 1
+	if last < n || bars[last-n].Close <= 0 {
+		return 0
+	}
+	return clamp((bars[last].Close/bars[last-n].Close - 1) * 100)
+}
+
+// volumeTrend is average volume over the last 5 sessions relative to the
+// last 20
+func volumeTrend(bars []models.DailyBar) float64 {
+	avg5, avg20 := avgVolume(bars, 5), avgVolume(bars, 20)
+	if avg20 == 0 {
+		return 0
+	}
+	return clamp(avg5 / avg20)
+}
+
+func avgVolume(bars []models.DailyBar, n int) float64 {
+	n = min(n, len(bars))
+	var sum float64
+	for _, bar := range bars[len(bars)-n:] {
+		sum += float64(bar.Volume)
+	}
+	return sum / float64(n)
+}
+
+// maAlignment scores the orderings close > SMA20, SMA20 > SMA50 and
+// SMA50 > SMA200 that have enough history to judge
+func maAlignment(bars []models.DailyBar) float64 {
+	levels := []float64{bars[len(bars)-1].Close}
+	for _, period := range []int{20, 50, 200} {
+		if len(bars) < period {
+			break
+		}
+		levels = append(levels, sma(bars, period))
+	}
+	if len(levels) < 2 {
+		return 50
+	}
+
+	held := 0
+	for i := 1; i < len(levels); i++ {
+		if levels[i-1] > levels[i] {
+			held++
+		}
+	}
+	return float64(held) / float64(len(levels)-1) * 100
+}
+
+func sma(bars []models.DailyBar, period int) float64 {
+	var sum float64
+	for _, bar := range bars[len(bars)-period:] {
+		sum += bar.Close
+	}
+	return sum / float64(period)
+}
+
+// highLowPosition places the last close within the range of the bars' highs
+// and lows
+func highLowPosition(bars []models.DailyBar) float64 {
+	high, low := bars[0].High, bars[0].Low
+	for _, bar := range bars[1:] {
+		high = max(high, bar.High)
+		low = min(low, bar.Low)
+	}
+	if high <= low {
+		return 50
+	}
+	pos := (bars[len(bars)-1].Close - low) / (high - low) * 100
+	return max(0, min(100, pos))
+}
+
+func clamp(v float64) float64 {
+	return max(-componentLimit, min(componentLimit, v))
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
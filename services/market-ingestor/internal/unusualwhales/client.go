@@ -0,0 +1,277 @@
+// Package unusualwhales is a client for the Unusual Whales API: options
+// flow alerts, dark pool prints and market tide.
+package unusualwhales
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/ratelimit"
+)
+
+const baseURL = "https://api.unusualwhales.com"
+
+const (
+	// maxBodyPreview caps how much of an error body is kept in errors
+	maxBodyPreview = 500
+	// maxBackoff caps the delay between retries
+	maxBackoff = time.Minute
+)
+
+// Options configures the client's endpoint, rate limiting and retries
+type Options struct {
+	// BaseURL replaces the API address, e.g. with a uwtest stand-in
+	BaseURL string
+	// RequestsPerMinute limits outgoing requests; zero disables limiting
+	RequestsPerMinute int
+	// Burst is the number of requests allowed back to back (default 1)
+	Burst int
+	// MaxRetries is how many times a 429, 5xx or transport error is retried
+	MaxRetries int
+	// BaseBackoff is the initial retry delay, doubled each attempt (default 1s)
+	BaseBackoff time.Duration
+}
+
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	limiter    *ratelimit.TokenBucket
+	opts       Options
+}
+
+func NewClient(apiKey string, opts Options) *Client {
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = time.Second
+	}
+	base := strings.TrimSuffix(opts.BaseURL, "/")
+	if base == "" {
+		base = baseURL
+	}
+
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    base,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		limiter:    ratelimit.New(opts.RequestsPerMinute, opts.Burst),
+		opts:       opts,
+	}
+}
+
+// Number is a decimal the API sends either as a JSON number or as a string
+type Number float64
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("parsing number %s: %w", data, err)
+	}
+	*n = Number(f)
+	return nil
+}
+
+// FlowAlert is an options order in one contract that tripped an alert rule,
+// made of one or more trades
+type FlowAlert struct {
+	ID        string    `json:"id"`
+	Ticker    string    `json:"ticker"`
+	CreatedAt time.Time `json:"created_at"`
+	// Expiry is the contract's expiration date, YYYY-MM-DD
+	Expiry string `json:"expiry"`
+	Strike Number `json:"strike"`
+	// Type is "call" or "put"
+	Type             string `json:"type"`
+	TotalPremium     Number `json:"total_premium"`
+	TotalAskSidePrem Number `json:"total_ask_side_prem"`
+	TotalBidSidePrem Number `json:"total_bid_side_prem"`
+	TotalSize        int64  `json:"total_size"`
+	TradeCount       int    `json:"trade_count"`
+	// Volume and OpenInterest are the contract's day volume and prior
+	// open interest
+	Volume          int64  `json:"volume"`
+	OpenInterest    int64  `json:"open_interest"`
+	HasSweep        bool   `json:"has_sweep"`
+	HasFloor        bool   `json:"has_floor"`
+	HasMultileg     bool   `json:"has_multileg"`
+	UnderlyingPrice Number `json:"underlying_price"`
+	AlertRule       string `json:"alert_rule"`
+
+	// Raw is the alert as received
+	Raw json.RawMessage `json:"-"`
+}
+
+func (a *FlowAlert) UnmarshalJSON(data []byte) error {
+	type plain FlowAlert
+	if err := json.Unmarshal(data, (*plain)(a)); err != nil {
+		return err
+	}
+	a.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// DarkPoolTrade is an off-exchange equity print
+type DarkPoolTrade struct {
+	Ticker     string    `json:"ticker"`
+	ExecutedAt time.Time `json:"executed_at"`
+	Price      Number    `json:"price"`
+	Size       int64     `json:"size"`
+	Premium    Number    `json:"premium"`
+	// Volume is the ticker's consolidated volume for the day so far
+	Volume       int64  `json:"volume"`
+	NBBOBid      Number `json:"nbbo_bid"`
+	NBBOAsk      Number `json:"nbbo_ask"`
+	MarketCenter string `json:"market_center"`
+}
+
+// MarketTide is one interval of market-wide options sentiment: net call
+// premium rising against net put premium is bullish
+type MarketTide struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Date           string    `json:"date"`
+	NetCallPremium Number    `json:"net_call_premium"`
+	NetPutPremium  Number    `json:"net_put_premium"`
+	NetVolume      int64     `json:"net_volume"`
+}
+
+// dataResponse is the envelope every endpoint wraps its results in
+type dataResponse[T any] struct {
+	Data []T `json:"data"`
+}
+
+// GetFlowAlerts returns up to limit of the latest flow alerts, newest first
+func (c *Client) GetFlowAlerts(ctx context.Context, limit int) ([]FlowAlert, error) {
+	return c.flowAlerts(ctx, url.Values{"limit": {strconv.Itoa(limit)}})
+}
+
+// GetTickerFlow returns up to limit of the latest flow alerts on ticker's
+// options, newest first
+func (c *Client) GetTickerFlow(ctx context.Context, ticker string, limit int) ([]FlowAlert, error) {
+	return c.flowAlerts(ctx, url.Values{
+		"ticker_symbol": {strings.ToUpper(ticker)},
+		"limit":         {strconv.Itoa(limit)},
+	})
+}
+
+func (c *Client) flowAlerts(ctx context.Context, query url.Values) ([]FlowAlert, error) {
+	var result dataResponse[FlowAlert]
+	if err := c.get(ctx, "/api/option-trades/flow-alerts", query, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetDarkPool returns up to limit of the latest dark pool prints, newest
+// first
+func (c *Client) GetDarkPool(ctx context.Context, limit int) ([]DarkPoolTrade, error) {
+	var result dataResponse[DarkPoolTrade]
+	if err := c.get(ctx, "/api/darkpool/recent", url.Values{"limit": {strconv.Itoa(limit)}}, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetMarketTide returns the current session's market tide, oldest interval
+// first
+func (c *Client) GetMarketTide(ctx context.Context) ([]MarketTide, error) {
+	var result dataResponse[MarketTide]
+	if err := c.get(ctx, "/api/market/market-tide", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// get performs a rate-limited GET against path, retrying throttled and
+// transient failures, and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	reqURL := c.baseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		body, retryAfter, err := c.do(ctx, reqURL)
+		if err == nil {
+			if err := json.Unmarshal(body, out); err != nil {
+				return fmt.Errorf("decoding response: %w (preview: %s)", err, preview(body))
+			}
+			return nil
+		}
+		lastErr = err
+
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && !retryable(statusErr.StatusCode) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt >= c.opts.MaxRetries {
+			return lastErr
+		}
+
+		delay := max(ratelimit.Backoff(c.opts.BaseBackoff, maxBackoff, attempt), retryAfter)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// do executes a single request and returns the body of a 200 response, or
+// an error along with any Retry-After delay the server asked for
+func (c *Client) do(ctx context.Context, reqURL string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After")), &StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       preview(body),
+		}
+	}
+
+	return body, 0, nil
+}
+
+func preview(body []byte) string {
+	if len(body) > maxBodyPreview {
+		return string(body[:maxBodyPreview])
+	}
+	return string(body)
+}
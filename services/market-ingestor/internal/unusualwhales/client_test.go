@@ -0,0 +1,162 @@
+package unusualwhales_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/unusualwhales"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/unusualwhales/uwtest"
+)
+
+const testKey = "test-key"
+
+var at = time.Date(2025, 3, 14, 15, 0, 0, 0, time.UTC)
+
+func newTestClient(url, apiKey string, maxRetries int) *unusualwhales.Client {
+	return unusualwhales.NewClient(apiKey, unusualwhales.Options{
+		BaseURL:     url,
+		MaxRetries:  maxRetries,
+		BaseBackoff: time.Millisecond,
+	})
+}
+
+func TestGetFlowAlerts(t *testing.T) {
+	server := uwtest.NewServer(testKey)
+	defer server.Close()
+	server.AddFlowAlerts(
+		unusualwhales.FlowAlert{ID: "old", Ticker: "AAPL", CreatedAt: at, Type: "call"},
+		unusualwhales.FlowAlert{ID: "new", Ticker: "MSFT", CreatedAt: at.Add(time.Minute), Type: "put"},
+		unusualwhales.FlowAlert{ID: "newest", Ticker: "AAPL", CreatedAt: at.Add(2 * time.Minute), Type: "call"},
+	)
+	client := newTestClient(server.URL(), testKey, 0)
+	ctx := context.Background()
+
+	alerts, err := client.GetFlowAlerts(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetFlowAlerts: %v", err)
+	}
+	if len(alerts) != 2 || alerts[0].ID != "newest" || alerts[1].ID != "new" {
+		t.Errorf("GetFlowAlerts(2) = %+v, want newest and new", alerts)
+	}
+	if len(alerts) > 0 && len(alerts[0].Raw) == 0 {
+		t.Error("alert has no raw JSON")
+	}
+
+	alerts, err = client.GetTickerFlow(ctx, "aapl", 10)
+	if err != nil {
+		t.Fatalf("GetTickerFlow: %v", err)
+	}
+	if len(alerts) != 2 || alerts[0].ID != "newest" || alerts[1].ID != "old" {
+		t.Errorf("GetTickerFlow(aapl) = %+v, want newest and old", alerts)
+	}
+}
+
+func TestGetDarkPoolAndMarketTide(t *testing.T) {
+	server := uwtest.NewServer(testKey)
+	defer server.Close()
+	server.AddDarkPool(unusualwhales.DarkPoolTrade{Ticker: "SPY", ExecutedAt: at, Price: 580.5, Size: 100_000})
+	server.SetMarketTide([]unusualwhales.MarketTide{
+		{Timestamp: at, Date: "2025-03-14", NetCallPremium: 1_000_000, NetPutPremium: -200_000},
+	})
+	client := newTestClient(server.URL(), testKey, 0)
+	ctx := context.Background()
+
+	trades, err := client.GetDarkPool(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetDarkPool: %v", err)
+	}
+	if len(trades) != 1 || trades[0].Price != 580.5 || trades[0].Size != 100_000 {
+		t.Errorf("GetDarkPool() = %+v", trades)
+	}
+
+	tide, err := client.GetMarketTide(ctx)
+	if err != nil {
+		t.Fatalf("GetMarketTide: %v", err)
+	}
+	if len(tide) != 1 || tide[0].NetCallPremium != 1_000_000 || tide[0].NetPutPremium != -200_000 {
+		t.Errorf("GetMarketTide() = %+v", tide)
+	}
+}
+
+func TestClientRejectedKey(t *testing.T) {
+	server := uwtest.NewServer(testKey)
+	defer server.Close()
+
+	client := newTestClient(server.URL(), "wrong-key", 3)
+	if _, err := client.GetFlowAlerts(context.Background(), 10); !errors.Is(err, unusualwhales.ErrUnauthorized) {
+		t.Errorf("GetFlowAlerts returned %v, want ErrUnauthorized", err)
+	}
+	if n := server.Requests(); n != 0 {
+		t.Errorf("server counted %d authorized requests, want 0", n)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	server := uwtest.NewServer(testKey)
+	defer server.Close()
+	ctx := context.Background()
+
+	// A server error is retried
+	server.Fail(http.StatusInternalServerError)
+	client := newTestClient(server.URL(), testKey, 1)
+	if _, err := client.GetMarketTide(ctx); err != nil {
+		t.Fatalf("GetMarketTide after one 500: %v", err)
+	}
+	if n := server.Requests(); n != 2 {
+		t.Errorf("server received %d requests, want 2", n)
+	}
+
+	// Throttling that outlasts the retries is reported as such
+	server.Fail(http.StatusTooManyRequests, http.StatusTooManyRequests)
+	start := time.Now()
+	if _, err := client.GetMarketTide(ctx); !errors.Is(err, unusualwhales.ErrRateLimited) {
+		t.Errorf("GetMarketTide returned %v, want ErrRateLimited", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want the server's 1s Retry-After honored", elapsed)
+	}
+
+	// Other client errors are not retried
+	before := server.Requests()
+	server.Fail(http.StatusBadRequest, http.StatusBadRequest)
+	var statusErr *unusualwhales.StatusError
+	if _, err := client.GetMarketTide(ctx); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("GetMarketTide returned %v, want a 400 StatusError", err)
+	}
+	if n := server.Requests() - before; n != 1 {
+		t.Errorf("400 was sent %d times, want 1", n)
+	}
+}
+
+func TestClientDecodesStringNumbers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[{"id":"x","ticker":"AAPL","created_at":"2025-03-14T15:00:00Z",` +
+			`"strike":"105.5","total_premium":"123456.78","total_ask_side_prem":null,"total_bid_side_prem":""}]}`))
+	}))
+	defer server.Close()
+
+	alerts, err := newTestClient(server.URL, testKey, 0).GetFlowAlerts(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetFlowAlerts: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("got %d alerts, want 1", len(alerts))
+	}
+	a := alerts[0]
+	if a.Strike != 105.5 || a.TotalPremium != 123456.78 || a.TotalAskSidePrem != 0 || a.TotalBidSidePrem != 0 {
+		t.Errorf("decoded %+v", a)
+	}
+}
+
+func TestNumberRejectsGarbage(t *testing.T) {
+	var n unusualwhales.Number
+	if err := json.Unmarshal([]byte(`"12abc"`), &n); err == nil {
+		t.Errorf("Unmarshal(12abc) = %v, want an error", float64(n))
+	}
+}
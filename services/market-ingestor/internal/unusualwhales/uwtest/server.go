@@ -0,0 +1,175 @@
+// Package uwtest provides a local stand-in for the Unusual Whales API, so
+// options flow ingestion can be exercised without an API key or network.
+package uwtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/unusualwhales"
+)
+
+// Server answers the flow alert, dark pool and market tide endpoints from
+// data added to it, checking the bearer token each request carries
+type Server struct {
+	server *httptest.Server
+	apiKey string
+
+	mu       sync.Mutex
+	alerts   []unusualwhales.FlowAlert
+	darkPool []unusualwhales.DarkPoolTrade
+	tide     []unusualwhales.MarketTide
+	requests int
+	// failures are status codes answered, in order, before serving again
+	failures []int
+}
+
+// NewServer starts a stand-in that accepts apiKey
+func NewServer(apiKey string) *Server {
+	s := &Server{apiKey: apiKey}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/option-trades/flow-alerts", s.flowAlerts)
+	mux.HandleFunc("GET /api/darkpool/recent", s.darkPoolRecent)
+	mux.HandleFunc("GET /api/market/market-tide", s.marketTide)
+	s.server = httptest.NewServer(s.authorize(mux))
+	return s
+}
+
+// URL is the address to pass as Options.BaseURL
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// AddFlowAlerts makes alerts available to flow alert requests, which
+// return them newest first
+func (s *Server) AddFlowAlerts(alerts ...unusualwhales.FlowAlert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alerts = append(s.alerts, alerts...)
+	sort.SliceStable(s.alerts, func(i, j int) bool {
+		return s.alerts[i].CreatedAt.After(s.alerts[j].CreatedAt)
+	})
+}
+
+// AddDarkPool makes trades available to dark pool requests, which return
+// them newest first
+func (s *Server) AddDarkPool(trades ...unusualwhales.DarkPoolTrade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.darkPool = append(s.darkPool, trades...)
+	sort.SliceStable(s.darkPool, func(i, j int) bool {
+		return s.darkPool[i].ExecutedAt.After(s.darkPool[j].ExecutedAt)
+	})
+}
+
+// SetMarketTide replaces the intervals market tide requests return
+func (s *Server) SetMarketTide(tide []unusualwhales.MarketTide) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tide = tide
+}
+
+// Fail answers the next requests with these status codes, one each, before
+// serving normally again. A 429 carries a one second Retry-After.
+func (s *Server) Fail(statusCodes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statusCodes...)
+}
+
+// Requests returns how many authorized requests have been received
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// authorize rejects requests without the bearer token and answers any
+// queued failures
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+s.apiKey {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "invalid api key"})
+			return
+		}
+
+		s.mu.Lock()
+		s.requests++
+		status := 0
+		if len(s.failures) > 0 {
+			status, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if status != 0 {
+			if status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "1")
+			}
+			writeJSON(w, status, map[string]string{"message": http.StatusText(status)})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) flowAlerts(w http.ResponseWriter, r *http.Request) {
+	ticker := strings.ToUpper(r.URL.Query().Get("ticker_symbol"))
+
+	s.mu.Lock()
+	alerts := make([]unusualwhales.FlowAlert, 0, len(s.alerts))
+	for _, alert := range s.alerts {
+		if ticker == "" || alert.Ticker == ticker {
+			alerts = append(alerts, alert)
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"data": truncate(alerts, r)})
+}
+
+func (s *Server) darkPoolRecent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	trades := append([]unusualwhales.DarkPoolTrade(nil), s.darkPool...)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"data": truncate(trades, r)})
+}
+
+func (s *Server) marketTide(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	tide := append([]unusualwhales.MarketTide{}, s.tide...)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"data": tide})
+}
+
+// truncate applies the request's ?limit= (50 by default) to items
+func truncate[T any](items []T, r *http.Request) []T {
+	limit := 50
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+		limit = n
+	}
+	if items == nil {
+		items = []T{}
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
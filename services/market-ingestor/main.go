@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -11,9 +13,18 @@ import (
 
 	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/api"
 	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/config"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/events"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/indicators"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/models"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/optionsflow"
 	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/polygon"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/provider/csvdir"
 	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/scheduler"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/screener"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/signals"
 	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/unusualwhales"
 	"github.com/joho/godotenv"
 )
 
@@ -30,34 +41,96 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Initialize Polygon client
-	polygonClient := polygon.NewClient(cfg.PolygonAPIKey)
-
-	// Initialize store (PostgreSQL if DATABASE_URL is set, otherwise memory)
-	var dataStore store.Store
-	var err error
-
-	if cfg.DatabaseURL != "" {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		dataStore, err = store.NewPostgresStore(ctx, cfg.DatabaseURL, logger)
-		cancel()
+	// Dispatch subcommands; with none, run the server
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "backfill":
+			err = runBackfill(cfg, logger, os.Args[2:])
+		case "migrate":
+			err = runMigrate(cfg, logger, os.Args[2:])
+		case "serve":
+			runServer(cfg, logger)
+		default:
+			err = fmt.Errorf("unknown command %q (expected serve, backfill or migrate)", os.Args[1])
+		}
 		if err != nil {
-			logger.Error("failed to connect to PostgreSQL, falling back to memory store", "error", err)
-			dataStore = store.NewMemoryStore()
+			logger.Error("command failed", "command", os.Args[1], "error", err)
+			os.Exit(1)
 		}
-	} else {
-		logger.Info("no DATABASE_URL set, using in-memory store")
-		dataStore = store.NewMemoryStore()
+		return
 	}
+
+	runServer(cfg, logger)
+}
+
+func runServer(cfg *config.Config, logger *slog.Logger) {
+	dataStore := openStore(cfg, logger)
 	defer dataStore.Close()
 
-	// Initialize scheduler for EOD data ingestion
-	sched := scheduler.New(polygonClient, dataStore, logger)
+	// Live events for the /stream endpoint
+	broadcaster := events.NewBroadcaster(events.DefaultBufferSize)
+
+	// Initialize scheduler for EOD data ingestion and optional streaming
+	stream, err := newStream(cfg, logger)
+	if err != nil {
+		logger.Error("failed to initialize stream", "error", err)
+		os.Exit(1)
+	}
+	sched, err := newScheduler(cfg, dataStore, broadcaster, stream, logger)
+	if err != nil {
+		logger.Error("failed to initialize data provider", "error", err)
+		os.Exit(1)
+	}
+
+	screenerDefaults := newScreenerDefaults(cfg)
+
+	// Keep cached indicator state current as each day is ingested
+	engine := indicators.NewEngine(dataStore, logger)
+	sched.OnIngest(func(ctx context.Context, ev scheduler.IngestEvent) {
+		engine.Update(ev.Date, ev.Bars)
+	})
+	// Indicators are seeded from split-adjusted history, so a saved split
+	// invalidates its symbol's cached state
+	sched.OnActions(func(ctx context.Context, actions []models.CorporateAction) {
+		var symbols []string
+		for _, a := range actions {
+			if a.Type == models.ActionSplit {
+				symbols = append(symbols, a.Symbol)
+			}
+		}
+		engine.Reset(symbols...)
+	})
+
+	// Evaluate saved screens after each EOD ingest; backfilled days are
+	// history and screens only run against the latest session
+	scr := screener.New(dataStore, engine, logger)
+	sched.OnIngest(func(ctx context.Context, ev scheduler.IngestEvent) {
+		if ev.Backfill {
+			return
+		}
+		alerts, err := scr.EvaluateSaved(ctx, screenerDefaults)
+		if err != nil {
+			logger.Error("failed to evaluate saved screens", "error", err)
+			return
+		}
+		for _, alert := range alerts {
+			sched.Publish(events.TypeAlert, alert)
+		}
+	})
+
 	sched.Start()
 	defer sched.Stop()
 
+	if cfg.AdminToken == "" {
+		logger.Warn("no ADMIN_TOKEN set, admin routes are disabled")
+	}
+
 	// Initialize HTTP server
-	router := api.NewRouter(dataStore, logger)
+	router := api.NewRouter(dataStore, sched, engine, scr, broadcaster, logger, api.Options{
+		AdminToken:       cfg.AdminToken,
+		ScreenerDefaults: screenerDefaults,
+	})
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
@@ -92,3 +165,147 @@ func main() {
 
 	logger.Info("server stopped")
 }
+
+// openStore returns PostgreSQL if DATABASE_URL is set, otherwise memory
+func openStore(cfg *config.Config, logger *slog.Logger) store.Store {
+	if cfg.DatabaseURL == "" {
+		logger.Info("no DATABASE_URL set, using in-memory store")
+		return newMemoryStore(cfg, logger)
+	}
+
+	// Migrations may wait on another instance holding the migration lock
+	timeout := 10 * time.Second
+	if cfg.AutoMigrate {
+		timeout = 2 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	dataStore, err := store.NewPostgresStore(ctx, cfg.DatabaseURL, logger, store.PostgresOptions{
+		AutoMigrate: cfg.AutoMigrate,
+	})
+	if err != nil {
+		logger.Error("failed to connect to PostgreSQL, falling back to memory store", "error", err)
+		return newMemoryStore(cfg, logger)
+	}
+	return dataStore
+}
+
+// newMemoryStore returns a memory store persisted to MEMORY_SNAPSHOT_PATH
+func newMemoryStore(cfg *config.Config, logger *slog.Logger) *store.MemoryStore {
+	return store.NewMemoryStore(store.MemoryOptions{
+		SnapshotPath:     cfg.MemorySnapshotPath,
+		SnapshotInterval: cfg.MemorySnapshotInterval,
+		Logger:           logger,
+	})
+}
+
+func newScheduler(cfg *config.Config, dataStore store.Store, publisher events.Publisher, stream provider.Streamer, logger *slog.Logger) (*scheduler.Scheduler, error) {
+	data, err := newProvider(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduler.New(data, dataStore, logger, scheduler.Options{
+		ChangeFallback:      models.ChangeBasis(cfg.ChangeFallback),
+		PrevCloseMaxLookups: cfg.PrevCloseMaxLookups,
+		BackfillConcurrency: cfg.BackfillConcurrency,
+		Events:              publisher,
+		Stream:              stream,
+		StreamSymbols:       cfg.StreamSymbols,
+		IntradaySymbols:     cfg.IntradaySymbols,
+		SectorLookups:       cfg.SectorLookups,
+		StrengthUniverse:    newStrengthUniverse(cfg),
+		SignalUniverse:      newScreenerDefaults(cfg),
+		OptionsFlow:         newOptionsFlow(cfg, dataStore, publisher, logger),
+		OptionsFlowInterval: cfg.OptionsFlowInterval,
+	}), nil
+}
+
+// newScreenerDefaults returns the liquidity floors screener lists and
+// signal detectors apply by default
+func newScreenerDefaults(cfg *config.Config) models.ScreenerFilter {
+	return models.ScreenerFilter{
+		Types:           cfg.ScreenerTypes,
+		MinPrice:        cfg.ScreenerMinPrice,
+		MinVolume:       cfg.ScreenerMinVolume,
+		MinDollarVolume: cfg.ScreenerMinDollarVolume,
+	}
+}
+
+// newStrengthUniverse returns the filter on tickers ranked by strength,
+// unfiltered unless STRENGTH_* is set so any ticker can be looked up
+func newStrengthUniverse(cfg *config.Config) models.ScreenerFilter {
+	return models.ScreenerFilter{
+		Types:           cfg.StrengthTypes,
+		MinPrice:        cfg.StrengthMinPrice,
+		MinVolume:       cfg.StrengthMinVolume,
+		MinDollarVolume: cfg.StrengthMinDollarVolume,
+	}
+}
+
+// newStream returns the live minute bar source when POLYGON_STREAM is set
+func newStream(cfg *config.Config, logger *slog.Logger) (provider.Streamer, error) {
+	if !cfg.PolygonStream {
+		return nil, nil
+	}
+	if cfg.DataProvider != "polygon" {
+		return nil, fmt.Errorf("POLYGON_STREAM requires DATA_PROVIDER=polygon, got %q", cfg.DataProvider)
+	}
+	if len(cfg.StreamSymbols) == 0 {
+		return nil, errors.New("POLYGON_STREAM requires STREAM_SYMBOLS")
+	}
+
+	logger.Info("polygon streaming enabled", "url", cfg.PolygonStreamURL, "symbols", len(cfg.StreamSymbols))
+	return polygon.NewStream(cfg.PolygonAPIKey, polygon.StreamOptions{
+		URL:    cfg.PolygonStreamURL,
+		Logger: logger,
+	}), nil
+}
+
+// newOptionsFlow returns the options flow poller when UW_API_KEY is set
+func newOptionsFlow(cfg *config.Config, dataStore store.Store, publisher events.Publisher, logger *slog.Logger) *optionsflow.Ingestor {
+	if cfg.UWAPIKey == "" {
+		return nil
+	}
+
+	rules := optionsflow.DefaultRules()
+	rules.MinPremium = cfg.OptionsFlowMinPremium
+	rules.MinVolumeOIRatio = cfg.OptionsFlowMinVolumeOI
+	rules.MaxDTE = cfg.OptionsFlowMaxDTE
+
+	client := unusualwhales.NewClient(cfg.UWAPIKey, unusualwhales.Options{
+		BaseURL:           cfg.UWBaseURL,
+		RequestsPerMinute: cfg.UWRequestsPerMinute,
+		MaxRetries:        cfg.UWMaxRetries,
+	})
+	return optionsflow.NewIngestor(client, dataStore, signals.NewWriter(dataStore, publisher, logger), optionsflow.Options{
+		Rules:  rules,
+		Logger: logger,
+	})
+}
+
+// newProvider returns the market data source selected by DATA_PROVIDER
+func newProvider(cfg *config.Config, logger *slog.Logger) (provider.MarketData, error) {
+	switch cfg.DataProvider {
+	case "polygon":
+		switch cfg.PolygonMode {
+		case polygon.ModeLive:
+		case polygon.ModeRecord, polygon.ModeReplay:
+			logger.Info("polygon fixture mode enabled", "mode", cfg.PolygonMode, "dir", cfg.PolygonFixturesDir)
+		default:
+			return nil, fmt.Errorf("unknown POLYGON_MODE %q (expected live, record or replay)", cfg.PolygonMode)
+		}
+		return polygon.NewClient(cfg.PolygonAPIKey, polygon.Options{
+			RequestsPerMinute: cfg.PolygonRequestsPerMinute,
+			MaxRetries:        cfg.PolygonMaxRetries,
+			Mode:              cfg.PolygonMode,
+			FixturesDir:       cfg.PolygonFixturesDir,
+		}), nil
+	case "csv":
+		logger.Info("using CSV data provider", "dir", cfg.DataDir)
+		return csvdir.New(cfg.DataDir)
+	default:
+		return nil, fmt.Errorf("unknown DATA_PROVIDER %q (expected polygon or csv)", cfg.DataProvider)
+	}
+}
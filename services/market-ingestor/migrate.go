@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/config"
+	"github.com/anubiskhan/market-dash/services/market-ingestor/internal/store"
+)
+
+// runMigrate implements `market-ingestor migrate up|down [-steps N]|status`
+func runMigrate(cfg *config.Config, logger *slog.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected migrate up, down or status")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	steps := fs.Int("steps", 1, "migrations to revert (down only)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *steps < 1 {
+		return errors.New("-steps must be at least 1")
+	}
+
+	if cfg.DatabaseURL == "" {
+		return errors.New("migrate requires DATABASE_URL")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	m, err := store.NewMigrator(ctx, cfg.DatabaseURL, logger)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch action {
+	case "up":
+		n, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "applied %d migrations\n", n)
+	case "down":
+		n, err := m.Down(ctx, *steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "reverted %d migrations\n", n)
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied {
+				state = "applied " + st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if st.Unknown {
+				state += " (not in this binary)"
+			}
+			fmt.Printf("%03d  %-28s  %s\n", st.Version, st.Name, state)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q (expected up, down or status)", action)
+	}
+	return nil
+}
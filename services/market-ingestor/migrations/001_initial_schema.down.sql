@@ -0,0 +1,5 @@
+-- Revert 001_initial_schema.sql
+
+DROP TABLE IF EXISTS market_indices;
+DROP TABLE IF EXISTS daily_bars;
+DROP FUNCTION IF EXISTS update_updated_at_column();
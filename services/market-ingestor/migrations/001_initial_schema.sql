@@ -90,12 +90,12 @@ END;
 $$ LANGUAGE plpgsql;
 
 -- Triggers for updated_at
-CREATE TRIGGER update_daily_bars_updated_at
+CREATE OR REPLACE TRIGGER update_daily_bars_updated_at
     BEFORE UPDATE ON daily_bars
     FOR EACH ROW
     EXECUTE FUNCTION update_updated_at_column();
 
-CREATE TRIGGER update_market_indices_updated_at
+CREATE OR REPLACE TRIGGER update_market_indices_updated_at
     BEFORE UPDATE ON market_indices
     FOR EACH ROW
     EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+-- Revert 002_change_basis.sql
+
+ALTER TABLE daily_bars DROP COLUMN IF EXISTS change_basis;
+ALTER TABLE daily_bars DROP COLUMN IF EXISTS prev_close;
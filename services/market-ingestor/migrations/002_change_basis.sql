@@ -0,0 +1,9 @@
+-- Migration: 002_change_basis.sql
+-- Description: Record the previous close and the basis used to compute daily change
+-- Created: 2026-10-16
+
+ALTER TABLE daily_bars ADD COLUMN IF NOT EXISTS prev_close NUMERIC(12, 4) CHECK (prev_close >= 0);
+ALTER TABLE daily_bars ADD COLUMN IF NOT EXISTS change_basis VARCHAR(16);
+
+COMMENT ON COLUMN daily_bars.prev_close IS 'Prior trading day close used as the change reference';
+COMMENT ON COLUMN daily_bars.change_basis IS 'Reference used for change: prev_close, open, or none';
@@ -0,0 +1,3 @@
+-- Revert 003_tickers.sql
+
+DROP TABLE IF EXISTS tickers;
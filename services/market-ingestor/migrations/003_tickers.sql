@@ -0,0 +1,28 @@
+-- Migration: 003_tickers.sql
+-- Description: Ticker reference data (company names, security types)
+-- Created: 2026-10-16
+
+-- =====================================================
+-- Table: tickers
+-- Description: Reference data for listed securities, refreshed weekly from Polygon
+-- =====================================================
+CREATE TABLE IF NOT EXISTS tickers (
+    symbol VARCHAR(16) PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    type VARCHAR(16),
+    primary_exchange VARCHAR(16),
+    cik VARCHAR(16),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    currency VARCHAR(8),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for filtering by security type (CS, ETF, ADRC, ...)
+CREATE INDEX IF NOT EXISTS idx_tickers_type
+    ON tickers (type)
+    WHERE active;
+
+COMMENT ON TABLE tickers IS 'Ticker reference data from Polygon (name, type, exchange)';
+COMMENT ON COLUMN tickers.type IS 'Polygon security type code (CS, ETF, ADRC, WARRANT, ...)';
+COMMENT ON COLUMN tickers.active IS 'False once a ticker drops out of the active reference list';
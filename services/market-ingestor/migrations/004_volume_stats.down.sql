@@ -0,0 +1,6 @@
+-- Revert 004_volume_stats.sql
+
+DROP INDEX IF EXISTS idx_daily_bars_volume_ratio;
+ALTER TABLE daily_bars DROP COLUMN IF EXISTS volume_ratio;
+ALTER TABLE daily_bars DROP COLUMN IF EXISTS avg_volume_50;
+ALTER TABLE daily_bars DROP COLUMN IF EXISTS avg_volume_20;
@@ -0,0 +1,16 @@
+-- Migration: 004_volume_stats.sql
+-- Description: Average volume and relative volume (RVOL) per daily bar
+-- Created: 2026-10-16
+
+ALTER TABLE daily_bars ADD COLUMN IF NOT EXISTS avg_volume_20 BIGINT CHECK (avg_volume_20 >= 0);
+ALTER TABLE daily_bars ADD COLUMN IF NOT EXISTS avg_volume_50 BIGINT CHECK (avg_volume_50 >= 0);
+ALTER TABLE daily_bars ADD COLUMN IF NOT EXISTS volume_ratio NUMERIC(10, 4);
+
+-- Index for ranking unusual volume on the latest date
+CREATE INDEX IF NOT EXISTS idx_daily_bars_volume_ratio
+    ON daily_bars (date DESC, volume_ratio DESC NULLS LAST)
+    WHERE volume_ratio IS NOT NULL;
+
+COMMENT ON COLUMN daily_bars.avg_volume_20 IS 'Average volume over the prior 20 sessions';
+COMMENT ON COLUMN daily_bars.avg_volume_50 IS 'Average volume over the prior 50 sessions';
+COMMENT ON COLUMN daily_bars.volume_ratio IS 'Volume relative to avg_volume_20 (RVOL)';
@@ -0,0 +1,4 @@
+-- Revert 005_ticker_sector.sql
+
+DROP INDEX IF EXISTS idx_tickers_sector;
+ALTER TABLE tickers DROP COLUMN IF EXISTS sector;
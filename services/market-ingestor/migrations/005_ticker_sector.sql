@@ -0,0 +1,12 @@
+-- Migration: 005_ticker_sector.sql
+-- Description: Sector classification on ticker reference data
+-- Created: 2026-10-16
+
+ALTER TABLE tickers ADD COLUMN IF NOT EXISTS sector VARCHAR(64);
+
+-- Index for screening by sector
+CREATE INDEX IF NOT EXISTS idx_tickers_sector
+    ON tickers (sector)
+    WHERE active AND sector IS NOT NULL;
+
+COMMENT ON COLUMN tickers.sector IS 'Sector classification; kept when a refresh does not supply one';
@@ -0,0 +1,4 @@
+-- Revert 006_saved_screens.sql
+
+DROP TABLE IF EXISTS screen_results;
+DROP TABLE IF EXISTS saved_screens;
@@ -0,0 +1,43 @@
+-- Migration: 006_saved_screens.sql
+-- Description: Saved custom screens and their daily results
+-- Created: 2026-10-16
+
+-- =====================================================
+-- Table: saved_screens
+-- Description: Named screen expressions evaluated after each EOD ingest
+-- =====================================================
+CREATE TABLE IF NOT EXISTS saved_screens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    filter TEXT NOT NULL DEFAULT '',
+    sort TEXT NOT NULL DEFAULT '',
+    sort_order VARCHAR(4) NOT NULL DEFAULT '',
+    result_limit INTEGER NOT NULL DEFAULT 0 CHECK (result_limit >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT saved_screens_name_unique UNIQUE (name)
+);
+
+-- =====================================================
+-- Table: screen_results
+-- Description: Symbols each saved screen matched per trading day, with the
+-- symbols that entered and exited since its previous evaluation
+-- =====================================================
+CREATE TABLE IF NOT EXISTS screen_results (
+    screen_id UUID NOT NULL REFERENCES saved_screens(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    previous_date DATE,
+    symbols TEXT[] NOT NULL DEFAULT '{}',
+    entered TEXT[] NOT NULL DEFAULT '{}',
+    exited TEXT[] NOT NULL DEFAULT '{}',
+    evaluated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (screen_id, date)
+);
+
+COMMENT ON TABLE saved_screens IS 'Named custom screen expressions';
+COMMENT ON COLUMN saved_screens.sort_order IS 'asc or desc; empty uses the screener default';
+COMMENT ON COLUMN saved_screens.result_limit IS 'Maximum matches kept; 0 uses the screener default';
+COMMENT ON TABLE screen_results IS 'Daily saved screen matches with entered/exited symbols';
@@ -0,0 +1,3 @@
+-- Revert 007_intraday_bars.sql
+
+DROP TABLE IF EXISTS intraday_bars;
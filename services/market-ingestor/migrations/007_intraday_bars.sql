@@ -0,0 +1,34 @@
+-- Migration: 007_intraday_bars.sql
+-- Description: Minute bars for intraday charts, streamed live or fetched after the close
+-- Created: 2026-10-16
+
+-- =====================================================
+-- Table: intraday_bars
+-- Description: One row per symbol per minute; coarser intervals (5m, 15m,
+-- 1h) are rolled up at query time
+-- =====================================================
+CREATE TABLE IF NOT EXISTS intraday_bars (
+    symbol VARCHAR(10) NOT NULL,
+    ts TIMESTAMPTZ NOT NULL,  -- bar start
+    open NUMERIC(12, 4) NOT NULL CHECK (open >= 0),
+    high NUMERIC(12, 4) NOT NULL CHECK (high >= 0),
+    low NUMERIC(12, 4) NOT NULL CHECK (low >= 0),
+    close NUMERIC(12, 4) NOT NULL CHECK (close >= 0),
+    volume BIGINT NOT NULL CHECK (volume >= 0),
+    vwap NUMERIC(12, 4),
+    transactions INTEGER,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    -- The primary key includes ts, as a hypertable's unique keys must
+    PRIMARY KEY (symbol, ts)
+);
+
+-- Convert to a TimescaleDB hypertable when the extension is installed;
+-- plain PostgreSQL keeps a regular table
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb') THEN
+        PERFORM create_hypertable('intraday_bars', 'ts', chunk_time_interval => INTERVAL '1 day', if_not_exists => TRUE);
+    END IF;
+END
+$$;
@@ -0,0 +1,3 @@
+-- Revert 008_corporate_actions.sql
+
+DROP TABLE IF EXISTS corporate_actions;
@@ -0,0 +1,28 @@
+-- Migration: 008_corporate_actions.sql
+-- Description: Splits and cash dividends used to adjust daily bar history
+-- Created: 2026-10-16
+
+-- =====================================================
+-- Table: corporate_actions
+-- Description: One row per split or dividend. price_factor multiplies
+-- prices before ex_date; it is recomputed whenever a symbol's actions are
+-- ingested and stays NULL until the close before ex_date is stored.
+-- =====================================================
+CREATE TABLE IF NOT EXISTS corporate_actions (
+    id VARCHAR(128) PRIMARY KEY,  -- Polygon reference ID
+    symbol VARCHAR(10) NOT NULL,
+    action_type VARCHAR(16) NOT NULL CHECK (action_type IN ('split', 'dividend')),
+    ex_date DATE NOT NULL,
+    split_from NUMERIC(16, 6),
+    split_to NUMERIC(16, 6),
+    cash_amount NUMERIC(16, 6),
+    currency VARCHAR(8),
+    pay_date DATE,
+    dividend_type VARCHAR(8),
+    price_factor NUMERIC(20, 12),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_corporate_actions_symbol
+    ON corporate_actions (symbol, ex_date);
@@ -0,0 +1,25 @@
+-- Revert 009_reconcile_infra_schema.sql. update_updated_at() is kept, as
+-- triggers created from infra/database/init may use it.
+
+DROP INDEX IF EXISTS idx_daily_bars_date;
+
+DROP TRIGGER IF EXISTS tr_daily_bars_updated_at ON daily_bars;
+DROP TRIGGER IF EXISTS tr_market_indices_updated_at ON market_indices;
+
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER update_daily_bars_updated_at
+    BEFORE UPDATE ON daily_bars
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE OR REPLACE TRIGGER update_market_indices_updated_at
+    BEFORE UPDATE ON market_indices
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
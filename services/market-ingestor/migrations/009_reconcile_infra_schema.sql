@@ -0,0 +1,34 @@
+-- Migration: 009_reconcile_infra_schema.sql
+-- Description: Converge with infra/database/init/001_schema.sql: one
+-- update_updated_at() trigger function and the daily_bars date index
+-- Created: 2026-10-16
+
+-- =====================================================
+-- updated_at triggers: infra names the function update_updated_at() and
+-- its triggers tr_<table>_updated_at
+-- =====================================================
+CREATE OR REPLACE FUNCTION update_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_daily_bars_updated_at ON daily_bars;
+DROP TRIGGER IF EXISTS update_market_indices_updated_at ON market_indices;
+DROP FUNCTION IF EXISTS update_updated_at_column();
+
+CREATE OR REPLACE TRIGGER tr_daily_bars_updated_at
+    BEFORE UPDATE ON daily_bars
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+CREATE OR REPLACE TRIGGER tr_market_indices_updated_at
+    BEFORE UPDATE ON market_indices
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+-- Index for queries over a single date
+CREATE INDEX IF NOT EXISTS idx_daily_bars_date
+    ON daily_bars (date DESC);
@@ -0,0 +1,4 @@
+-- Revert 010_sector_analysis.sql
+
+DROP TABLE IF EXISTS sector_rotation;
+DROP TABLE IF EXISTS sector_data;
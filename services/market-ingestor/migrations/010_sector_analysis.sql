@@ -0,0 +1,46 @@
+-- Migration: 010_sector_analysis.sql
+-- Description: Sector ETF relative strength and daily rotation signals
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS sector_data (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(10) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    date DATE NOT NULL,
+    open NUMERIC(12, 4),
+    high NUMERIC(12, 4),
+    low NUMERIC(12, 4),
+    close NUMERIC(12, 4) NOT NULL,
+    volume BIGINT,
+    change_percent NUMERIC(8, 4),
+    relative_strength NUMERIC(8, 4),  -- vs SPY
+    volume_ratio NUMERIC(8, 4),       -- vs 20-day avg
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT sector_data_symbol_date_unique UNIQUE (symbol, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sector_data_date
+    ON sector_data (date DESC);
+CREATE INDEX IF NOT EXISTS idx_sector_data_rs
+    ON sector_data (date DESC, relative_strength DESC NULLS LAST);
+
+CREATE OR REPLACE TRIGGER tr_sector_data_updated_at
+    BEFORE UPDATE ON sector_data
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+CREATE TABLE IF NOT EXISTS sector_rotation (
+    id BIGSERIAL PRIMARY KEY,
+    date DATE NOT NULL UNIQUE,
+    signal VARCHAR(20) NOT NULL,  -- RISK_ON, RISK_OFF, NEUTRAL
+    leading_sectors JSONB,        -- Array of leading sector symbols
+    lagging_sectors JSONB,        -- Array of lagging sector symbols
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE sector_data IS 'SPDR sector ETF data with relative strength metrics';
+COMMENT ON COLUMN sector_data.relative_strength IS 'Percent change minus SPY percent change, in percentage points';
+COMMENT ON TABLE sector_rotation IS 'Daily RISK_ON/RISK_OFF/NEUTRAL signal from leading and lagging sectors';
@@ -0,0 +1,3 @@
+-- Revert 011_strength_scores.sql
+
+DROP TABLE IF EXISTS strength_scores;
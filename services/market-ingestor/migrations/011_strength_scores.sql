@@ -0,0 +1,33 @@
+-- Migration: 011_strength_scores.sql
+-- Description: Daily composite strength scores ranking the screener universe
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS strength_scores (
+    id BIGSERIAL PRIMARY KEY,
+    ticker VARCHAR(10) NOT NULL,
+    date DATE NOT NULL,
+    composite_score NUMERIC(5, 2) NOT NULL,  -- 0-100
+    momentum_1d NUMERIC(8, 4),
+    momentum_5d NUMERIC(8, 4),
+    momentum_20d NUMERIC(8, 4),
+    rs_vs_spy NUMERIC(8, 4),
+    volume_trend NUMERIC(8, 4),
+    ma_alignment NUMERIC(5, 2),
+    high_low_position NUMERIC(5, 2),  -- 0-100, position within 52wk range
+    rank INTEGER,
+    total_ranked INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT strength_scores_ticker_date_unique UNIQUE (ticker, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_strength_scores_date_rank
+    ON strength_scores (date DESC, rank ASC);
+CREATE INDEX IF NOT EXISTS idx_strength_scores_ticker
+    ON strength_scores (ticker, date DESC);
+CREATE INDEX IF NOT EXISTS idx_strength_scores_composite
+    ON strength_scores (date DESC, composite_score DESC);
+
+COMMENT ON TABLE strength_scores IS 'Daily composite strength scores for ticker ranking';
+COMMENT ON COLUMN strength_scores.rs_vs_spy IS '20-session percent change minus SPY''s, in percentage points';
+COMMENT ON COLUMN strength_scores.volume_trend IS 'Average volume over 5 sessions relative to 20';
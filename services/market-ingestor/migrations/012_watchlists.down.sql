@@ -0,0 +1,4 @@
+-- Revert 012_watchlists.sql
+
+DROP TABLE IF EXISTS watchlist_items;
+DROP TABLE IF EXISTS watchlists;
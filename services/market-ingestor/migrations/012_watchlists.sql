@@ -0,0 +1,44 @@
+-- Migration: 012_watchlists.sql
+-- Description: User watchlists and the tickers on them
+-- Created: 2026-10-16
+
+-- =====================================================
+-- Table: watchlists
+-- Description: Named ticker lists, such as current holdings
+-- =====================================================
+CREATE TABLE IF NOT EXISTS watchlists (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- =====================================================
+-- Table: watchlist_items
+-- Description: Tickers on each watchlist, with optional notes
+-- =====================================================
+CREATE TABLE IF NOT EXISTS watchlist_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    watchlist_id UUID NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    ticker VARCHAR(10) NOT NULL,
+    notes TEXT,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT watchlist_items_unique UNIQUE (watchlist_id, ticker)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_items_ticker
+    ON watchlist_items (ticker);
+CREATE INDEX IF NOT EXISTS idx_watchlist_items_watchlist
+    ON watchlist_items (watchlist_id);
+
+CREATE OR REPLACE TRIGGER tr_watchlists_updated_at
+    BEFORE UPDATE ON watchlists
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+-- Default watchlist. Names are not unique, so only seed an empty table.
+INSERT INTO watchlists (name, description)
+SELECT 'My Holdings', 'Track your current stock holdings'
+WHERE NOT EXISTS (SELECT 1 FROM watchlists);
@@ -0,0 +1,3 @@
+-- Revert 013_signals.sql
+
+DROP TABLE IF EXISTS signals;
@@ -0,0 +1,51 @@
+-- Migration: 013_signals.sql
+-- Description: Unified signals table every tool writes to, with one signal
+-- per source, ticker and session
+-- Created: 2026-10-16
+
+-- =====================================================
+-- Table: signals
+-- Description: Directional calls from every tool in a common format
+-- =====================================================
+CREATE TABLE IF NOT EXISTS signals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source VARCHAR(50) NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    session_date DATE NOT NULL,
+    ticker VARCHAR(10),
+    direction VARCHAR(20),
+    strength INTEGER CHECK (strength BETWEEN 1 AND 4),
+    confidence NUMERIC(5, 4),
+    tags JSONB DEFAULT '[]',
+    raw_data JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Tables created from infra/database/init predate session_date
+ALTER TABLE signals ADD COLUMN IF NOT EXISTS session_date DATE;
+UPDATE signals SET session_date = ("timestamp" AT TIME ZONE 'America/New_York')::date
+WHERE session_date IS NULL;
+ALTER TABLE signals ALTER COLUMN session_date SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_signals_source_time
+    ON signals (source, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_signals_ticker
+    ON signals (ticker, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_signals_timestamp
+    ON signals (timestamp DESC);
+
+-- Keep the strongest of any duplicates before enforcing one signal per
+-- source, ticker and session
+DELETE FROM signals a
+USING signals b
+WHERE a.source = b.source
+  AND COALESCE(a.ticker, '') = COALESCE(b.ticker, '')
+  AND a.session_date = b.session_date
+  AND (COALESCE(a.strength, 0), a.created_at, a.id) < (COALESCE(b.strength, 0), b.created_at, b.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_signals_dedup
+    ON signals (source, COALESCE(ticker, ''), session_date);
+
+COMMENT ON TABLE signals IS 'Unified signals from every tool, one per source, ticker and session';
+COMMENT ON COLUMN signals.source IS 'Tool or detector: gap, breakout, unusual_volume, new_high, options_flow, ...';
+COMMENT ON COLUMN signals.session_date IS 'New York trading date of timestamp';
@@ -0,0 +1,3 @@
+-- Revert 014_options_flow.sql
+
+DROP TABLE IF EXISTS options_flow;
@@ -0,0 +1,43 @@
+-- Migration: 014_options_flow.sql
+-- Description: Options flow kept by the flow rules, stored once per
+-- provider alert
+-- Created: 2026-10-17
+
+-- =====================================================
+-- Table: options_flow
+-- Description: Large options orders from Unusual Whales flow alerts
+-- =====================================================
+CREATE TABLE IF NOT EXISTS options_flow (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    alert_id VARCHAR(64),
+    timestamp TIMESTAMPTZ NOT NULL,
+    ticker VARCHAR(10) NOT NULL,
+    expiration DATE NOT NULL,
+    strike NUMERIC(12, 2) NOT NULL,
+    option_type VARCHAR(4) NOT NULL,
+    side VARCHAR(4),
+    trade_type VARCHAR(20),
+    premium NUMERIC(14, 2) NOT NULL,
+    volume INTEGER NOT NULL,
+    open_interest INTEGER,
+    dte INTEGER,
+    direction VARCHAR(10),
+    raw_data JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Tables created from infra/database/init predate alert_id
+ALTER TABLE options_flow ADD COLUMN IF NOT EXISTS alert_id VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_options_flow_timestamp
+    ON options_flow (timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_options_flow_ticker
+    ON options_flow (ticker, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_options_flow_premium
+    ON options_flow (timestamp DESC, premium DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_options_flow_alert
+    ON options_flow (alert_id);
+
+COMMENT ON TABLE options_flow IS 'Options orders passing the flow rules: premium, volume vs open interest, DTE and time of day';
+COMMENT ON COLUMN options_flow.alert_id IS 'Unusual Whales alert ID, so repeated polls store an alert once';
+COMMENT ON COLUMN options_flow.direction IS 'BULLISH (calls bought, puts sold), BEARISH (calls sold, puts bought) or NULL at mid';
@@ -0,0 +1,10 @@
+// Package migrations embeds the market ingestor's versioned schema changes.
+// NNN_name.sql applies version NNN and NNN_name.down.sql, when present,
+// reverts it. Every up migration is safe to run against a database created
+// from infra/database/init, which already holds the same schema.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS